@@ -24,6 +24,7 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"time"
@@ -38,6 +39,7 @@ import (
 	_ "github.com/v3io/frames/backends/stream"
 	_ "github.com/v3io/frames/backends/tsdb"
 	"github.com/v3io/frames/backends/utils"
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
 	v3iohttp "github.com/v3io/v3io-go/pkg/dataplane/http"
 )
 
@@ -52,10 +54,23 @@ type API struct {
 	backends      map[string]frames.DataBackend
 	config        *frames.Config
 	historyServer *utils.HistoryServer
+	v3ioContext   v3io.Context // shared by all backends if set
+}
+
+// Option is an API option
+type Option func(*API)
+
+// WithV3ioContext makes all backends use v3ioContext instead of creating
+// their own V3IO HTTP context (e.g. an in-memory v3ioutils/fake.Context in
+// tests)
+func WithV3ioContext(v3ioContext v3io.Context) Option {
+	return func(api *API) {
+		api.v3ioContext = v3ioContext
+	}
 }
 
 // New returns a new API layer struct
-func New(logger logger.Logger, config *frames.Config, historyServer *utils.HistoryServer) (*API, error) {
+func New(logger logger.Logger, config *frames.Config, historyServer *utils.HistoryServer, options ...Option) (*API, error) {
 	if logger == nil {
 		var err error
 		logger, err = frames.NewLogger(config.Log.Level)
@@ -70,6 +85,10 @@ func New(logger logger.Logger, config *frames.Config, historyServer *utils.Histo
 		historyServer: historyServer,
 	}
 
+	for _, option := range options {
+		option(api)
+	}
+
 	if err := api.createBackends(config); err != nil {
 		msg := "can't create backends"
 		api.logger.ErrorWith(msg, "error", err, "config", config)
@@ -115,18 +134,54 @@ func (api *API) Read(request *frames.ReadRequest, out chan frames.Frame) error {
 	return nil
 }
 
+// WriteAckFunc is called with the index of a written frame (request
+// ImmidiateData is frame 0), its number of rows and the rows that failed to
+// write (see frames.WriteRequest.ContinueOnError)
+type WriteAckFunc func(frame int, rows int, failedRows []frames.RowError) error
+
+// WriteResult is the result of a write
+type WriteResult struct {
+	Frames     int
+	Rows       int
+	FailedRows []frames.RowError // see frames.WriteRequest.ContinueOnError
+}
+
 // Write write data to backend, returns num_frames, num_rows, error
 func (api *API) Write(request *frames.WriteRequest, in chan frames.Frame) (int, int, error) {
+	next := func() (frames.Frame, error) {
+		frame, ok := <-in
+		if !ok {
+			return nil, io.EOF
+		}
+		return frame, nil
+	}
+
+	result, err := api.WriteStream(request, next, nil)
+	if result == nil {
+		return -1, -1, err
+	}
+
+	return result.Frames, result.Rows, err
+}
+
+// WriteStream writes frames returned by next until it returns io.EOF. If ack
+// is not nil, it's called for every frame once the backend applied it. Frames
+// of backends that can't flush in the middle of a write (see
+// frames.FrameFlusher) are acknowledged when the write completes. Failed rows
+// are passed to ack with the last frame of the flush that reported them.
+// Errors from next and ack are returned as is. The result (if not nil) has
+// the frames written so far also on error.
+func (api *API) WriteStream(request *frames.WriteRequest, next func() (frames.Frame, error), ack WriteAckFunc) (*WriteResult, error) {
 	if request.Backend == "" || request.Table == "" {
 		api.logger.ErrorWith(missingMsg, "request", request)
-		return -1, -1, fmt.Errorf(missingMsg)
+		return nil, fmt.Errorf(missingMsg)
 	}
 
 	api.logger.DebugWith("write request", "request", request)
 	backend, ok := api.backends[request.Backend]
 	if !ok {
 		api.logger.ErrorWith("unknown backend", "name", request.Backend)
-		return -1, -1, fmt.Errorf("unknown backend - %s", request.Backend)
+		return nil, fmt.Errorf("unknown backend - %s", request.Backend)
 	}
 
 	ingestStartTime := time.Now()
@@ -134,15 +189,79 @@ func (api *API) Write(request *frames.WriteRequest, in chan frames.Frame) (int,
 	if err != nil {
 		msg := "backend Write failed"
 		api.logger.ErrorWith(msg, "error", err)
-		return -1, -1, errors.Wrap(err, msg)
+		return nil, errors.Wrap(err, msg)
 	}
 	defer appender.Close()
-	nFrames, nRows := 0, 0
+
+	// TODO: Specify timeout in request?
+	timeout := time.Duration(api.config.DefaultTimeout) * time.Second
+	flusher, canFlush := appender.(frames.FrameFlusher)
+	collector, collectsRowErrors := appender.(frames.RowErrorsCollector)
+	result := &WriteResult{}
+	var unacked []int // number of rows in frames not acknowledged yet
+
+	takeRowErrors := func() []frames.RowError {
+		if !collectsRowErrors {
+			return nil
+		}
+
+		rowErrors := collector.TakeRowErrors()
+		result.FailedRows = append(result.FailedRows, rowErrors...)
+		return rowErrors
+	}
+
+	ackFrames := func() error {
+		rowErrors := takeRowErrors()
+		first := result.Frames - len(unacked)
+		for i, rows := range unacked {
+			var failedRows []frames.RowError
+			if i == len(unacked)-1 {
+				failedRows = rowErrors
+			}
+
+			if err := ack(first+i, rows, failedRows); err != nil {
+				return err
+			}
+		}
+		unacked = unacked[:0]
+		return nil
+	}
+
+	added := func(rows int) error {
+		result.Frames++
+		result.Rows += rows
+		if ack == nil {
+			return nil
+		}
+
+		unacked = append(unacked, rows)
+		if !canFlush {
+			return nil
+		}
+
+		if err := flusher.Flush(timeout); err != nil {
+			msg := "can't flush"
+			api.logger.ErrorWith(msg, "error", err)
+			return errors.Wrap(err, msg)
+		}
+		return ackFrames()
+	}
+
 	if request.ImmidiateData != nil {
-		nFrames, nRows = 1, request.ImmidiateData.Len()
+		if err := added(request.ImmidiateData.Len()); err != nil {
+			return result, err
+		}
 	}
 
-	for frame := range in {
+	for {
+		frame, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
 		api.logger.DebugWith("frame to write", "size", frame.Len())
 		if err := appender.Add(frame); err != nil {
 			msg := "can't add frame"
@@ -150,25 +269,33 @@ func (api *API) Write(request *frames.WriteRequest, in chan frames.Frame) (int,
 			if strings.Contains(err.Error(), "Failed POST with status 401") {
 				err = errors.New("unauthorized update (401), may be caused by wrong password or credentials")
 			}
-			return nFrames, nRows, errors.Wrap(err, msg)
+			return result, errors.Wrap(err, msg)
 		}
 
-		nFrames++
-		nRows += frame.Len()
-		api.logger.DebugWith("write", "numFrames", nFrames, "numRows", nRows)
+		if err := added(frame.Len()); err != nil {
+			return result, err
+		}
+		api.logger.DebugWith("write", "numFrames", result.Frames, "numRows", result.Rows)
 	}
 
 	api.logger.Debug("write done")
 
-	// TODO: Specify timeout in request?
-	if nRows > 0 {
-		if err := appender.WaitForComplete(time.Duration(api.config.DefaultTimeout) * time.Second); err != nil {
+	if result.Rows > 0 {
+		if err := appender.WaitForComplete(timeout); err != nil {
 			msg := "can't wait for completion"
 			api.logger.ErrorWith(msg, "error", err)
-			return nFrames, nRows, errors.Wrap(err, msg)
+			return result, errors.Wrap(err, msg)
+		}
+	} else {
+		api.logger.DebugWith("write request with zero rows", "frames", result.Frames, "request", request)
+	}
+
+	if ack != nil {
+		if err := ackFrames(); err != nil {
+			return result, err
 		}
 	} else {
-		api.logger.DebugWith("write request with zero rows", "frames", nFrames, "request", request)
+		takeRowErrors()
 	}
 
 	ingestDuration := time.Since(ingestStartTime)
@@ -176,7 +303,45 @@ func (api *API) Write(request *frames.WriteRequest, in chan frames.Frame) (int,
 		api.historyServer.AddWriteLog(request, ingestDuration, ingestStartTime)
 	}
 
-	return nFrames, nRows, nil
+	return result, nil
+}
+
+// BatchWrite writes frames to several tables of a backend that supports batch
+// writes (see frames.BatchWriter), either all the tables are written or none
+// is. The result has the outcome of every table, also when the batch is
+// aborted (with an error).
+func (api *API) BatchWrite(request *frames.BatchWriteRequest) (*frames.BatchWriteResult, error) {
+	if request.Backend == "" || len(request.Tables) == 0 {
+		api.logger.ErrorWith(missingMsg, "backend", request.Backend, "tables", len(request.Tables))
+		return nil, fmt.Errorf(missingMsg)
+	}
+
+	for _, table := range request.Tables {
+		if table.Table == "" || table.Frame == nil {
+			api.logger.ErrorWith(missingMsg, "backend", request.Backend, "table", table.Table)
+			return nil, fmt.Errorf(missingMsg)
+		}
+	}
+
+	backend, ok := api.backends[request.Backend]
+	if !ok {
+		api.logger.ErrorWith("unknown backend", "name", request.Backend)
+		return nil, fmt.Errorf("unknown backend - %s", request.Backend)
+	}
+
+	batchWriter, ok := backend.(frames.BatchWriter)
+	if !ok {
+		return nil, fmt.Errorf("backend %s doesn't support batch writes", request.Backend)
+	}
+
+	result, err := batchWriter.BatchWrite(request)
+	if err != nil {
+		api.logger.ErrorWith("batch write failed", "error", err, "backend", request.Backend)
+		return result, err
+	}
+
+	api.logger.DebugWith("batch write done", "backend", request.Backend, "tables", len(request.Tables))
+	return result, nil
 }
 
 // Create will create a new table
@@ -276,25 +441,9 @@ func (api *API) createBackends(config *frames.Config) error {
 	api.backends = make(map[string]frames.DataBackend)
 
 	for _, backendConfig := range config.Backends {
-		newClient := v3iohttp.NewClient(&v3iohttp.NewClientInput{DialTimeout: time.Duration(backendConfig.DialTimeoutSeconds) * time.Second, MaxConnsPerHost: math.MaxInt64})
-
-		api.logger.InfoWith("Creating v3io context for backend",
-			"backend", backendConfig.Name,
-			"workers", backendConfig.V3ioGoWorkers,
-			"requestChanLength", backendConfig.V3ioGoRequestChanLength,
-			"maxConns", backendConfig.MaxConnections)
-
-		newContextInput := &v3iohttp.NewContextInput{
-			HTTPClient:     newClient,
-			NumWorkers:     backendConfig.V3ioGoWorkers,
-			RequestChanLen: backendConfig.V3ioGoRequestChanLength,
-			MaxConns:       backendConfig.MaxConnections,
-		}
-		// create a context for the backend
-		v3ioContext, err := v3iohttp.NewContext(api.logger, newContextInput)
-
+		v3ioContext, err := api.newV3ioContext(backendConfig)
 		if err != nil {
-			return errors.Wrap(err, "Failed to create v3io context for backend")
+			return err
 		}
 
 		factory := backends.GetFactory(backendConfig.Type)
@@ -313,3 +462,32 @@ func (api *API) createBackends(config *frames.Config) error {
 
 	return nil
 }
+
+// newV3ioContext returns the V3IO context of a backend
+func (api *API) newV3ioContext(backendConfig *frames.BackendConfig) (v3io.Context, error) {
+	if api.v3ioContext != nil {
+		return api.v3ioContext, nil
+	}
+
+	newClient := v3iohttp.NewClient(&v3iohttp.NewClientInput{DialTimeout: time.Duration(backendConfig.DialTimeoutSeconds) * time.Second, MaxConnsPerHost: math.MaxInt64})
+
+	api.logger.InfoWith("Creating v3io context for backend",
+		"backend", backendConfig.Name,
+		"workers", backendConfig.V3ioGoWorkers,
+		"requestChanLength", backendConfig.V3ioGoRequestChanLength,
+		"maxConns", backendConfig.MaxConnections)
+
+	newContextInput := &v3iohttp.NewContextInput{
+		HTTPClient:     newClient,
+		NumWorkers:     backendConfig.V3ioGoWorkers,
+		RequestChanLen: backendConfig.V3ioGoRequestChanLength,
+		MaxConns:       backendConfig.MaxConnections,
+	}
+	// create a context for the backend
+	v3ioContext, err := v3iohttp.NewContext(api.logger, newContextInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create v3io context for backend")
+	}
+
+	return v3ioContext, nil
+}
@@ -479,6 +479,12 @@ func (ca *csvAppender) WaitForComplete(timeout time.Duration) error {
 		ca.logger.Error(err)
 		return err
 	}
+
+	return ca.Flush(timeout)
+}
+
+// Flush writes the records added so far to the file
+func (ca *csvAppender) Flush(timeout time.Duration) error {
 	ca.csvWriter.Flush()
 	if err := ca.csvWriter.Error(); err != nil {
 		ca.logger.ErrorWith("CSV flush", "error", err)
@@ -76,8 +76,7 @@ func (b *Backend) Delete(request *frames.DeleteRequest) error {
 		return err
 	}
 
-	return v3ioutils.DeleteTable(b.logger, container, path, request.Proto.Filter, b.numWorkers, b.numWorkers*b.updateWorkersPerVN, request.Proto.IfMissing == frames.IgnoreError)
-	// TODO: delete the table directory entry if filter == ""
+	return b.deleteItems(container, path, request.Proto.Filter, false, request.Proto.IfMissing == frames.IgnoreError, nil)
 }
 
 // Exec executes a command
@@ -85,9 +84,21 @@ func (b *Backend) Exec(request *frames.ExecRequest) (frames.Frame, error) {
 	cmd := strings.TrimSpace(strings.ToLower(request.Proto.Command))
 	switch cmd {
 	case "infer", "infer_schema":
-		return nil, b.inferSchema(request)
+		return b.inferSchema(request)
 	case "update":
 		return nil, b.updateItem(request)
+	case "batch_update", "update_batch":
+		return b.batchUpdate(request)
+	case "segments", "suggest_segments":
+		return b.suggestSegments(request)
+	case "delete":
+		return b.deleteKeys(request)
+	case "create_index", "drop_index", "rebuild_index":
+		return b.indexCommand(request, cmd)
+	case "purge_expired":
+		return nil, b.purgeExpired(request)
+	case "schema_mode":
+		return nil, b.setSchemaMode(request)
 	}
 	return nil, fmt.Errorf("NoSQL backend doesn't support execute command '%s'", cmd)
 }
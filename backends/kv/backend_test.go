@@ -0,0 +1,75 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils/fake"
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+)
+
+// testBackend is a KV backend over an in-memory V3IO, with helpers to access
+// the items of its container directly
+type testBackend struct {
+	*Backend
+	t         testing.TB
+	container *fake.Container
+}
+
+// newTestBackend returns a KV backend over a new in-memory V3IO, tables are in
+// the "bigdata" container
+func newTestBackend(t testing.TB) *testBackend {
+	logger, err := frames.NewLogger("error")
+	require.NoError(t, err)
+
+	v3ioContext := fake.NewContext()
+	config := &frames.BackendConfig{Workers: 2, UpdateWorkersPerVN: 2}
+	backend, err := NewBackend(logger, v3ioContext, config, &frames.Config{})
+	require.NoError(t, err)
+
+	return &testBackend{
+		Backend:   backend.(*Backend),
+		t:         t,
+		container: v3ioContext.GetContainer("bigdata"),
+	}
+}
+
+// putItem writes an item directly to the container, bypassing the backend
+// (schema, indexes, etc.)
+func (b *testBackend) putItem(path string, attributes map[string]interface{}) {
+	_, err := b.container.PutItemSync(&v3io.PutItemInput{Path: path, Attributes: attributes})
+	require.NoError(b.t, err)
+}
+
+// getItem returns all the attributes of an item
+func (b *testBackend) getItem(path string) v3io.Item {
+	resp, err := b.container.GetItemSync(&v3io.GetItemInput{Path: path, AttributeNames: []string{"*"}})
+	require.NoError(b.t, err)
+	defer resp.Release()
+	return resp.Output.(*v3io.GetItemOutput).Item
+}
+
+func (b *testBackend) itemExists(path string) bool {
+	return b.container.CheckPathExistsSync(&v3io.CheckPathExistsInput{Path: path}) == nil
+}
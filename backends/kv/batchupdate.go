@@ -0,0 +1,172 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+)
+
+// Batch update outcome frame columns and statuses
+const (
+	batchUpdateKeyColumn     = "key"
+	batchUpdateStatusColumn  = "status"
+	batchUpdateCodeColumn    = "error_code"
+	batchUpdateMessageColumn = "message"
+
+	batchUpdateUpdated        = "updated"
+	batchUpdateConditionFalse = "condition_false"
+	batchUpdateItemExists     = "item_exists"
+	batchUpdateFailed         = "failed"
+)
+
+// batchUpdate applies the "expression" template (and the optional "condition"
+// template) to the item of every row in the request frame, the frame index
+// holds the item keys (and sorting keys). Templates are filled from the row
+// like in a write with an expression (see genExpr). The optional "save_mode"
+// argument is updateItem (default), overwriteItem or createNewItemsOnly.
+//
+// Returns a frame with the key, status, error_code and message of every row,
+// status is one of updated, condition_false, item_exists or failed.
+func (b *Backend) batchUpdate(request *frames.ExecRequest) (frames.Frame, error) {
+	varExpr, hasExpr := request.Proto.Args["expression"]
+	if !hasExpr || request.Proto.Table == "" {
+		return nil, fmt.Errorf("missing a required parameter - 'table' and/or 'expression' argument")
+	}
+
+	if request.Proto.Frame == nil {
+		return nil, fmt.Errorf("batch_update requires a frame of keys")
+	}
+
+	frame := frames.NewFrameFromProto(request.Proto.Frame)
+	if len(frame.Indices()) == 0 {
+		return nil, fmt.Errorf("batch_update frame must be indexed by the item keys")
+	}
+
+	if len(frame.Indices()) > 2 {
+		return nil, fmt.Errorf("batch_update frame can have up to two indices (key and sorting key)")
+	}
+
+	condition := ""
+	if val, ok := request.Proto.Args["condition"]; ok {
+		condition = val.GetSval()
+	}
+
+	saveMode := frames.UpdateItem
+	if val, ok := request.Proto.Args["save_mode"]; ok {
+		var err error
+		if saveMode, err = frames.SaveModeFromString(val.GetSval()); err != nil {
+			return nil, err
+		}
+	}
+
+	switch saveMode {
+	case frames.UpdateItem, frames.OverwriteItem, frames.CreateNewItemsOnly:
+	default:
+		return nil, fmt.Errorf("batch_update doesn't support save mode '%v'", saveMode)
+	}
+
+	writeRequest := &frames.WriteRequest{
+		Session:         request.Proto.Session,
+		Password:        request.Password,
+		Token:           request.Token,
+		Backend:         request.Proto.Backend,
+		Table:           request.Proto.Table,
+		Expression:      varExpr.GetSval(),
+		Condition:       condition,
+		SaveMode:        saveMode,
+		ContinueOnError: true,
+	}
+
+	frameAppender, err := b.Write(writeRequest)
+	if err != nil {
+		return nil, err
+	}
+	appender := frameAppender.(*Appender)
+
+	keyFunc, err := appender.keyFunc(frame)
+	if err == nil {
+		// The frame is checked above, skip the column checks of Add
+		err = appender.update(frame)
+	}
+
+	if waitErr := appender.WaitForComplete(0); err == nil {
+		err = waitErr
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "batch update failed")
+	}
+
+	rowErrors := make(map[string]frames.RowError)
+	for _, rowError := range appender.TakeRowErrors() {
+		rowErrors[rowError.Key] = rowError
+	}
+
+	numRows := frame.Len()
+	data := map[string][]string{
+		batchUpdateKeyColumn:     make([]string, numRows),
+		batchUpdateStatusColumn:  make([]string, numRows),
+		batchUpdateCodeColumn:    make([]string, numRows),
+		batchUpdateMessageColumn: make([]string, numRows),
+	}
+
+	for r := 0; r < numRows; r++ {
+		key := keyFunc(r)
+		data[batchUpdateKeyColumn][r] = key
+
+		rowError, failed := rowErrors[key]
+		if !failed {
+			data[batchUpdateStatusColumn][r] = batchUpdateUpdated
+			continue
+		}
+
+		data[batchUpdateStatusColumn][r] = batchUpdateStatus(rowError)
+		data[batchUpdateCodeColumn][r] = rowError.Code
+		data[batchUpdateMessageColumn][r] = rowError.Message
+	}
+
+	b.logger.DebugWith("batch update", "table", request.Proto.Table, "rows", numRows, "failed", len(rowErrors))
+
+	var columns []frames.Column
+	for _, name := range []string{batchUpdateKeyColumn, batchUpdateStatusColumn, batchUpdateCodeColumn, batchUpdateMessageColumn} {
+		col, err := frames.NewSliceColumn(name, data[name])
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+
+	return frames.NewFrame(columns, nil, nil)
+}
+
+// batchUpdateStatus returns the status of a failed row update
+func batchUpdateStatus(rowError frames.RowError) string {
+	switch rowError.Code {
+	case falseConditionErrorCode:
+		return batchUpdateConditionFalse
+	case createNewItemOnlyExistingItemErrorCode:
+		return batchUpdateItemExists
+	}
+
+	return batchUpdateFailed
+}
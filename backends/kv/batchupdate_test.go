@@ -0,0 +1,171 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+)
+
+type BatchUpdateTestSuite struct {
+	suite.Suite
+	backend *testBackend
+}
+
+func (suite *BatchUpdateTestSuite) SetupTest() {
+	suite.backend = newTestBackend(suite.T())
+	for _, key := range []string{"a", "b"} {
+		suite.backend.putItem("table/"+key, map[string]interface{}{"count": 1})
+	}
+}
+
+func (suite *BatchUpdateTestSuite) execRequest(frame frames.Frame, args map[string]interface{}) *frames.ExecRequest {
+	pbArgs := make(map[string]*pb.Value)
+	for name, value := range args {
+		pbValue := &pb.Value{}
+		suite.Require().NoError(pbValue.SetValue(value))
+		pbArgs[name] = pbValue
+	}
+
+	request := &pb.ExecRequest{
+		Session: &frames.Session{Container: "bigdata"},
+		Backend: "kv",
+		Table:   "table",
+		Command: "batch_update",
+		Args:    pbArgs,
+	}
+	if frame != nil {
+		request.Frame = frame.(pb.Framed).Proto()
+	}
+
+	return &frames.ExecRequest{
+		Proto:    request,
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+}
+
+func (suite *BatchUpdateTestSuite) keysFrame(keys []string, deltas []int64) frames.Frame {
+	keysCol, err := frames.NewSliceColumn("key", keys)
+	suite.Require().NoError(err)
+	deltaCol, err := frames.NewSliceColumn("delta", deltas)
+	suite.Require().NoError(err)
+
+	frame, err := frames.NewFrame([]frames.Column{deltaCol}, []frames.Column{keysCol}, nil)
+	suite.Require().NoError(err)
+	return frame
+}
+
+func (suite *BatchUpdateTestSuite) count(key string) interface{} {
+	return suite.backend.getItem("table/" + key)["count"]
+}
+
+func (suite *BatchUpdateTestSuite) TestBatchUpdate() {
+	frame := suite.keysFrame([]string{"a", "b", "c"}, []int64{10, 20, 30})
+	args := map[string]interface{}{
+		"expression": "count=count+{delta}",
+		"condition":  "count < 5",
+	}
+
+	out, err := suite.backend.Exec(suite.execRequest(frame, args))
+	suite.Require().NoError(err)
+	suite.Require().Equal(3, out.Len())
+
+	keys, err := out.Column(batchUpdateKeyColumn)
+	suite.Require().NoError(err)
+	statuses, err := out.Column(batchUpdateStatusColumn)
+	suite.Require().NoError(err)
+
+	expected := map[string]string{
+		"a": batchUpdateUpdated,
+		"b": batchUpdateUpdated,
+		"c": batchUpdateConditionFalse, // count doesn't exist
+	}
+	for r := 0; r < out.Len(); r++ {
+		key, _ := keys.StringAt(r)
+		status, _ := statuses.StringAt(r)
+		suite.Require().Equal(expected[key], status, "key %s", key)
+	}
+
+	suite.Require().Equal(11, suite.count("a"))
+	suite.Require().Equal(21, suite.count("b"))
+
+	// Condition is now false for all
+	out, err = suite.backend.Exec(suite.execRequest(frame, args))
+	suite.Require().NoError(err)
+	statuses, err = out.Column(batchUpdateStatusColumn)
+	suite.Require().NoError(err)
+	for r := 0; r < out.Len(); r++ {
+		status, _ := statuses.StringAt(r)
+		suite.Require().Equal(batchUpdateConditionFalse, status)
+	}
+	suite.Require().Equal(11, suite.count("a"))
+}
+
+func (suite *BatchUpdateTestSuite) TestBatchUpdateCreateNewItemsOnly() {
+	frame := suite.keysFrame([]string{"a", "c"}, []int64{1, 2})
+	args := map[string]interface{}{
+		"expression": "count={delta}",
+		"save_mode":  "createNewItemsOnly",
+	}
+
+	out, err := suite.backend.Exec(suite.execRequest(frame, args))
+	suite.Require().NoError(err)
+
+	statuses, err := out.Column(batchUpdateStatusColumn)
+	suite.Require().NoError(err)
+	status, _ := statuses.StringAt(0)
+	suite.Require().Equal(batchUpdateItemExists, status)
+	status, _ = statuses.StringAt(1)
+	suite.Require().Equal(batchUpdateUpdated, status)
+
+	suite.Require().Equal(1, suite.count("a"))
+	suite.Require().Equal(2, suite.count("c"))
+}
+
+func (suite *BatchUpdateTestSuite) TestBatchUpdateErrors() {
+	frame := suite.keysFrame([]string{"a"}, []int64{1})
+	noIndex, err := frames.NewFrame(frame.Indices(), nil, nil)
+	suite.Require().NoError(err)
+
+	badRequests := []struct {
+		frame frames.Frame
+		args  map[string]interface{}
+	}{
+		{frame, nil},
+		{nil, map[string]interface{}{"expression": "count=1"}},
+		{noIndex, map[string]interface{}{"expression": "count=1"}},
+		{frame, map[string]interface{}{"expression": "count=1", "save_mode": "overwriteTable"}},
+		{frame, map[string]interface{}{"expression": "count=1", "save_mode": "nope"}},
+	}
+
+	for _, request := range badRequests {
+		_, err := suite.backend.Exec(suite.execRequest(request.frame, request.args))
+		suite.Require().Error(err, "args: %v", request.args)
+	}
+}
+
+func TestBatchUpdateTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchUpdateTestSuite))
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils"
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+)
+
+// batchItem is an item written by a batch write
+type batchItem struct {
+	appender *Appender // of the item table
+	table    int       // index in the request
+	input    *v3io.UpdateItemInput
+
+	before     v3io.Item // attributes before the batch, nil if there was no item
+	hasVersion bool
+	version    int64 // before the batch
+	updated    bool
+	restoreErr error
+}
+
+// batchItemError is the error of the item that aborted a batch write
+type batchItemError struct {
+	item *batchItem
+	err  error
+}
+
+func (e *batchItemError) Error() string {
+	return e.err.Error()
+}
+
+// BatchWrite writes the frames of request to their tables, either all the
+// items are written or none is.
+//
+// Items are read first, to get their version attribute and the attributes to
+// restore if the batch fails. Then they are updated (with the next version)
+// on condition that their version didn't change since. If an item update
+// fails, items that were updated are restored on condition that their
+// version is still the one the batch wrote; tables with items that could not
+// be restored get the BatchRollbackFailed status. Items can't be deleted on
+// condition, so the items the batch created are left without attributes.
+//
+// Versions detect changes of writers that set them (other batch writes), an
+// item without a version is only checked to still not have one. Each item can
+// be written once per batch, a batch with duplicate keys is rejected.
+//
+// Table schemas (new columns and the version attribute) are saved only after
+// the batch is committed, an aborted batch leaves them unchanged.
+func (b *Backend) BatchWrite(request *frames.BatchWriteRequest) (*frames.BatchWriteResult, error) {
+	versionAttr := request.VersionAttribute
+	if versionAttr == "" {
+		versionAttr = frames.DefaultVersionAttribute
+	}
+	if !validColumnNamePattern.MatchString(versionAttr) {
+		return nil, fmt.Errorf("invalid version attribute '%s'", versionAttr)
+	}
+
+	if len(request.Tables) == 0 {
+		return nil, fmt.Errorf("batch write has no tables")
+	}
+
+	result := &frames.BatchWriteResult{}
+	var items []*batchItem
+	paths := make(map[string]bool)
+	tableAppenders := make([]*Appender, len(request.Tables))
+	for i, table := range request.Tables {
+		result.Tables = append(result.Tables, &frames.BatchTableResult{Table: table.Table, Status: frames.BatchAborted})
+		appender, err := b.batchAppender(request, table, versionAttr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't write to table '%s'", table.Table)
+		}
+
+		tableAppenders[i] = appender
+		result.Tables[i].Items = len(appender.collected)
+		for _, input := range appender.collected {
+			item := &batchItem{appender: appender, table: i, input: input}
+			// An item can be updated once, a second update would fail the
+			// version condition of the first and abort the batch
+			if paths[input.Path] {
+				return nil, fmt.Errorf("item '%s' of table '%s' is written more than once", item.key(), table.Table)
+			}
+			paths[input.Path] = true
+			items = append(items, item)
+		}
+	}
+
+	err := b.forEach(len(items), func(i int) error {
+		return items[i].read(versionAttr)
+	})
+	if err == nil {
+		err = b.forEach(len(items), func(i int) error {
+			return items[i].update(versionAttr)
+		})
+	}
+
+	if err != nil {
+		b.logger.WarnWith("batch write aborted", "error", err)
+		if itemErr, ok := err.(*batchItemError); ok {
+			result.Tables[itemErr.item.table].Error = itemErr.err.Error()
+		}
+
+		b.rollback(items, versionAttr, result)
+		return result, errors.Wrap(err, "batch write aborted")
+	}
+
+	result.Committed = true
+	for _, tableResult := range result.Tables {
+		tableResult.Status = frames.BatchCommitted
+	}
+
+	for i, table := range request.Tables {
+		if err := saveBatchSchema(tableAppenders[i]); err != nil {
+			b.logger.WarnWith("can't save batch table schema", "table", table.Table, "error", err)
+			result.Tables[i].Error = err.Error()
+		}
+	}
+
+	for _, item := range items {
+		if len(item.appender.indexes) == 0 {
+			continue
+		}
+		if err := item.appender.indexItem(item.input.Path); err != nil {
+			b.logger.WarnWith("can't index batch item", "path", item.input.Path, "error", err)
+			result.Tables[item.table].Error = err.Error()
+		}
+	}
+
+	return result, nil
+}
+
+// batchAppender returns an appender with the update requests of the table
+// frame, the version attribute is added to the appender schema (see
+// saveBatchSchema)
+func (b *Backend) batchAppender(request *frames.BatchWriteRequest, table *frames.BatchWriteTable, versionAttr string) (*Appender, error) {
+	if table.Frame == nil || len(table.Frame.Indices()) == 0 {
+		return nil, fmt.Errorf("batch write frames must be indexed by the item keys")
+	}
+
+	frameAppender, err := b.Write(&frames.WriteRequest{
+		Session:       request.Session,
+		Password:      request.Password,
+		Token:         request.Token,
+		Backend:       request.Backend,
+		Table:         table.Table,
+		SaveMode:      frames.UpdateItem,
+		PartitionKeys: table.PartitionKeys,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	appender := frameAppender.(*Appender)
+	appender.collect = true
+	err = appender.Add(table.Frame)
+	if err == nil {
+		schema := appender.schema.(*v3ioutils.OldV3ioSchema)
+		newSchema := v3ioutils.NewSchema(schema.Key, schema.SortingKey)
+		if err = newSchema.AddField(versionAttr, int64(0), true); err == nil {
+			_, err = schema.Merge(newSchema)
+		}
+	}
+
+	// Nothing was sent to the update workers, stop them
+	if waitErr := appender.WaitForComplete(0); err == nil {
+		err = waitErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return appender, nil
+}
+
+// saveBatchSchema merges the appender schema into the current table schema
+// and saves it, the table schema is read again since batches writing to the
+// same table may have saved it after the appender was created
+func saveBatchSchema(appender *Appender) error {
+	schema := appender.schema.(*v3ioutils.OldV3ioSchema)
+	tableSchema, err := v3ioutils.GetSchema(appender.tablePath, appender.container)
+	if err != nil {
+		if !isNotFoundError(err) {
+			return err
+		}
+		tableSchema = v3ioutils.NewSchema(schema.Key, schema.SortingKey)
+	}
+
+	return tableSchema.UpdateSchema(appender.container, appender.tablePath, schema)
+}
+
+// read reads the item version and attributes
+func (item *batchItem) read(versionAttr string) error {
+	input := &v3io.GetItemInput{Path: item.input.Path, AttributeNames: []string{"*"}}
+	resp, err := item.appender.container.GetItemSync(input)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil
+		}
+		return &batchItemError{item, errors.Wrapf(err, "can't read item '%s'", item.key())}
+	}
+	item.before = resp.Output.(*v3io.GetItemOutput).Item
+	resp.Release()
+
+	switch version := item.before[versionAttr].(type) {
+	case int:
+		item.version, item.hasVersion = int64(version), true
+	case int64:
+		item.version, item.hasVersion = version, true
+	case float64:
+		item.version, item.hasVersion = int64(version), true
+	}
+
+	return nil
+}
+
+// update writes the item with the next version, on condition that its
+// version didn't change
+func (item *batchItem) update(versionAttr string) error {
+	input := *item.input
+	expression := fmt.Sprintf("%s%s=%d;", *input.Expression, versionAttr, item.version+1)
+	input.Expression = &expression
+	input.Condition = fmt.Sprintf("NOT exists(%s)", versionAttr)
+	if item.hasVersion {
+		input.Condition = fmt.Sprintf("%s == %d", versionAttr, item.version)
+	}
+
+	resp, err := item.appender.container.UpdateItemSync(&input)
+	if err != nil {
+		if isFalseConditionError(err) {
+			err = fmt.Errorf("item '%s' was changed by another writer", item.key())
+		} else {
+			err = errors.Wrapf(err, "can't update item '%s'", item.key())
+		}
+		return &batchItemError{item, err}
+	}
+	resp.Release()
+
+	item.updated = true
+	return nil
+}
+
+// restore restores the item to what it was before the batch, if its version
+// is still the one the batch wrote
+func (item *batchItem) restore(versionAttr string) error {
+	input := &v3io.UpdateItemInput{
+		Path:      item.input.Path,
+		Condition: fmt.Sprintf("%s == %d", versionAttr, item.version+1),
+	}
+
+	if item.before == nil {
+		// Items can't be deleted on condition, the attributes of the item the
+		// batch created are deleted instead
+		expression, err := item.deleteExpression()
+		if err != nil {
+			return err
+		}
+		input.Expression = &expression
+	} else {
+		attributes := make(map[string]interface{}, len(item.before))
+		for name, value := range item.before {
+			if !strings.HasPrefix(name, "__") {
+				attributes[name] = value
+			}
+		}
+		input.Attributes = attributes
+		input.UpdateMode = frames.OverwriteItem.GetNginxModeName()
+	}
+
+	resp, err := item.appender.container.UpdateItemSync(input)
+	if err != nil {
+		if isFalseConditionError(err) {
+			return fmt.Errorf("item '%s' was changed by another writer", item.key())
+		}
+		return errors.Wrapf(err, "can't restore item '%s'", item.key())
+	}
+	resp.Release()
+	return nil
+}
+
+// deleteExpression returns an update expression deleting the item attributes
+func (item *batchItem) deleteExpression() (string, error) {
+	input := &v3io.GetItemInput{Path: item.input.Path, AttributeNames: []string{"*"}}
+	resp, err := item.appender.container.GetItemSync(input)
+	if err != nil {
+		return "", errors.Wrapf(err, "can't read item '%s'", item.key())
+	}
+	defer resp.Release()
+
+	expression := strings.Builder{}
+	for name := range resp.Output.(*v3io.GetItemOutput).Item {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		expression.WriteString("delete(")
+		expression.WriteString(name)
+		expression.WriteString(");")
+	}
+
+	return expression.String(), nil
+}
+
+// rollback restores the updated items and sets the table statuses
+func (b *Backend) rollback(items []*batchItem, versionAttr string, result *frames.BatchWriteResult) {
+	b.forEach(len(items), func(i int) error { // nolint: errcheck
+		if items[i].updated {
+			items[i].restoreErr = items[i].restore(versionAttr)
+		}
+		return nil
+	})
+
+	for _, item := range items {
+		if item.restoreErr == nil {
+			continue
+		}
+
+		b.logger.WarnWith("can't restore batch item", "path", item.input.Path, "error", item.restoreErr)
+		tableResult := result.Tables[item.table]
+		if tableResult.Status != frames.BatchRollbackFailed {
+			tableResult.Status = frames.BatchRollbackFailed
+			tableResult.Error = item.restoreErr.Error()
+		}
+	}
+}
+
+// key returns the item key, relative to the table path
+func (item *batchItem) key() string {
+	return strings.TrimPrefix(item.input.Path, item.appender.tablePath)
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils"
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+)
+
+// afterGetContainer calls afterGet after each item read
+type afterGetContainer struct {
+	v3io.Container
+	afterGet func()
+}
+
+func (c *afterGetContainer) GetItemSync(input *v3io.GetItemInput) (*v3io.Response, error) {
+	resp, err := c.Container.GetItemSync(input)
+	c.afterGet()
+	return resp, err
+}
+
+type BatchWriteTestSuite struct {
+	suite.Suite
+	backend *testBackend
+}
+
+func (suite *BatchWriteTestSuite) SetupTest() {
+	suite.backend = newTestBackend(suite.T())
+}
+
+func (suite *BatchWriteTestSuite) frame(keys []string, column string, values []int64) frames.Frame {
+	keyCol, err := frames.NewSliceColumn("key", keys)
+	suite.Require().NoError(err)
+	col, err := frames.NewSliceColumn(column, values)
+	suite.Require().NoError(err)
+	frame, err := frames.NewFrame([]frames.Column{col}, []frames.Column{keyCol}, nil)
+	suite.Require().NoError(err)
+	return frame
+}
+
+func (suite *BatchWriteTestSuite) request(tables ...*frames.BatchWriteTable) *frames.BatchWriteRequest {
+	return &frames.BatchWriteRequest{
+		Session:  &frames.Session{Container: "bigdata"},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+		Backend:  "kv",
+		Tables:   tables,
+	}
+}
+
+// order returns a batch writing an order and its lines
+func (suite *BatchWriteTestSuite) order(total int64, lines ...string) *frames.BatchWriteRequest {
+	quantities := make([]int64, len(lines))
+	for i := range lines {
+		quantities[i] = int64(i + 1)
+	}
+
+	return suite.request(
+		&frames.BatchWriteTable{Table: "orders", Frame: suite.frame([]string{"o1"}, "total", []int64{total})},
+		&frames.BatchWriteTable{Table: "lines", Frame: suite.frame(lines, "quantity", quantities)},
+	)
+}
+
+func (suite *BatchWriteTestSuite) TestCommit() {
+	result, err := suite.backend.BatchWrite(suite.order(10, "l1", "l2"))
+	suite.Require().NoError(err)
+	suite.Require().True(result.Committed)
+	suite.Require().Len(result.Tables, 2)
+	for _, table := range result.Tables {
+		suite.Require().Equal(frames.BatchCommitted, table.Status)
+	}
+	suite.Require().Equal(2, result.Tables[1].Items)
+
+	suite.Require().EqualValues(1, suite.backend.getItem("orders/o1")[frames.DefaultVersionAttribute])
+	suite.Require().EqualValues(2, suite.backend.getItem("lines/l2")["quantity"])
+
+	schema, err := v3ioutils.GetSchema("lines/", suite.backend.container)
+	suite.Require().NoError(err)
+	for _, name := range []string{"quantity", frames.DefaultVersionAttribute} {
+		_, err = schema.(*v3ioutils.OldV3ioSchema).GetField(name)
+		suite.Require().NoError(err, name)
+	}
+
+	result, err = suite.backend.BatchWrite(suite.order(20, "l1"))
+	suite.Require().NoError(err)
+	suite.Require().True(result.Committed)
+	order := suite.backend.getItem("orders/o1")
+	suite.Require().EqualValues(2, order[frames.DefaultVersionAttribute])
+	suite.Require().EqualValues(20, order["total"])
+}
+
+func (suite *BatchWriteTestSuite) TestRollback() {
+	_, err := suite.backend.BatchWrite(suite.order(10))
+	suite.Require().NoError(err)
+
+	// lines/bad is a directory, it can't be updated
+	suite.backend.putItem("lines/bad/x", map[string]interface{}{"a": 1})
+
+	request := suite.order(20, "l1", "bad")
+	request.Tables = append(request.Tables, &frames.BatchWriteTable{Table: "notes", Frame: suite.frame([]string{"n1"}, "count", []int64{1})})
+	result, err := suite.backend.BatchWrite(request)
+	suite.Require().Error(err)
+	suite.Require().False(result.Committed)
+	suite.Require().Equal(frames.BatchAborted, result.Tables[0].Status)
+	suite.Require().Equal(frames.BatchAborted, result.Tables[1].Status)
+	suite.Require().Empty(result.Tables[0].Error)
+	suite.Require().Contains(result.Tables[1].Error, "bad")
+
+	order := suite.backend.getItem("orders/o1")
+	suite.Require().EqualValues(10, order["total"])
+	suite.Require().EqualValues(1, order[frames.DefaultVersionAttribute])
+	// Items the batch created are left without attributes
+	line := suite.backend.getItem("lines/l1")
+	suite.Require().NotContains(line, "quantity")
+	suite.Require().NotContains(line, frames.DefaultVersionAttribute)
+
+	// Schemas are saved only on commit
+	suite.Require().False(suite.backend.itemExists("notes/.#schema"))
+	suite.Require().NotContains(suite.backend.getItem("notes/n1"), "count")
+}
+
+func (suite *BatchWriteTestSuite) TestRollbackFailed() {
+	_, err := suite.backend.BatchWrite(suite.order(10))
+	suite.Require().NoError(err)
+
+	appender, err := suite.backend.batchAppender(suite.order(20), suite.order(20).Tables[0], frames.DefaultVersionAttribute)
+	suite.Require().NoError(err)
+	item := &batchItem{appender: appender, input: appender.collected[0]}
+	suite.Require().NoError(item.read(frames.DefaultVersionAttribute))
+	suite.Require().NoError(item.update(frames.DefaultVersionAttribute))
+
+	// Another writer changes the item after the batch
+	other := &batchItem{appender: appender, input: appender.collected[0]}
+	suite.Require().NoError(other.read(frames.DefaultVersionAttribute))
+	suite.Require().NoError(other.update(frames.DefaultVersionAttribute))
+	// The batch version check fails
+	suite.Require().Error(item.update(frames.DefaultVersionAttribute))
+
+	result := &frames.BatchWriteResult{Tables: []*frames.BatchTableResult{{Table: "orders", Status: frames.BatchAborted}}}
+	suite.backend.rollback([]*batchItem{item}, frames.DefaultVersionAttribute, result)
+	suite.Require().Equal(frames.BatchRollbackFailed, result.Tables[0].Status)
+	suite.Require().EqualValues(3, suite.backend.getItem("orders/o1")[frames.DefaultVersionAttribute])
+}
+
+func (suite *BatchWriteTestSuite) TestRollbackFailedNewItem() {
+	request := suite.order(10)
+	appender, err := suite.backend.batchAppender(request, request.Tables[0], frames.DefaultVersionAttribute)
+	suite.Require().NoError(err)
+	item := &batchItem{appender: appender, input: appender.collected[0]}
+	suite.Require().NoError(item.read(frames.DefaultVersionAttribute))
+	suite.Require().NoError(item.update(frames.DefaultVersionAttribute))
+
+	// Another writer changes the item the batch created after the rollback
+	// reads it
+	appender.container = &afterGetContainer{
+		Container: appender.container,
+		afterGet: func() {
+			suite.backend.putItem("orders/o1", map[string]interface{}{"total": 30, frames.DefaultVersionAttribute: 5})
+		},
+	}
+
+	result := &frames.BatchWriteResult{Tables: []*frames.BatchTableResult{{Table: "orders", Status: frames.BatchAborted}}}
+	suite.backend.rollback([]*batchItem{item}, frames.DefaultVersionAttribute, result)
+	suite.Require().Equal(frames.BatchRollbackFailed, result.Tables[0].Status)
+	suite.Require().EqualValues(30, suite.backend.getItem("orders/o1")["total"])
+}
+
+func (suite *BatchWriteTestSuite) TestBadRequest() {
+	keys, err := frames.NewSliceColumn("key", []string{"a"})
+	suite.Require().NoError(err)
+	frame, err := frames.NewFrame([]frames.Column{keys}, nil, nil)
+	suite.Require().NoError(err)
+
+	_, err = suite.backend.BatchWrite(suite.request(&frames.BatchWriteTable{Table: "orders", Frame: frame}))
+	suite.Require().Error(err)
+
+	request := suite.order(10, "l1")
+	request.VersionAttribute = "bad attr"
+	_, err = suite.backend.BatchWrite(request)
+	suite.Require().Error(err)
+}
+
+func (suite *BatchWriteTestSuite) TestDuplicateKeys() {
+	_, err := suite.backend.BatchWrite(suite.order(10, "l1", "l1"))
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "more than once")
+	suite.Require().False(suite.backend.itemExists("orders/o1"))
+
+	// The same table twice in a batch
+	request := suite.order(10)
+	request.Tables[1] = request.Tables[0]
+	_, err = suite.backend.BatchWrite(request)
+	suite.Require().Error(err)
+	suite.Require().False(suite.backend.itemExists("orders/o1"))
+}
+
+func TestBatchWriteTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchWriteTestSuite))
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils"
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+	v3ioerrors "github.com/v3io/v3io-go/pkg/errors"
+	"github.com/v3io/v3io-tsdb/pkg/utils"
+)
+
+const schemaFileName = ".#schema"
+
+// deleteItems deletes the items of the table at tablePath that match filter
+// (all items if it's empty), they are deleted while they're listed. If onKey
+// isn't nil it's called with the key of every matching item, relative to
+// tablePath (e.g. "col=value/key" in partitioned tables). Nothing is deleted
+// if dryRun is set.
+//
+// Partitions that are pruned by the filter are not read. Partitions whose
+// items all match (the filter has only predicates on partition columns) are
+// removed with their directory. Deleting all the items also removes the
+// schema and the table directory.
+func (kv *Backend) deleteItems(container v3io.Container, tablePath string, filter string, dryRun bool, ignoreMissing bool, onKey func(key string)) error {
+	schema := &v3ioutils.OldV3ioSchema{}
+	if schemaInterface, err := v3ioutils.GetSchema(tablePath, container); err == nil {
+		schema = schemaInterface.(*v3ioutils.OldV3ioSchema)
+	} else if !isNotFoundError(err) {
+		return err
+	}
+
+	partitionFilter := parsePartitionFilter(filter)
+	partitions, err := kv.getPartitions(tablePath, container, partitionFilter)
+	if err != nil {
+		if isNotFoundError(err) && ignoreMissing {
+			return nil
+		}
+		return errors.Wrapf(err, "can't list partitions of '%s'", tablePath)
+	}
+
+	_, partitionTypes := partitionColumns(tablePath, partitions, schema)
+	itemsFilter := partitionFilter.residual(partitionTypes)
+	if itemsFilter != "" {
+		itemsFilter = "(" + itemsFilter + ") AND __name != '" + schemaFileName + "'"
+	}
+
+	numItems := 0
+	if len(partitions) > 0 {
+		input := v3io.GetItemsInput{Filter: itemsFilter, AttributeNames: []string{indexColKey}}
+		iter, err := v3ioutils.NewAsyncItemsCursor(container, &input, kv.numWorkers, nil, kv.logger, 0, partitions, "", "")
+		if err != nil {
+			return err
+		}
+
+		var paths chan<- string
+		wait := func() error { return nil }
+		if !dryRun {
+			paths, wait = kv.startDeletes(container)
+		}
+
+		for iter.Next() {
+			name, _ := iter.GetField(indexColKey).(string)
+			if name == "" || name == schemaFileName {
+				continue
+			}
+
+			numItems++
+			partition := iter.CurrentPartition()
+			if onKey != nil {
+				onKey(strings.TrimPrefix(partition, tablePath) + name)
+			}
+			if paths != nil {
+				paths <- partition + name
+			}
+		}
+
+		deleteErr := wait()
+		if err := iter.Err(); err != nil {
+			if !isNotFoundError(err) || !ignoreMissing {
+				return errors.Wrapf(err, "can't list items of '%s'", tablePath)
+			}
+		}
+		if deleteErr != nil {
+			return errors.Wrapf(deleteErr, "can't delete items of '%s'", tablePath)
+		}
+	}
+
+	kv.logger.DebugWith("delete items", "table", tablePath, "filter", filter, "items", numItems, "partitions", len(partitions), "dryRun", dryRun)
+	if dryRun || itemsFilter != "" {
+		return nil
+	}
+
+	// All the items of the partitions were deleted
+	for _, partition := range partitions {
+		if partition == tablePath {
+			continue
+		}
+		if err := deletePartitionDir(container, tablePath, partition); err != nil {
+			return err
+		}
+	}
+
+	if filter == "" {
+		for _, attr := range schema.Indexes {
+			if err := kv.deleteIndex(container, tablePath, attr); err != nil {
+				return err
+			}
+		}
+		for _, path := range []string{tablePath + schemaFileName, tablePath} {
+			err := container.DeleteObjectSync(&v3io.DeleteObjectInput{Path: path})
+			if err != nil && !utils.IsNotExistsOrConflictError(err) {
+				return errors.Wrapf(err, "can't delete '%s'", path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// startDeletes deletes the paths sent to the returned channel using the update
+// workers. wait closes the channel and returns the first delete error, paths
+// sent after an error are not deleted.
+func (kv *Backend) startDeletes(container v3io.Container) (paths chan<- string, wait func() error) {
+	numWorkers := kv.numWorkers * kv.updateWorkersPerVN
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	pathChan := make(chan string, numWorkers)
+	failed := make(chan struct{})
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range pathChan {
+				select {
+				case <-failed:
+					continue
+				default:
+				}
+
+				err := container.DeleteObjectSync(&v3io.DeleteObjectInput{Path: path})
+				if err != nil && !utils.IsNotExistsOrConflictError(err) {
+					once.Do(func() {
+						firstErr = errors.Wrapf(err, "can't delete '%s'", path)
+						close(failed)
+					})
+				}
+			}
+		}()
+	}
+
+	wait = func() error {
+		close(pathChan)
+		wg.Wait()
+		return firstErr
+	}
+	return pathChan, wait
+}
+
+// forEach calls fn with 0 <= i < n using the update workers, it stops on the
+// first error
+func (kv *Backend) forEach(n int, fn func(i int) error) error {
+	numWorkers := kv.numWorkers * kv.updateWorkersPerVN
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	indexChan := make(chan int, numWorkers)
+	errChan := make(chan error, numWorkers)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexChan {
+				if err := fn(i); err != nil {
+					errChan <- err
+					return
+				}
+			}
+		}()
+	}
+
+	var err error
+	for i := 0; i < n; i++ {
+		select {
+		case indexChan <- i:
+			continue
+		case err = <-errChan:
+		}
+		break
+	}
+	close(indexChan)
+	wg.Wait()
+
+	if err == nil {
+		select {
+		case err = <-errChan:
+		default:
+		}
+	}
+
+	return err
+}
+
+// deletePartitionDir deletes an (empty) partition directory and the parent
+// partition directories it leaves empty
+func deletePartitionDir(container v3io.Container, tablePath string, partition string) error {
+	for dir := partition; strings.HasPrefix(dir, tablePath) && dir != tablePath; {
+		err := container.DeleteObjectSync(&v3io.DeleteObjectInput{Path: dir})
+		if err != nil {
+			if errorWithStatus, ok := err.(v3ioerrors.ErrorWithStatusCode); ok && errorWithStatus.StatusCode() == http.StatusConflict {
+				// Not empty, other partitions are left
+				return nil
+			}
+			if !isNotFoundError(err) {
+				return errors.Wrapf(err, "can't delete partition '%s'", dir)
+			}
+		}
+
+		dir = dir[:strings.LastIndex(strings.TrimSuffix(dir, "/"), "/")+1]
+	}
+
+	return nil
+}
+
+// isNotFoundError returns true if err is a V3IO 404 error
+func isNotFoundError(err error) bool {
+	errorWithStatus, ok := errors.Cause(err).(v3ioerrors.ErrorWithStatusCode)
+	return ok && errorWithStatus.StatusCode() == http.StatusNotFound
+}
+
+// deleteKeys is the delete exec command, it deletes the items matching the
+// "filter" argument and returns a frame with their keys. Only the matching
+// keys are returned if the "dry_run" argument is set.
+func (kv *Backend) deleteKeys(request *frames.ExecRequest) (frames.Frame, error) {
+	filter, dryRun := "", false
+	if val, ok := request.Proto.Args["filter"]; ok {
+		filter = val.GetSval()
+	}
+	if val, ok := request.Proto.Args["dry_run"]; ok {
+		dryRun = val.GetBval()
+	}
+
+	container, tablePath, err := kv.newConnection(request.Proto.Session, request.Password.Get(), request.Token.Get(), request.Proto.Table, true)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	onKey := func(key string) { keys = append(keys, key) }
+	if err := kv.deleteItems(container, tablePath, filter, dryRun, false, onKey); err != nil {
+		return nil, err
+	}
+
+	col, err := frames.NewSliceColumn("key", keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return frames.NewFrame([]frames.Column{col}, nil, nil)
+}
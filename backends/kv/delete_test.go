@@ -0,0 +1,156 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+)
+
+type DeleteTestSuite struct {
+	suite.Suite
+	backend *testBackend
+}
+
+func (suite *DeleteTestSuite) SetupTest() {
+	suite.backend = newTestBackend(suite.T())
+
+	keys, err := frames.NewSliceColumn("key", []string{"a", "b", "c", "d"})
+	suite.Require().NoError(err)
+	p, err := frames.NewSliceColumn("p", []int64{1, 1, 2, 2})
+	suite.Require().NoError(err)
+	x, err := frames.NewSliceColumn("x", []int64{1, 2, 3, 4})
+	suite.Require().NoError(err)
+	frame, err := frames.NewFrame([]frames.Column{p, x}, []frames.Column{keys}, nil)
+	suite.Require().NoError(err)
+
+	request := &frames.WriteRequest{
+		Session:       &frames.Session{Container: "bigdata"},
+		Password:      frames.InitSecretString(""),
+		Token:         frames.InitSecretString(""),
+		Table:         "table",
+		ImmidiateData: frame,
+		PartitionKeys: []string{"p"},
+	}
+	appender, err := suite.backend.Write(request)
+	suite.Require().NoError(err)
+	suite.Require().NoError(appender.WaitForComplete(time.Second))
+}
+
+func (suite *DeleteTestSuite) exec(filter string, dryRun bool) []string {
+	request := &frames.ExecRequest{
+		Proto: &pb.ExecRequest{
+			Session: &frames.Session{Container: "bigdata"},
+			Backend: "kv",
+			Table:   "table",
+			Command: "delete",
+			Args: map[string]*pb.Value{
+				"filter":  {Value: &pb.Value_Sval{Sval: filter}},
+				"dry_run": {Value: &pb.Value_Bval{Bval: dryRun}},
+			},
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+
+	frame, err := suite.backend.Exec(request)
+	suite.Require().NoError(err)
+
+	col, err := frame.Column("key")
+	suite.Require().NoError(err)
+	keys := col.(interface{ Strings() []string }).Strings()
+	sort.Strings(keys)
+	return keys
+}
+
+func (suite *DeleteTestSuite) TestDryRun() {
+	keys := suite.exec("p == 1", true)
+	suite.Require().Equal([]string{"p=1/a", "p=1/b"}, keys)
+	suite.Require().True(suite.backend.itemExists("table/p=1/a"))
+}
+
+func (suite *DeleteTestSuite) TestDeletePartition() {
+	keys := suite.exec("p == 1", false)
+	suite.Require().Equal([]string{"p=1/a", "p=1/b"}, keys)
+	suite.Require().False(suite.backend.itemExists("table/p=1/"))
+	suite.Require().True(suite.backend.itemExists("table/p=2/c"))
+}
+
+func (suite *DeleteTestSuite) TestDeleteItems() {
+	keys := suite.exec("x > 1 AND p == 1", false)
+	suite.Require().Equal([]string{"p=1/b"}, keys)
+	suite.Require().True(suite.backend.itemExists("table/p=1/a"))
+	suite.Require().False(suite.backend.itemExists("table/p=1/b"))
+}
+
+func (suite *DeleteTestSuite) TestDeleteAll() {
+	keys := suite.exec("", false)
+	suite.Require().Equal([]string{"p=1/a", "p=1/b", "p=2/c", "p=2/d"}, keys)
+	suite.Require().False(suite.backend.itemExists("table/.#schema"))
+	suite.Require().False(suite.backend.itemExists("table/"))
+}
+
+func (suite *DeleteTestSuite) TestDeleteMissing() {
+	request := &frames.DeleteRequest{
+		Proto: &pb.DeleteRequest{
+			Session:   &frames.Session{Container: "bigdata"},
+			Table:     "nope",
+			IfMissing: frames.IgnoreError,
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+	suite.Require().NoError(suite.backend.Delete(request))
+
+	request.Proto.IfMissing = frames.FailOnError
+	suite.Require().Error(suite.backend.Delete(request))
+}
+
+func (suite *DeleteTestSuite) TestDeleteRequest() {
+	// Items are deleted while the next pages are listed
+	suite.backend.container.GetItemsLimit = 1
+
+	request := &frames.DeleteRequest{
+		Proto: &pb.DeleteRequest{
+			Session: &frames.Session{Container: "bigdata"},
+			Table:   "table",
+			Filter:  "x > 1",
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+	suite.Require().NoError(suite.backend.Delete(request))
+
+	suite.Require().True(suite.backend.itemExists("table/p=1/a"))
+	for _, path := range []string{"table/p=1/b", "table/p=2/c", "table/p=2/d"} {
+		suite.Require().False(suite.backend.itemExists(path), path)
+	}
+	suite.Require().True(suite.backend.itemExists("table/.#schema"))
+}
+
+func TestDeleteTestSuite(t *testing.T) {
+	suite.Run(t, new(DeleteTestSuite))
+}
@@ -0,0 +1,559 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils"
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+	"github.com/v3io/v3io-tsdb/pkg/utils"
+)
+
+// Secondary indexes (see OldV3ioSchema.Indexes) map attribute values to the
+// keys of the items that have them. The index of attribute col is kept
+// under .#index/col/ in the table directory, with an entry for every indexed
+// item in a col=value/ directory (e.g. .#index/city/city=NY/p%3D1%2Fa for
+// item p=1/a). Entries are added when items are written, but aren't removed
+// when items change or are deleted, so reads check the items they find in an
+// index against the filter. Rebuilding an index removes its stale entries.
+
+const (
+	indexDirName      = ".#index/"
+	indexKeyAttr      = "key"
+	indexAttributeArg = "attribute"
+)
+
+// indexDir returns the directory of the attr index of the table at tablePath
+func indexDir(tablePath string, attr string) string {
+	return tablePath + indexDirName + attr + "/"
+}
+
+// indexValueDir returns the directory of the index entries of items whose
+// attr is value, and false if attributes of the schema type fieldType can't
+// be equal to value
+func indexValueDir(tablePath string, attr string, fieldType string, value interface{}) (string, bool) {
+	var str string
+	switch fieldType {
+	case v3ioutils.LongType:
+		switch value := value.(type) {
+		case int:
+			str = strconv.Itoa(value)
+		case int64:
+			str = strconv.FormatInt(value, 10)
+		case float64:
+			if value != math.Trunc(value) {
+				return "", false
+			}
+			str = strconv.FormatInt(int64(value), 10)
+		default:
+			return "", false
+		}
+	case v3ioutils.DoubleType:
+		switch value := value.(type) {
+		case int:
+			str = strconv.FormatFloat(float64(value), 'g', -1, 64)
+		case int64:
+			str = strconv.FormatFloat(float64(value), 'g', -1, 64)
+		case float64:
+			str = strconv.FormatFloat(value, 'g', -1, 64)
+		default:
+			return "", false
+		}
+	case v3ioutils.StringType:
+		s, ok := value.(string)
+		if !ok {
+			return "", false
+		}
+		str = s
+	case v3ioutils.BoolType:
+		b, ok := value.(bool)
+		if !ok {
+			return "", false
+		}
+		str = strconv.FormatBool(b)
+	default:
+		return "", false
+	}
+
+	return indexDir(tablePath, attr) + attr + "=" + url.PathEscape(str) + "/", true
+}
+
+// isIndexable returns true if attributes of schema type fieldType can be
+// indexed
+func isIndexable(fieldType string) bool {
+	switch fieldType {
+	case v3ioutils.LongType, v3ioutils.DoubleType, v3ioutils.StringType, v3ioutils.BoolType:
+		return true
+	}
+
+	return false
+}
+
+// indexFields returns the schema fields of the indexed attributes
+func indexFields(schema *v3ioutils.OldV3ioSchema) []v3ioutils.OldSchemaField {
+	var fields []v3ioutils.OldSchemaField
+	for _, attr := range schema.Indexes {
+		if field, err := schema.GetField(attr); err == nil {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// putIndexEntry adds the entry of the item key (relative to tablePath) whose
+// attribute is value to the index of field
+func putIndexEntry(container v3io.Container, tablePath string, field v3ioutils.OldSchemaField, key string, value interface{}) error {
+	dir, ok := indexValueDir(tablePath, field.Name, field.Type, value)
+	if !ok {
+		return nil
+	}
+
+	input := &v3io.PutItemInput{
+		Path:       dir + url.PathEscape(key),
+		Attributes: map[string]interface{}{indexKeyAttr: key},
+	}
+	resp, err := container.PutItemSync(input)
+	if err != nil {
+		return errors.Wrapf(err, "can't index item '%s' by %s", key, field.Name)
+	}
+	resp.Release()
+	return nil
+}
+
+// indexItem adds the index entries of the item at itemPath
+func (a *Appender) indexItem(itemPath string) error {
+	attrs := make([]string, len(a.indexes))
+	for i, field := range a.indexes {
+		attrs[i] = field.Name
+	}
+
+	resp, err := a.container.GetItemSync(&v3io.GetItemInput{Path: itemPath, AttributeNames: attrs})
+	if err != nil {
+		return errors.Wrapf(err, "can't read indexed attributes of '%s'", itemPath)
+	}
+	item := resp.Output.(*v3io.GetItemOutput).Item
+	resp.Release()
+
+	key := strings.TrimPrefix(itemPath, a.tablePath)
+	for _, field := range a.indexes {
+		if value, ok := item[field.Name]; ok && value != nil {
+			if err := putIndexEntry(a.container, a.tablePath, field, key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// indexCursor returns the items of an index lookup that match the filter
+// predicates. It has the methods of v3ioutils.AsyncItemsCursor the Iterator
+// uses, and its state is always done (index reads have no markers).
+type indexCursor struct {
+	container  v3io.Container
+	tablePath  string
+	keys       []string        // item keys from the index
+	partitions map[string]bool // partitions the items may be in
+	attributes []string        // read from items
+	columns    map[string]bool // returned, nil for all
+	predicates []*partitionPredicate
+	expiredAt  int64 // skip items that expired at this time, 0 for none (see ttl.go)
+
+	partition string
+	fields    map[string]interface{}
+	err       error
+}
+
+// newIndexCursor returns a cursor over the items of an index lookup, or nil
+// if the read can't use an index. Reads can use an index when the filter is
+// a conjunction of simple predicates, one of them an equality on an indexed
+// attribute, and the read is not segmented, resumed or by sharding keys.
+func (kv *Backend) newIndexCursor(container v3io.Container, tablePath string, schema *v3ioutils.OldV3ioSchema,
+	request *frames.ReadRequest, filter *partitionFilter, partitions []string, partitionTypes map[string]string,
+	columns []string) (*indexCursor, error) {
+
+	proto := request.Proto
+	if len(schema.Indexes) == 0 || len(filter.predicates) == 0 || proto.Marker != "" || proto.TotalSegments > 0 ||
+		len(proto.ShardingKeys) > 0 || proto.SortKeyRangeStart != "" || proto.SortKeyRangeEnd != "" {
+		return nil, nil
+	}
+
+	var lookup *partitionPredicate
+	var lookupField v3ioutils.OldSchemaField
+	var predicates []*partitionPredicate
+	for _, predicate := range filter.predicates {
+		if predicate == nil {
+			return nil, nil
+		}
+
+		if dtype, ok := partitionTypes[predicate.column]; ok && predicate.decides(dtype) {
+			continue
+		}
+		predicates = append(predicates, predicate)
+
+		if lookup == nil && predicate.op == "==" && containsString(schema.Indexes, predicate.column) {
+			if field, err := schema.GetField(predicate.column); err == nil {
+				lookup, lookupField = predicate, field
+			}
+		}
+	}
+
+	if lookup == nil {
+		return nil, nil
+	}
+
+	cursor := &indexCursor{
+		container:  container,
+		tablePath:  tablePath,
+		partitions: make(map[string]bool, len(partitions)),
+		predicates: predicates,
+	}
+	for _, partition := range partitions {
+		cursor.partitions[partition] = true
+	}
+	if hidesExpired(request, schema) {
+		cursor.expiredAt = time.Now().UnixNano()
+	}
+
+	if columns[0] != "*" {
+		cursor.columns = make(map[string]bool, len(columns))
+		cursor.attributes = append(cursor.attributes, columns...)
+		for _, column := range columns {
+			cursor.columns[column] = true
+		}
+		for _, predicate := range predicates {
+			if !cursor.columns[predicate.column] {
+				cursor.attributes = append(cursor.attributes, predicate.column)
+			}
+		}
+		if cursor.expiredAt != 0 && !cursor.columns[expiryAttr] {
+			cursor.attributes = append(cursor.attributes, expiryAttr)
+		}
+	} else {
+		cursor.attributes = columns
+	}
+
+	dir, ok := indexValueDir(tablePath, lookupField.Name, lookupField.Type, lookup.values[0])
+	if !ok { // No item can match
+		return cursor, nil
+	}
+
+	input := v3io.GetItemsInput{AttributeNames: []string{indexKeyAttr}}
+	iter, err := v3ioutils.NewAsyncItemsCursor(container, &input, 1, nil, kv.logger, 0, []string{dir}, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for iter.Next() {
+		if key, ok := iter.GetField(indexKeyAttr).(string); ok {
+			cursor.keys = append(cursor.keys, key)
+		}
+	}
+	if err := iter.Err(); err != nil && !isNotFoundError(err) {
+		return nil, errors.Wrapf(err, "can't read index of %s", lookupField.Name)
+	}
+
+	kv.logger.DebugWith("index lookup", "table", tablePath, "attribute", lookupField.Name, "items", len(cursor.keys))
+	return cursor, nil
+}
+
+// Next advances the cursor to the next matching item
+func (ic *indexCursor) Next() bool {
+	for len(ic.keys) > 0 && ic.err == nil {
+		key := ic.keys[0]
+		ic.keys = ic.keys[1:]
+
+		partition := ic.tablePath
+		if dir := path.Dir(key); dir != "." {
+			partition += dir + "/"
+		}
+		if !ic.partitions[partition] {
+			continue
+		}
+
+		resp, err := ic.container.GetItemSync(&v3io.GetItemInput{Path: ic.tablePath + key, AttributeNames: ic.attributes})
+		if err != nil {
+			if !isNotFoundError(err) { // Deleted items are stale entries
+				ic.err = err
+			}
+			continue
+		}
+		item := resp.Output.(*v3io.GetItemOutput).Item
+		resp.Release()
+
+		if !ic.match(item) {
+			continue
+		}
+
+		if ic.columns != nil {
+			for name := range item {
+				if !ic.columns[name] {
+					delete(item, name)
+				}
+			}
+		}
+
+		ic.partition = partition
+		ic.fields = item
+		return true
+	}
+
+	return false
+}
+
+// match returns true if the item passes the predicates
+func (ic *indexCursor) match(item v3io.Item) bool {
+	if ic.expiredAt != 0 && isExpired(item, ic.expiredAt) {
+		return false
+	}
+
+	for _, predicate := range ic.predicates {
+		if !predicate.matchValue(item[predicate.column]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetFields returns the attributes of the current item
+func (ic *indexCursor) GetFields() map[string]interface{} {
+	return ic.fields
+}
+
+// Err returns the last error
+func (ic *indexCursor) Err() error {
+	return ic.err
+}
+
+// State returns a done state
+func (ic *indexCursor) State() *v3ioutils.ItemsCursorState {
+	return &v3ioutils.ItemsCursorState{}
+}
+
+// CurrentPartition returns the partition of the current item
+func (ic *indexCursor) CurrentPartition() string {
+	return ic.partition
+}
+
+// listDirs returns the sub directories of dir
+func listDirs(container v3io.Container, dir string) ([]string, error) {
+	var dirs []string
+	input := &v3io.GetContainerContentsInput{Path: dir, DirectoriesOnly: true}
+	for {
+		resp, err := container.GetContainerContentsSync(input)
+		if err != nil {
+			return nil, err
+		}
+		resp.Release()
+
+		out := resp.Output.(*v3io.GetContainerContentsOutput)
+		for _, prefix := range out.CommonPrefixes {
+			dirs = append(dirs, prefix.Prefix)
+		}
+		if !out.IsTruncated || out.NextMarker == "" {
+			return dirs, nil
+		}
+		input.Marker = out.NextMarker
+	}
+}
+
+// deleteIndex deletes the entries and the directory of the attr index
+func (kv *Backend) deleteIndex(container v3io.Container, tablePath string, attr string) error {
+	dir := indexDir(tablePath, attr)
+	valueDirs, err := listDirs(container, dir)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "can't list index of %s", attr)
+	}
+
+	for _, valueDir := range valueDirs {
+		paths, wait := kv.startDeletes(container)
+		iter, err := v3ioutils.NewFilesCursor(container, &v3io.GetContainerContentsInput{Path: valueDir})
+		if err == nil {
+			for iter.Next() {
+				paths <- iter.GetFilePath()
+			}
+			err = iter.Err()
+		}
+		deleteErr := wait()
+		if err != nil {
+			return errors.Wrapf(err, "can't list index of %s", attr)
+		}
+		if deleteErr != nil {
+			return deleteErr
+		}
+		if err := deletePartitionDir(container, dir, valueDir); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range []string{dir, tablePath + indexDirName} {
+		err := container.DeleteObjectSync(&v3io.DeleteObjectInput{Path: path})
+		if err != nil && !utils.IsNotExistsOrConflictError(err) {
+			return errors.Wrapf(err, "can't delete '%s'", path)
+		}
+	}
+
+	return nil
+}
+
+// buildIndex adds the index entries of all the items of the table, and
+// returns their number
+func (kv *Backend) buildIndex(container v3io.Container, tablePath string, field v3ioutils.OldSchemaField) (int, error) {
+	partitions, err := kv.getPartitions(tablePath, container, nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "can't list partitions of '%s'", tablePath)
+	}
+
+	var keys []string
+	var values []interface{}
+	input := v3io.GetItemsInput{AttributeNames: []string{indexColKey, field.Name}}
+	iter, err := v3ioutils.NewAsyncItemsCursor(container, &input, kv.numWorkers, nil, kv.logger, 0, partitions, "", "")
+	if err != nil {
+		return 0, err
+	}
+	for iter.Next() {
+		name, _ := iter.GetField(indexColKey).(string)
+		value := iter.GetField(field.Name)
+		if name == "" || name == schemaFileName || value == nil {
+			continue
+		}
+
+		keys = append(keys, strings.TrimPrefix(iter.CurrentPartition(), tablePath)+name)
+		values = append(values, value)
+	}
+	if err := iter.Err(); err != nil {
+		return 0, errors.Wrapf(err, "can't read items of '%s'", tablePath)
+	}
+
+	err = kv.forEach(len(keys), func(i int) error {
+		return putIndexEntry(container, tablePath, field, keys[i], values[i])
+	})
+	return len(keys), err
+}
+
+// indexCommand is the create_index, drop_index and rebuild_index exec
+// commands. They get the indexed attribute in the "attribute" argument,
+// rebuild_index rebuilds all the indexes if it's not given. create_index and
+// rebuild_index return a frame with the number of entries of every index.
+func (kv *Backend) indexCommand(request *frames.ExecRequest, cmd string) (frames.Frame, error) {
+	attr := ""
+	if val, ok := request.Proto.Args[indexAttributeArg]; ok {
+		attr = val.GetSval()
+	}
+	if attr == "" && cmd != "rebuild_index" {
+		return nil, fmt.Errorf("missing '%s' argument", indexAttributeArg)
+	}
+
+	container, tablePath, err := kv.newConnection(request.Proto.Session, request.Password.Get(), request.Token.Get(), request.Proto.Table, true)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaInterface, err := v3ioutils.GetSchema(tablePath, container)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't read schema of '%s'", tablePath)
+	}
+	schema := schemaInterface.(*v3ioutils.OldV3ioSchema)
+
+	attrs := schema.Indexes
+	switch cmd {
+	case "create_index":
+		field, err := schema.GetField(attr)
+		if err != nil {
+			return nil, fmt.Errorf("table '%s' has no attribute %s", tablePath, attr)
+		}
+		if attr == schema.Key {
+			return nil, fmt.Errorf("%s is the table key", attr)
+		}
+		if !isIndexable(field.Type) {
+			return nil, fmt.Errorf("can't index %s attributes", field.Type)
+		}
+		if !containsString(schema.Indexes, attr) {
+			schema.Indexes = append(schema.Indexes, attr)
+			if err := schema.Save(container, tablePath); err != nil {
+				return nil, err
+			}
+		}
+		attrs = []string{attr}
+	case "drop_index":
+		if !containsString(schema.Indexes, attr) {
+			return nil, fmt.Errorf("%s is not indexed", attr)
+		}
+		var indexes []string
+		for _, name := range schema.Indexes {
+			if name != attr {
+				indexes = append(indexes, name)
+			}
+		}
+		schema.Indexes = indexes
+		if err := schema.Save(container, tablePath); err != nil {
+			return nil, err
+		}
+		return nil, kv.deleteIndex(container, tablePath, attr)
+	default:
+		if attr != "" {
+			if !containsString(schema.Indexes, attr) {
+				return nil, fmt.Errorf("%s is not indexed", attr)
+			}
+			attrs = []string{attr}
+		}
+	}
+
+	entries := []int64{}
+	for _, name := range attrs {
+		field, err := schema.GetField(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := kv.deleteIndex(container, tablePath, name); err != nil {
+			return nil, err
+		}
+		n, err := kv.buildIndex(container, tablePath, field)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, int64(n))
+	}
+
+	attrCol, err := frames.NewSliceColumn("attribute", append([]string{}, attrs...))
+	if err != nil {
+		return nil, err
+	}
+	entriesCol, err := frames.NewSliceColumn("entries", entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return frames.NewFrame([]frames.Column{attrCol, entriesCol}, nil, nil)
+}
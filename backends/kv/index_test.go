@@ -0,0 +1,189 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+type IndexTestSuite struct {
+	suite.Suite
+	backend *testBackend
+}
+
+func (suite *IndexTestSuite) SetupTest() {
+	suite.backend = newTestBackend(suite.T())
+
+	suite.write([]string{"a", "b", "c", "d"}, []int64{1, 1, 2, 2}, []string{"NY", "LA", "NY", "SF"})
+}
+
+func (suite *IndexTestSuite) write(keys []string, p []int64, cities []string) {
+	keyCol, err := frames.NewSliceColumn("key", keys)
+	suite.Require().NoError(err)
+	pCol, err := frames.NewSliceColumn("p", p)
+	suite.Require().NoError(err)
+	cityCol, err := frames.NewSliceColumn("city", cities)
+	suite.Require().NoError(err)
+	frame, err := frames.NewFrame([]frames.Column{pCol, cityCol}, []frames.Column{keyCol}, nil)
+	suite.Require().NoError(err)
+
+	request := &frames.WriteRequest{
+		Session:       &frames.Session{Container: "bigdata"},
+		Password:      frames.InitSecretString(""),
+		Token:         frames.InitSecretString(""),
+		Table:         "table",
+		ImmidiateData: frame,
+		PartitionKeys: []string{"p"},
+		SaveMode:      frames.OverwriteItem,
+	}
+	appender, err := suite.backend.Write(request)
+	suite.Require().NoError(err)
+	suite.Require().NoError(appender.WaitForComplete(time.Second))
+}
+
+func (suite *IndexTestSuite) exec(command string, attribute string) (frames.Frame, error) {
+	request := &frames.ExecRequest{
+		Proto: &pb.ExecRequest{
+			Session: &frames.Session{Container: "bigdata"},
+			Backend: "kv",
+			Table:   "table",
+			Command: command,
+			Args: map[string]*pb.Value{
+				"attribute": {Value: &pb.Value_Sval{Sval: attribute}},
+			},
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+
+	return suite.backend.Exec(request)
+}
+
+// read returns the keys of the items matching filter
+func (suite *IndexTestSuite) read(filter string) []string {
+	request := &frames.ReadRequest{
+		Proto: &pb.ReadRequest{
+			Session: &frames.Session{Container: "bigdata"},
+			Table:   "table",
+			Filter:  filter,
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+	iter, err := suite.backend.Read(request)
+	suite.Require().NoError(err)
+
+	keys := []string{}
+	for iter.Next() {
+		col := iter.At().Indices()[0]
+		for i := 0; i < col.Len(); i++ {
+			key, err := col.StringAt(i)
+			suite.Require().NoError(err)
+			keys = append(keys, key)
+		}
+	}
+	suite.Require().NoError(iter.Err())
+	sort.Strings(keys)
+	return keys
+}
+
+func (suite *IndexTestSuite) TestCreateIndex() {
+	frame, err := suite.exec("create_index", "city")
+	suite.Require().NoError(err)
+	col, err := frame.Column("entries")
+	suite.Require().NoError(err)
+	entries, err := col.IntAt(0)
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(4), entries)
+
+	schema, err := v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"city"}, schema.(*v3ioutils.OldV3ioSchema).Indexes)
+
+	suite.Require().Equal([]string{"a", "c"}, suite.read("city == 'NY'"))
+	suite.Require().Equal([]string{"c"}, suite.read("city == 'NY' AND p == 2"))
+	suite.Require().Equal([]string{}, suite.read("city == 'Paris'"))
+
+	// Only items in the index are read, putItem doesn't maintain the indexes
+	suite.backend.putItem("table/p=2/x", map[string]interface{}{"key": "x", "p": 2, "city": "NY"})
+	suite.Require().Equal([]string{"a", "c"}, suite.read("city == 'NY'"))
+	suite.Require().Equal([]string{"a", "c", "x"}, suite.read("city == 'NY' OR city == 'none'"))
+
+	_, err = suite.exec("rebuild_index", "")
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"a", "c", "x"}, suite.read("city == 'NY'"))
+}
+
+func (suite *IndexTestSuite) TestWriteMaintainsIndex() {
+	_, err := suite.exec("create_index", "city")
+	suite.Require().NoError(err)
+
+	suite.write([]string{"a", "e"}, []int64{1, 2}, []string{"LA", "NY"})
+	suite.Require().Equal([]string{"a", "b"}, suite.read("city == 'LA'"))
+	// The stale entry of a is skipped
+	suite.Require().Equal([]string{"c", "e"}, suite.read("city == 'NY'"))
+}
+
+func (suite *IndexTestSuite) TestDropIndex() {
+	_, err := suite.exec("create_index", "city")
+	suite.Require().NoError(err)
+	_, err = suite.exec("drop_index", "city")
+	suite.Require().NoError(err)
+
+	schema, err := v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().NoError(err)
+	suite.Require().Empty(schema.(*v3ioutils.OldV3ioSchema).Indexes)
+	suite.Require().False(suite.backend.itemExists("table/.#index/"))
+	suite.Require().Equal([]string{"a", "c"}, suite.read("city == 'NY'"))
+}
+
+func (suite *IndexTestSuite) TestDeleteTable() {
+	_, err := suite.exec("create_index", "city")
+	suite.Require().NoError(err)
+
+	request := &frames.DeleteRequest{
+		Proto:    &pb.DeleteRequest{Session: &frames.Session{Container: "bigdata"}, Table: "table"},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+	suite.Require().NoError(suite.backend.Delete(request))
+	suite.Require().False(suite.backend.itemExists("table/"))
+}
+
+func (suite *IndexTestSuite) TestBadIndex() {
+	_, err := suite.exec("create_index", "nope")
+	suite.Require().Error(err)
+	_, err = suite.exec("create_index", "")
+	suite.Require().Error(err)
+	_, err = suite.exec("rebuild_index", "city")
+	suite.Require().Error(err)
+}
+
+func TestIndexTestSuite(t *testing.T) {
+	suite.Run(t, new(IndexTestSuite))
+}
@@ -26,6 +26,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/v3io/frames"
@@ -39,48 +40,206 @@ var (
 	hashedBucketFormat = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*_[0-9]+$")
 )
 
-func (b *Backend) inferSchema(request *frames.ExecRequest) error {
+// inferSchema infers the table schema from items sampled from every
+// partition, and returns a frame with the inferred type, observed types and
+// null rate of every attribute. Optional arguments are "key", "samples"
+// (items per partition segment), "segments" (per partition) and "dry_run"
+// (don't write the schema).
+func (b *Backend) inferSchema(request *frames.ExecRequest) (frames.Frame, error) {
+	var keyField string
+	if val, ok := request.Proto.Args["key"]; ok {
+		keyField = val.GetSval()
+	}
+
+	samples := int64(b.maxRecordsInfer)
+	if val, ok := request.Proto.Args["samples"]; ok {
+		if samples = val.GetIval(); samples <= 0 {
+			return nil, fmt.Errorf("samples must be positive")
+		}
+	}
+
+	segments := int64(1)
+	if val, ok := request.Proto.Args["segments"]; ok {
+		if segments = val.GetIval(); segments <= 0 {
+			return nil, fmt.Errorf("segments must be positive")
+		}
+	}
+
+	dryRun := false
+	if val, ok := request.Proto.Args["dry_run"]; ok {
+		dryRun = val.GetBval()
+	}
 
 	container, table, err := b.newConnection(request.Proto.Session, request.Password.Get(), request.Token.Get(), request.Proto.Table, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var keyField string
-	if val, ok := request.Proto.Args["key"]; ok {
-		keyField = val.GetSval()
+	partitions, err := b.getPartitions(table, container, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rowSet, err := b.sampleItems(container, partitions, int(segments), int(samples))
+	if err != nil {
+		return nil, err
 	}
 
-	input := v3io.GetItemsInput{Path: table, Filter: "", AttributeNames: []string{"*"}}
-	b.logger.DebugWith("GetItems for schema", "input", input)
-	iter, err := v3ioutils.NewAsyncItemsCursor(container, &input, b.numWorkers, []string{}, b.logger, b.maxRecordsInfer, []string{table}, "", "")
+	newSchema, conflicts, err := inferSchemaFromRows(keyField, rowSet)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	if !dryRun {
+		if len(conflicts) > 0 {
+			return nil, errors.Errorf("can't infer the schema of table '%s': %s", table, strings.Join(conflicts, " "))
+		}
+
+		// Inferred fields are merged into the table schema, keeping its
+		// indexes, mode and time formats
+		oldSchema, err := v3ioutils.GetSchema(table, container)
+		if err != nil {
+			if !isNotFoundError(err) {
+				return nil, err
+			}
+			oldSchema = v3ioutils.NewSchema(keyField, "")
+		}
+		if err := oldSchema.UpdateSchema(container, table, newSchema); err != nil {
+			return nil, err
+		}
+	}
+
+	return inferReport(newSchema.(*v3ioutils.OldV3ioSchema), rowSet)
+}
+
+// sampleItems reads up to samples items from every segment of every partition
+func (b *Backend) sampleItems(container v3io.Container, partitions []string, segments int, samples int) ([]map[string]interface{}, error) {
+	var lock sync.Mutex
 	var rowSet []map[string]interface{}
 
-	for rowNum := 0; iter.Next(); rowNum++ {
-		row := iter.GetFields()
-		rowSet = append(rowSet, row)
+	err := b.forEach(len(partitions)*segments, func(i int) error {
+		state := &v3ioutils.ItemsCursorState{
+			TotalSegments: segments,
+			Streams:       []v3ioutils.ItemsStreamState{{Partition: partitions[i/segments], Segment: i % segments}},
+		}
+		input := v3io.GetItemsInput{AttributeNames: []string{"*"}}
+		b.logger.DebugWith("GetItems for schema", "partition", partitions[i/segments], "segment", i%segments)
+		iter, err := v3ioutils.NewAsyncItemsCursorFromState(container, &input, state, 1, b.logger, samples, "", "")
+		if err != nil {
+			return err
+		}
+
+		var rows []map[string]interface{}
+		for iter.Next() {
+			if row := iter.GetFields(); row["__name"] != ".#schema" {
+				rows = append(rows, row)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return errors.Wrapf(err, "can't sample items of '%s'", partitions[i/segments])
+		}
+
+		lock.Lock()
+		rowSet = append(rowSet, rows...)
+		lock.Unlock()
+		return nil
+	})
+
+	return rowSet, err
+}
+
+// inferReport returns a frame with the schema type, observed types, type
+// conflict, null rate (of the sampled items without the attribute) and key
+// role of every sampled attribute
+func inferReport(schema *v3ioutils.OldV3ioSchema, rowSet []map[string]interface{}) (frames.Frame, error) {
+	observed := make(map[string]map[string]bool)
+	counts := make(map[string]int)
+	for _, row := range rowSet {
+		for name, value := range row {
+			if name == "__name" {
+				continue
+			}
+			if observed[name] == nil {
+				observed[name] = make(map[string]bool)
+			}
+			observed[name][valueType(value)] = true
+			counts[name]++
+		}
 	}
 
-	if iter.Err() != nil {
-		return iter.Err()
+	var names []string
+	for name := range observed {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	newSchema, err := schemaFromKeys(keyField, rowSet)
-	if err != nil {
-		return err
+	var fieldTypes, observedTypes, roles []string
+	var conflicts []bool
+	var nullRates []float64
+	for _, name := range names {
+		field, err := schema.GetField(name)
+		fieldTypes = append(fieldTypes, field.Type)
+		conflicts = append(conflicts, err != nil)
+
+		var types []string
+		for dtype := range observed[name] {
+			types = append(types, dtype)
+		}
+		sort.Strings(types)
+		observedTypes = append(observedTypes, strings.Join(types, ","))
+		nullRates = append(nullRates, float64(len(rowSet)-counts[name])/float64(len(rowSet)))
+
+		role := ""
+		switch name {
+		case schema.Key:
+			role = "key"
+		case schema.SortingKey:
+			role = "sorting_key"
+		}
+		roles = append(roles, role)
 	}
 
-	nullSchema := v3ioutils.NewSchema(keyField, "")
+	var columns []frames.Column
+	for _, data := range []struct {
+		name   string
+		values interface{}
+	}{
+		{"field", names},
+		{"type", fieldTypes},
+		{"observed_types", observedTypes},
+		{"conflict", conflicts},
+		{"null_rate", nullRates},
+		{"role", roles},
+	} {
+		col, err := frames.NewSliceColumn(data.name, data.values)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
 
-	return nullSchema.UpdateSchema(container, table, newSchema)
+	return frames.NewFrame(columns, nil, nil)
 }
 
+// schemaFromKeys infers a schema from items, it fails if attributes have
+// values of different types
 func schemaFromKeys(keyField string, rowSet []map[string]interface{}) (v3ioutils.V3ioSchema, error) {
+	schema, conflicts, err := inferSchemaFromRows(keyField, rowSet)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, errors.New(strings.Join(conflicts, " "))
+	}
+
+	return schema, nil
+}
+
+// inferSchemaFromRows infers a schema from items, attributes with values of
+// different types are left out of the schema and described in conflicts
+func inferSchemaFromRows(keyField string, rowSet []map[string]interface{}) (v3ioutils.V3ioSchema, []string, error) {
 	columnNameToValue := make(map[string]interface{})
+	conflicts := make(map[string]string)
 	columnCanBeFullKey := make(map[string]bool)
 	columnCanBePrimaryKey := make(map[string]bool)
 	columnCanBeSortingKey := make(map[string]bool)
@@ -116,7 +275,10 @@ func schemaFromKeys(keyField string, rowSet []map[string]interface{}) (v3ioutils
 					} else if previousType == intType && currentType == floatType {
 						// continue to set the `columnNameToValue` to float
 					} else {
-						return nil, errors.Errorf("type '%v' of value '%v' doesn't match type '%v' of value '%v' for column '%s'.", previousType, previousValue, currentType, attrValue, attrName)
+						if _, ok := conflicts[attrName]; !ok {
+							conflicts[attrName] = fmt.Sprintf("type '%v' of value '%v' doesn't match type '%v' of value '%v' for column '%s'.", previousType, previousValue, currentType, attrValue, attrName)
+						}
+						continue
 					}
 				}
 			}
@@ -148,6 +310,14 @@ func schemaFromKeys(keyField string, rowSet []map[string]interface{}) (v3ioutils
 		}
 	}
 
+	// Attributes with conflicting types can't be keys
+	for name := range conflicts {
+		delete(columnCanBeFullKey, name)
+		delete(columnCanBePrimaryKey, name)
+		delete(columnCanBeSortingKey, name)
+		delete(columnCanBeHashedPrimaryKey, name)
+	}
+
 	var primaryKeyField string
 	var sortingKeyField string
 	var hashingBuckets int
@@ -181,24 +351,37 @@ func schemaFromKeys(keyField string, rowSet []map[string]interface{}) (v3ioutils
 				sort.Strings(possibleFullKeys)
 				reason = fmt.Sprintf("%d columns (%s) match the primary-key attribute", len(possibleFullKeys), strings.Join(possibleFullKeys, ", "))
 			}
-			return nil, errors.Errorf("could not determine which column is the table's primary-key attribute, because %s", reason)
+			return nil, nil, errors.Errorf("could not determine which column is the table's primary-key attribute, because %s", reason)
 		}
 	} else {
 		if val, ok := columnCanBeFullKey[keyField]; !ok || !val {
-			return nil, errors.Errorf("%s is not one of the optional key columns", keyField)
+			return nil, nil, errors.Errorf("%s is not one of the optional key columns", keyField)
 		}
 	}
 
 	newSchema := v3ioutils.NewSchemaWithHashingBuckets(keyField, sortingKeyField, hashingBuckets)
 
 	for name, value := range columnNameToValue {
+		if _, ok := conflicts[name]; ok {
+			continue
+		}
 		err := newSchema.AddField(name, value, name != keyField && name != sortingKeyField)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
-	return newSchema, nil
+	conflictNames := make([]string, 0, len(conflicts))
+	for name := range conflicts {
+		conflictNames = append(conflictNames, name)
+	}
+	sort.Strings(conflictNames)
+	conflictMessages := make([]string, 0, len(conflicts))
+	for _, name := range conflictNames {
+		conflictMessages = append(conflictMessages, conflicts[name])
+	}
+
+	return newSchema, conflictMessages, nil
 }
 
 func filterOutFalse(m map[string]bool) []string {
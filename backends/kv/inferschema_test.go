@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
 	"github.com/v3io/frames/v3ioutils"
 )
 
@@ -274,3 +276,156 @@ func (suite *InferSchemaTestSuite) TestInferSchemaWithInvalidKey() {
 func TestInferSchemaTestSuite(t *testing.T) {
 	suite.Run(t, new(InferSchemaTestSuite))
 }
+
+type InferSchemaExecTestSuite struct {
+	suite.Suite
+	backend *testBackend
+}
+
+func (suite *InferSchemaExecTestSuite) SetupTest() {
+	suite.backend = newTestBackend(suite.T())
+	suite.backend.maxRecordsInfer = 10
+
+	suite.backend.putItem("table/p=1/a", map[string]interface{}{"key": "a", "p": 1, "v": 1, "x": "one"})
+	suite.backend.putItem("table/p=1/b", map[string]interface{}{"key": "b", "p": 1, "v": 2, "x": "two"})
+	suite.backend.putItem("table/p=2/c", map[string]interface{}{"key": "c", "p": 2, "v": 2.5, "x": "three", "c": "new"})
+	suite.backend.putItem("table/p=2/d", map[string]interface{}{"key": "d", "p": 2, "v": 3.5, "x": "four", "c": "new"})
+}
+
+func (suite *InferSchemaExecTestSuite) infer(args map[string]interface{}) (frames.Frame, error) {
+	pbArgs := make(map[string]*pb.Value)
+	for name, value := range args {
+		pbValue := &pb.Value{}
+		suite.Require().NoError(pbValue.SetValue(value))
+		pbArgs[name] = pbValue
+	}
+
+	request := &frames.ExecRequest{
+		Proto: &pb.ExecRequest{
+			Session: &frames.Session{Container: "bigdata"},
+			Backend: "kv",
+			Table:   "table",
+			Command: "infer",
+			Args:    pbArgs,
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+
+	return suite.backend.Exec(request)
+}
+
+// report returns the report rows by field
+func (suite *InferSchemaExecTestSuite) report(frame frames.Frame) map[string]map[string]interface{} {
+	rows := make(map[string]map[string]interface{})
+	for i := 0; i < frame.Len(); i++ {
+		row := make(map[string]interface{})
+		for _, name := range frame.Names() {
+			col, err := frame.Column(name)
+			suite.Require().NoError(err)
+			switch col.DType() {
+			case frames.StringType:
+				row[name], err = col.StringAt(i)
+			case frames.FloatType:
+				row[name], err = col.FloatAt(i)
+			case frames.BoolType:
+				row[name], err = col.BoolAt(i)
+			}
+			suite.Require().NoError(err)
+		}
+		rows[row["field"].(string)] = row
+	}
+	return rows
+}
+
+func (suite *InferSchemaExecTestSuite) TestPartitions() {
+	frame, err := suite.infer(map[string]interface{}{"key": "key"})
+	suite.Require().NoError(err)
+
+	report := suite.report(frame)
+	suite.Require().Len(report, 5)
+	suite.Require().Equal("double", report["v"]["type"])
+	suite.Require().Equal("double,long", report["v"]["observed_types"])
+	suite.Require().Equal(0.5, report["c"]["null_rate"])
+	suite.Require().Equal(0.0, report["x"]["null_rate"])
+	suite.Require().Equal("key", report["key"]["role"])
+
+	schema, err := v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().NoError(err)
+	field, err := schema.(*v3ioutils.OldV3ioSchema).GetField("c")
+	suite.Require().NoError(err)
+	suite.Require().Equal("string", field.Type)
+}
+
+func (suite *InferSchemaExecTestSuite) TestSamples() {
+	frame, err := suite.infer(map[string]interface{}{"key": "key", "samples": 1, "dry_run": true})
+	suite.Require().NoError(err)
+	// One item of every partition
+	suite.Require().Equal(0.5, suite.report(frame)["c"]["null_rate"])
+
+	rows, err := suite.backend.sampleItems(suite.backend.container, []string{"table/p=1/", "table/p=2/"}, 1, 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 2)
+
+	_, err = suite.infer(map[string]interface{}{"samples": 0})
+	suite.Require().Error(err)
+
+	frame, err = suite.infer(map[string]interface{}{"key": "key", "segments": 4, "dry_run": true})
+	suite.Require().NoError(err)
+	suite.Require().Equal(0.5, suite.report(frame)["c"]["null_rate"])
+}
+
+func (suite *InferSchemaExecTestSuite) TestConflictsDryRun() {
+	suite.backend.putItem("table/p=2/e", map[string]interface{}{"key": "e", "p": 2, "v": 4.5, "x": true})
+
+	frame, err := suite.infer(map[string]interface{}{"key": "key", "dry_run": true})
+	suite.Require().NoError(err)
+	report := suite.report(frame)
+	suite.Require().Equal(true, report["x"]["conflict"])
+	suite.Require().Equal("", report["x"]["type"])
+	suite.Require().Equal("boolean,string", report["x"]["observed_types"])
+	suite.Require().Equal(false, report["v"]["conflict"])
+
+	// Dry runs don't write the schema
+	_, err = v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().Error(err)
+
+	_, err = suite.infer(map[string]interface{}{"key": "key"})
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "column 'x'")
+	_, err = v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().Error(err)
+}
+
+func (suite *InferSchemaExecTestSuite) TestReinferKeepsSchema() {
+	_, err := suite.infer(map[string]interface{}{"key": "key"})
+	suite.Require().NoError(err)
+
+	schemaObj, err := v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().NoError(err)
+	schema := schemaObj.(*v3ioutils.OldV3ioSchema)
+	schema.Indexes = []string{"x"}
+	schema.Mode = frames.SchemaModeStrict
+	schema.Fields = append(schema.Fields, v3ioutils.OldSchemaField{Name: "ts", Type: v3ioutils.TimeType, Nullable: true, TimeZone: "UTC", Precision: "ms"})
+	suite.Require().NoError(schema.Save(suite.backend.container, "table/"))
+
+	suite.backend.putItem("table/p=1/e", map[string]interface{}{"key": "e", "p": 1, "v": 4, "x": "five", "added": "yes"})
+	_, err = suite.infer(map[string]interface{}{"key": "key"})
+	suite.Require().NoError(err)
+
+	schemaObj, err = v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().NoError(err)
+	schema = schemaObj.(*v3ioutils.OldV3ioSchema)
+	suite.Require().Equal([]string{"x"}, schema.Indexes)
+	suite.Require().Equal(frames.SchemaModeStrict, schema.Mode)
+	field, err := schema.GetField("ts")
+	suite.Require().NoError(err)
+	suite.Require().Equal("UTC", field.TimeZone)
+	suite.Require().Equal("ms", field.Precision)
+	_, err = schema.GetField("added")
+	suite.Require().NoError(err)
+}
+
+func TestInferSchemaExecTestSuite(t *testing.T) {
+	suite.Run(t, new(InferSchemaExecTestSuite))
+}
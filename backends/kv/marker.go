@@ -0,0 +1,103 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames/pb"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+const readMarkerVersion = 1
+
+// readMarker is the state of a KV read, passed to clients in the frames
+// MarkerLabel and back in ReadRequest.Marker to resume the read
+type readMarker struct {
+	Version int                         `json:"v"`
+	Query   uint64                      `json:"q"` // hash of the request fields that select items
+	Cursor  *v3ioutils.ItemsCursorState `json:"c"`
+}
+
+// readQueryHash returns a hash of the read request fields that select items,
+// a marker can be used only with the same query
+func readQueryHash(request *pb.ReadRequest) uint64 {
+	hash := fnv.New64a()
+	parts := []string{
+		request.GetSession().GetContainer(),
+		request.Table,
+		request.Filter,
+		strings.Join(request.ShardingKeys, ","),
+		request.SortKeyRangeStart,
+		request.SortKeyRangeEnd,
+		fmt.Sprint(request.TotalSegments, request.Segments),
+	}
+
+	_, _ = hash.Write([]byte(strings.Join(parts, "\x00")))
+
+	return hash.Sum64()
+}
+
+func encodeReadMarker(request *pb.ReadRequest, cursor *v3ioutils.ItemsCursorState) (string, error) {
+	marker := &readMarker{
+		Version: readMarkerVersion,
+		Query:   readQueryHash(request),
+		Cursor:  cursor,
+	}
+
+	data, err := json.Marshal(marker)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeReadMarker(request *pb.ReadRequest) (*v3ioutils.ItemsCursorState, error) {
+	data, err := base64.RawURLEncoding.DecodeString(request.Marker)
+	if err != nil {
+		return nil, errors.Wrap(err, "bad marker encoding")
+	}
+
+	marker := &readMarker{}
+	if err := json.Unmarshal(data, marker); err != nil {
+		return nil, errors.Wrap(err, "bad marker")
+	}
+
+	if marker.Version != readMarkerVersion {
+		return nil, errors.Errorf("unsupported marker version - %d", marker.Version)
+	}
+
+	if marker.Cursor == nil {
+		return nil, errors.New("bad marker - missing cursor state")
+	}
+
+	if marker.Query != readQueryHash(request) {
+		return nil, errors.New("marker doesn't match the read request (table, filter, sharding keys, sort key range and segments must not change)")
+	}
+
+	return marker.Cursor, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames/pb"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+type MarkerTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MarkerTestSuite) TestRoundTrip() {
+	request := &pb.ReadRequest{Table: "mytable", Filter: "age > 3"}
+	state := &v3ioutils.ItemsCursorState{
+		TotalSegments: 2,
+		Streams: []v3ioutils.ItemsStreamState{
+			{Partition: "mytable/p=1/", Segment: 0, Marker: "abc", Skip: 3},
+			{Partition: "mytable/p=1/", Segment: 1, Done: true},
+		},
+	}
+
+	marker, err := encodeReadMarker(request, state)
+	suite.Require().NoError(err)
+
+	request.Marker = marker
+	decoded, err := decodeReadMarker(request)
+	suite.Require().NoError(err)
+	suite.Require().Equal(state, decoded)
+}
+
+func (suite *MarkerTestSuite) TestMismatch() {
+	request := &pb.ReadRequest{Table: "mytable", Filter: "age > 3"}
+	marker, err := encodeReadMarker(request, &v3ioutils.ItemsCursorState{})
+	suite.Require().NoError(err)
+
+	other := &pb.ReadRequest{Table: "mytable", Filter: "age > 4", Marker: marker}
+	_, err = decodeReadMarker(other)
+	suite.Require().Error(err)
+
+	other = &pb.ReadRequest{Table: "mytable", Marker: "not a marker"}
+	_, err = decodeReadMarker(other)
+	suite.Require().Error(err)
+}
+
+func TestMarkerTestSuite(t *testing.T) {
+	suite.Run(t, new(MarkerTestSuite))
+}
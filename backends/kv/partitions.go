@@ -0,0 +1,549 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/v3io/frames/v3ioutils"
+)
+
+// Partitioned tables (see WriteRequest.PartitionKeys) keep items under
+// col=value/ directories. The reader prunes partitions using the filter
+// predicates on the partition columns, and fills the partition columns from
+// the directory names for items that don't have them as attributes.
+
+const nullPartitionValue = "null"
+
+// filterToken is a token of a read filter
+type filterToken struct {
+	kind       filterTokenKind
+	text       string
+	start, end int // offsets in the filter
+}
+
+type filterTokenKind int
+
+const (
+	identToken filterTokenKind = iota
+	numberToken
+	stringToken
+	opToken
+	lparenToken
+	rparenToken
+	commaToken
+)
+
+// tokenizeFilter splits a filter to tokens, it returns false on anything it
+// doesn't understand
+func tokenizeFilter(filter string) ([]filterToken, bool) {
+	var tokens []filterToken
+	for i := 0; i < len(filter); {
+		c := rune(filter[i])
+		start := i
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c == '(':
+			i++
+			tokens = append(tokens, filterToken{lparenToken, "(", start, i})
+		case c == ')':
+			i++
+			tokens = append(tokens, filterToken{rparenToken, ")", start, i})
+		case c == ',':
+			i++
+			tokens = append(tokens, filterToken{commaToken, ",", start, i})
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(filter[i+1:], filter[i])
+			if end == -1 {
+				return nil, false
+			}
+			i += end + 2
+			tokens = append(tokens, filterToken{stringToken, filter[start+1 : i-1], start, i})
+		case strings.ContainsRune("=!<>&|", c):
+			for i < len(filter) && strings.ContainsRune("=!<>&|", rune(filter[i])) {
+				i++
+			}
+			tokens = append(tokens, filterToken{opToken, filter[start:i], start, i})
+		case unicode.IsDigit(c) || c == '-' || c == '.':
+			for i < len(filter) && strings.ContainsRune("0123456789.eE+-", rune(filter[i])) {
+				i++
+			}
+			tokens = append(tokens, filterToken{numberToken, filter[start:i], start, i})
+		case unicode.IsLetter(c) || c == '_':
+			for i < len(filter) && (unicode.IsLetter(rune(filter[i])) || unicode.IsDigit(rune(filter[i])) || filter[i] == '_' || filter[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{identToken, filter[start:i], start, i})
+		default:
+			return nil, false
+		}
+	}
+
+	return tokens, true
+}
+
+func isKeyword(token filterToken, keyword string) bool {
+	return token.kind == identToken && strings.EqualFold(token.text, keyword)
+}
+
+// partitionPredicate is a simple condition on a single column
+type partitionPredicate struct {
+	column string
+	op     string        // ==, <, <=, >, >= or in
+	values []interface{} // int64, float64, bool or string
+}
+
+// partitionFilter is a read filter split to its top level AND conjuncts
+type partitionFilter struct {
+	filter     string
+	conjuncts  [][2]int              // start and end offsets of the conjuncts
+	predicates []*partitionPredicate // predicate of every conjunct, nil if it's not a simple one
+}
+
+// parsePartitionFilter parses the predicates of a filter. Filters with a top
+// level OR, or that can't be parsed, don't have predicates.
+func parsePartitionFilter(filter string) *partitionFilter {
+	pf := &partitionFilter{filter: filter}
+	tokens, ok := tokenizeFilter(filter)
+	if !ok || len(tokens) == 0 {
+		return pf
+	}
+
+	depth, start := 0, 0
+	var conjuncts [][]filterToken
+	for i, token := range tokens {
+		switch {
+		case token.kind == lparenToken:
+			depth++
+		case token.kind == rparenToken:
+			depth--
+		case depth == 0 && (isKeyword(token, "or") || token.text == "||"):
+			return pf
+		case depth == 0 && (isKeyword(token, "and") || token.text == "&&"):
+			conjuncts = append(conjuncts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	conjuncts = append(conjuncts, tokens[start:])
+
+	for _, conjunct := range conjuncts {
+		if len(conjunct) == 0 { // Malformed
+			return &partitionFilter{filter: filter}
+		}
+		pf.conjuncts = append(pf.conjuncts, [2]int{conjunct[0].start, conjunct[len(conjunct)-1].end})
+		pf.predicates = append(pf.predicates, parsePredicate(conjunct))
+	}
+
+	return pf
+}
+
+// parsePredicate parses "col op value", "value op col" and
+// "col IN (value, ...)", it returns nil for anything else
+func parsePredicate(tokens []filterToken) *partitionPredicate {
+	for len(tokens) > 2 && tokens[0].kind == lparenToken && tokens[len(tokens)-1].kind == rparenToken {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	if len(tokens) == 3 && tokens[1].kind == opToken {
+		op := tokens[1].text
+		if op == "=" {
+			op = "=="
+		}
+
+		switch op {
+		case "==", "<", "<=", ">", ">=":
+		default:
+			return nil
+		}
+
+		column, literal := tokens[0], tokens[2]
+		if column.kind != identToken || isLiteral(column) {
+			column, literal = literal, column
+			op = flipOp(op)
+		}
+
+		value, ok := literalValue(literal)
+		if column.kind != identToken || isLiteral(column) || !ok {
+			return nil
+		}
+
+		return &partitionPredicate{column: column.text, op: op, values: []interface{}{value}}
+	}
+
+	if len(tokens) < 5 || tokens[0].kind != identToken || !isKeyword(tokens[1], "in") ||
+		tokens[2].kind != lparenToken || tokens[len(tokens)-1].kind != rparenToken {
+		return nil
+	}
+
+	predicate := &partitionPredicate{column: tokens[0].text, op: "in"}
+	for i, token := range tokens[3 : len(tokens)-1] {
+		if i%2 == 1 {
+			if token.kind != commaToken {
+				return nil
+			}
+			continue
+		}
+
+		value, ok := literalValue(token)
+		if !ok {
+			return nil
+		}
+		predicate.values = append(predicate.values, value)
+	}
+
+	return predicate
+}
+
+func flipOp(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	}
+
+	return op
+}
+
+func isLiteral(token filterToken) bool {
+	return isKeyword(token, "true") || isKeyword(token, "false")
+}
+
+func literalValue(token filterToken) (interface{}, bool) {
+	switch token.kind {
+	case stringToken:
+		return token.text, true
+	case numberToken:
+		if i, err := strconv.ParseInt(token.text, 10, 64); err == nil {
+			return i, true
+		}
+		f, err := strconv.ParseFloat(token.text, 64)
+		return f, err == nil
+	case identToken:
+		if isLiteral(token) {
+			return strings.EqualFold(token.text, "true"), true
+		}
+	}
+
+	return nil, false
+}
+
+// match returns false if value of a partition column fails one of the
+// predicates on the column
+func (pf *partitionFilter) match(column string, value string) bool {
+	for _, predicate := range pf.predicates {
+		if predicate != nil && predicate.column == column && !predicate.match(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *partitionPredicate) match(value string) bool {
+	if value == nullPartitionValue {
+		return false
+	}
+
+	return p.test(value)
+}
+
+// matchValue returns true if an item attribute value passes the predicate,
+// missing (nil) values don't pass any predicate
+func (p *partitionPredicate) matchValue(value interface{}) bool {
+	switch value := value.(type) {
+	case string:
+		return p.test(value)
+	case int, int64, bool:
+		return p.test(fmt.Sprint(value))
+	case float64:
+		return p.test(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+
+	return false
+}
+
+// test compares value to the predicate values
+func (p *partitionPredicate) test(value string) bool {
+	if p.op == "in" {
+		for _, literal := range p.values {
+			if cmp, ok := comparePartitionValue(value, literal); ok && cmp == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	cmp, ok := comparePartitionValue(value, p.values[0])
+	if !ok {
+		return false
+	}
+
+	switch p.op {
+	case "==":
+		return cmp == 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+
+	return false
+}
+
+// comparePartitionValue compares a directory value to a literal, it returns
+// false if they can't be compared
+func comparePartitionValue(value string, literal interface{}) (int, bool) {
+	switch literal := literal.(type) {
+	case int64:
+		return compareFloats(value, float64(literal))
+	case float64:
+		return compareFloats(value, literal)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil || b != literal {
+			return 1, err == nil
+		}
+		return 0, true
+	case string:
+		return strings.Compare(value, literal), true
+	}
+
+	return 0, false
+}
+
+func compareFloats(value string, literal float64) (int, bool) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	switch {
+	case f < literal:
+		return -1, true
+	case f > literal:
+		return 1, true
+	}
+	return 0, true
+}
+
+// residual returns the filter without the conjuncts that were decided by
+// partition pruning
+func (pf *partitionFilter) residual(partitionColumns map[string]string) string {
+	if len(pf.conjuncts) == 0 {
+		return pf.filter
+	}
+
+	var parts []string
+	for i, conjunct := range pf.conjuncts {
+		predicate := pf.predicates[i]
+		if predicate != nil {
+			if dtype, ok := partitionColumns[predicate.column]; ok && predicate.decides(dtype) {
+				continue
+			}
+		}
+		parts = append(parts, "("+strings.TrimSpace(pf.filter[conjunct[0]:conjunct[1]])+")")
+	}
+
+	return strings.Join(parts, " AND ")
+}
+
+// decides returns true if pruning partitions of a column with schema type
+// dtype gives the same result as the predicate on the items
+func (p *partitionPredicate) decides(dtype string) bool {
+	for _, value := range p.values {
+		switch value.(type) {
+		case int64, float64:
+			if dtype != v3ioutils.LongType && dtype != v3ioutils.DoubleType {
+				return false
+			}
+		case bool:
+			if dtype != v3ioutils.BoolType {
+				return false
+			}
+		case string:
+			if dtype != v3ioutils.StringType {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// partitionPathValues returns the column=value pairs of a partition path
+// under tablePath
+func partitionPathValues(tablePath string, partition string) [][2]string {
+	var values [][2]string
+	for _, part := range strings.Split(strings.TrimPrefix(partition, tablePath), "/") {
+		if i := strings.Index(part, "="); i > 0 {
+			values = append(values, [2]string{part[:i], part[i+1:]})
+		}
+	}
+
+	return values
+}
+
+// partitionColumns returns the columns that all partitions have, with their
+// schema type. Types of columns that aren't in the schema are inferred from
+// the partition values.
+func partitionColumns(tablePath string, partitions []string, schema *v3ioutils.OldV3ioSchema) ([]string, map[string]string) {
+	if len(partitions) == 0 {
+		return nil, nil
+	}
+
+	var names []string
+	counts := make(map[string]int)
+	values := make(map[string][]string)
+	for _, partition := range partitions {
+		for _, pair := range partitionPathValues(tablePath, partition) {
+			if counts[pair[0]] == 0 {
+				names = append(names, pair[0])
+			}
+			counts[pair[0]]++
+			values[pair[0]] = append(values[pair[0]], pair[1])
+		}
+	}
+
+	var columns []string
+	types := make(map[string]string)
+	for _, name := range names {
+		if counts[name] != len(partitions) {
+			continue
+		}
+
+		columns = append(columns, name)
+		if field, err := schema.GetField(name); err == nil {
+			types[name] = field.Type
+		} else {
+			types[name] = inferPartitionType(values[name])
+		}
+	}
+
+	return columns, types
+}
+
+// inferPartitionType returns the schema type that all partition values
+// parse as
+func inferPartitionType(values []string) string {
+	for _, dtype := range []string{v3ioutils.LongType, v3ioutils.DoubleType, v3ioutils.BoolType} {
+		ok := true
+		for _, value := range values {
+			if _, err := parsePartitionValue(value, dtype); err != nil {
+				ok = false
+				break
+			}
+		}
+
+		if ok {
+			return dtype
+		}
+	}
+
+	return v3ioutils.StringType
+}
+
+// parsePartitionValue parses a directory value by schema type, nil for null
+// partitions
+func parsePartitionValue(value string, dtype string) (interface{}, error) {
+	if value == nullPartitionValue {
+		return nil, nil
+	}
+
+	switch dtype {
+	case v3ioutils.LongType:
+		return strconv.ParseInt(value, 10, 64)
+	case v3ioutils.DoubleType:
+		return strconv.ParseFloat(value, 64)
+	case v3ioutils.BoolType:
+		return strconv.ParseBool(value)
+	case v3ioutils.StringType:
+		return value, nil
+	}
+
+	return nil, strconv.ErrSyntax
+}
+
+// matchDir returns false if the partition directory (col=value/) fails the
+// filter predicates
+func (pf *partitionFilter) matchDir(dir string) bool {
+	if pf == nil {
+		return true
+	}
+
+	dir = strings.TrimSuffix(dir, "/")
+	if i := strings.LastIndex(dir, "/"); i != -1 {
+		dir = dir[i+1:]
+	}
+
+	i := strings.Index(dir, "=")
+	if i <= 0 {
+		return true
+	}
+
+	return pf.match(dir[:i], dir[i+1:])
+}
+
+// statePartitions returns the partitions of a cursor state
+func statePartitions(state *v3ioutils.ItemsCursorState) []string {
+	var partitions []string
+	seen := make(map[string]bool)
+	for _, stream := range state.Streams {
+		if !seen[stream.Partition] {
+			seen[stream.Partition] = true
+			partitions = append(partitions, stream.Partition)
+		}
+	}
+
+	return partitions
+}
+
+// schemaWithPartitionColumns returns schema with the partition columns it's
+// missing, schema is not changed
+func schemaWithPartitionColumns(schema *v3ioutils.OldV3ioSchema, columns []string, types map[string]string) *v3ioutils.OldV3ioSchema {
+	var missing []v3ioutils.OldSchemaField
+	for _, name := range columns {
+		if _, err := schema.GetField(name); err != nil {
+			missing = append(missing, v3ioutils.OldSchemaField{Name: name, Type: types[name], Nullable: true})
+		}
+	}
+
+	if len(missing) == 0 {
+		return schema
+	}
+
+	out := *schema
+	out.Fields = append(append([]v3ioutils.OldSchemaField{}, schema.Fields...), missing...)
+	return &out
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+type PartitionsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PartitionsTestSuite) TestParsePartitionFilter() {
+	pf := parsePartitionFilter(`year >= 2018 AND (month IN (1, 2)) and 'a' == name and x != 3`)
+	suite.Require().Len(pf.predicates, 4)
+	suite.Require().Equal(&partitionPredicate{column: "year", op: ">=", values: []interface{}{int64(2018)}}, pf.predicates[0])
+	suite.Require().Equal(&partitionPredicate{column: "month", op: "in", values: []interface{}{int64(1), int64(2)}}, pf.predicates[1])
+	suite.Require().Equal(&partitionPredicate{column: "name", op: "==", values: []interface{}{"a"}}, pf.predicates[2])
+	suite.Require().Nil(pf.predicates[3])
+
+	pf = parsePartitionFilter(`5 < x`)
+	suite.Require().Equal(&partitionPredicate{column: "x", op: ">", values: []interface{}{int64(5)}}, pf.predicates[0])
+
+	for _, filter := range []string{"", "year == 2018 OR month == 1", "year == 'x", "year == 2018 AND"} {
+		pf = parsePartitionFilter(filter)
+		suite.Require().Empty(pf.predicates, filter)
+		suite.Require().Equal(filter, pf.residual(map[string]string{"year": v3ioutils.LongType}))
+	}
+}
+
+func (suite *PartitionsTestSuite) TestMatchDir() {
+	pf := parsePartitionFilter(`year >= 2018 AND month IN (1, 2) AND name == 'a' AND ok == true`)
+
+	testCases := []struct {
+		dir   string
+		match bool
+	}{
+		{"table/year=2019/", true},
+		{"table/year=2017/", false},
+		{"table/year=null/", false},
+		{"table/year=2019/month=2/", true},
+		{"table/year=2019/month=3/", false},
+		{"table/name=a/", true},
+		{"table/name=b/", false},
+		{"table/ok=true/", true},
+		{"table/ok=false/", false},
+		{"table/other=1/", true},
+	}
+
+	for _, tc := range testCases {
+		suite.Require().Equal(tc.match, pf.matchDir(tc.dir), tc.dir)
+	}
+
+	var nilFilter *partitionFilter
+	suite.Require().True(nilFilter.matchDir("table/year=2017/"))
+}
+
+func (suite *PartitionsTestSuite) TestResidual() {
+	pf := parsePartitionFilter(`year > 2018 AND month == 'x' AND v > 1.5`)
+	types := map[string]string{"year": v3ioutils.LongType, "month": v3ioutils.LongType}
+	suite.Require().Equal("(month == 'x') AND (v > 1.5)", pf.residual(types))
+
+	pf = parsePartitionFilter(`year > 2018`)
+	suite.Require().Equal("", pf.residual(types))
+}
+
+func (suite *PartitionsTestSuite) TestPartitionColumns() {
+	schema := &v3ioutils.OldV3ioSchema{
+		Fields: []v3ioutils.OldSchemaField{{Name: "year", Type: v3ioutils.StringType}},
+	}
+	partitions := []string{
+		"table/year=2018/day=1/v=1.5/ok=true/",
+		"table/year=2019/day=null/v=2/ok=false/name=a/",
+	}
+
+	columns, types := partitionColumns("table/", partitions, schema)
+	suite.Require().Equal([]string{"year", "day", "v", "ok"}, columns)
+	suite.Require().Equal(map[string]string{
+		"year": v3ioutils.StringType,
+		"day":  v3ioutils.LongType,
+		"v":    v3ioutils.DoubleType,
+		"ok":   v3ioutils.BoolType,
+	}, types)
+
+	out := schemaWithPartitionColumns(schema, columns, types)
+	suite.Require().Len(out.Fields, 4)
+	suite.Require().Len(schema.Fields, 1)
+
+	value, err := parsePartitionValue("2018", v3ioutils.LongType)
+	suite.Require().NoError(err)
+	suite.Require().Equal(int64(2018), value)
+	value, err = parsePartitionValue("null", v3ioutils.LongType)
+	suite.Require().NoError(err)
+	suite.Require().Nil(value)
+}
+
+func TestPartitionsTestSuite(t *testing.T) {
+	suite.Run(t, new(PartitionsTestSuite))
+}
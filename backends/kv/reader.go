@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/v3io/frames"
@@ -47,6 +48,8 @@ var allowedReadRequestFields = map[string]bool{
 	"ShardingKeys":      true,
 	"SortKeyRangeStart": true,
 	"SortKeyRangeEnd":   true,
+	"TimeZone":          true,
+	"IncludeExpired":    true,
 }
 
 // Read sends a read request
@@ -61,27 +64,22 @@ func (kv *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, erro
 		request.Proto.MessageLimit = 256 // TODO: More?
 	}
 
-	columns := request.Proto.Columns
-	if len(columns) < 1 || columns[0] == "" {
-		columns = []string{"*"}
-	}
-
-	container, tablePath, err := kv.newConnection(request.Proto.Session, request.Password.Get(), request.Token.Get(), request.Proto.Table, true)
+	segments, err := requestSegments(request.Proto)
 	if err != nil {
 		return nil, err
 	}
 
-	partitions, err := kv.getPartitions(tablePath, container)
+	err = v3ioutils.ValidateTimeFormat(request.Proto.TimeZone, "")
 	if err != nil {
 		return nil, err
 	}
 
-	input := v3io.GetItemsInput{Filter: request.Proto.Filter, AttributeNames: columns, SortKeyRangeStart: request.Proto.SortKeyRangeStart, SortKeyRangeEnd: request.Proto.SortKeyRangeEnd}
-	kv.logger.DebugWith("read input", "input", input, "request", request)
+	columns := request.Proto.Columns
+	if len(columns) < 1 || columns[0] == "" {
+		columns = []string{"*"}
+	}
 
-	iter, err := v3ioutils.NewAsyncItemsCursor(
-		container, &input, kv.numWorkers, request.Proto.ShardingKeys, kv.logger, 0, partitions,
-		request.Proto.SortKeyRangeStart, request.Proto.SortKeyRangeEnd)
+	container, tablePath, err := kv.newConnection(request.Proto.Session, request.Password.Get(), request.Token.Get(), request.Proto.Table, true)
 	if err != nil {
 		return nil, err
 	}
@@ -101,20 +99,99 @@ func (kv *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, erro
 	}
 	schemaObj := schemaInterface.(*v3ioutils.OldV3ioSchema)
 
+	var state *v3ioutils.ItemsCursorState
+	var partitions []string
+	partitionFilter := parsePartitionFilter(request.Proto.Filter)
+	if request.Proto.Marker != "" {
+		// Resume a previous read, partitions are taken from the marker
+		state, err = decodeReadMarker(request.Proto)
+		if err != nil {
+			return nil, err
+		}
+		partitions = statePartitions(state)
+	} else {
+		partitions, err = kv.getPartitions(tablePath, container, partitionFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		if request.Proto.TotalSegments > 0 {
+			// Segmented read, other clients read the other segments
+			state = v3ioutils.NewItemsCursorState(partitions, int(request.Proto.TotalSegments), segments, nil)
+		}
+	}
+
+	// Conditions on partition columns were decided by the partitions we read
+	partitionColumns, partitionTypes := partitionColumns(tablePath, partitions, schemaObj)
+	schemaObj = schemaWithPartitionColumns(schemaObj, partitionColumns, partitionTypes)
+	filter := partitionFilter.residual(partitionTypes)
+	if hidesExpired(request, schemaObj) {
+		filter = notExpiredFilter(filter, time.Now())
+	}
+
+	input := v3io.GetItemsInput{Filter: filter, AttributeNames: columns, SortKeyRangeStart: request.Proto.SortKeyRangeStart, SortKeyRangeEnd: request.Proto.SortKeyRangeEnd}
+	kv.logger.DebugWith("read input", "input", input, "request", request)
+
+	var iter itemsCursor
+	indexIter, err := kv.newIndexCursor(container, tablePath, schemaObj, request, partitionFilter, partitions, partitionTypes, columns)
+	if err != nil {
+		return nil, err
+	}
+	if indexIter != nil {
+		iter = indexIter
+	} else if state != nil {
+		iter, err = v3ioutils.NewAsyncItemsCursorFromState(
+			container, &input, state, kv.numWorkers, kv.logger, 0, request.Proto.SortKeyRangeStart, request.Proto.SortKeyRangeEnd)
+	} else {
+		iter, err = v3ioutils.NewAsyncItemsCursor(
+			container, &input, kv.numWorkers, request.Proto.ShardingKeys, kv.logger, 0, partitions,
+			request.Proto.SortKeyRangeStart, request.Proto.SortKeyRangeEnd)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	shouldDuplicateSorting := schemaObj.SortingKey != "" && containsString(columns, schemaObj.SortingKey)
-	newKVIter := Iterator{request: request, iter: iter, schema: schemaObj, shouldDuplicateIndex: containsString(columns, schemaObj.Key), shouldDuplicateSorting: shouldDuplicateSorting}
+	newKVIter := Iterator{
+		request:                request,
+		iter:                   iter,
+		schema:                 schemaObj,
+		shouldDuplicateIndex:   containsString(columns, schemaObj.Key),
+		shouldDuplicateSorting: shouldDuplicateSorting,
+		tablePath:              tablePath,
+		partitionColumns:       partitionColumns,
+		partitionTypes:         partitionTypes,
+	}
 	return &newKVIter, nil
 }
 
-// Iterator is key/value iterator
+// itemsCursor is the items source of an Iterator, a v3ioutils.AsyncItemsCursor
+// or an indexCursor
+type itemsCursor interface {
+	Next() bool
+	GetFields() map[string]interface{}
+	Err() error
+	State() *v3ioutils.ItemsCursorState
+	CurrentPartition() string
+}
+
+// Iterator is key/value iterator. Frames of a read that has more items have
+// a frames.MarkerLabel label to resume the read after them.
 type Iterator struct {
 	request                *frames.ReadRequest
-	iter                   *v3ioutils.AsyncItemsCursor
+	iter                   itemsCursor
 	err                    error
 	currFrame              frames.Frame
 	shouldDuplicateIndex   bool
 	schema                 *v3ioutils.OldV3ioSchema
 	shouldDuplicateSorting bool
+	numRows                int64 // rows returned so far, for request Limit
+
+	tablePath        string
+	partitionColumns []string          // columns filled from partition paths
+	partitionTypes   map[string]string // schema type of partition columns
+	partition        string            // partition of partitionValues
+	partitionValues  map[string]interface{}
 }
 
 // Next advances the iterator to next frame
@@ -203,7 +280,8 @@ func (ki *Iterator) Next() bool {
 		}
 	}
 
-	for ; rowNum < int(ki.request.Proto.MessageLimit) && ki.iter.Next(); rowNum++ {
+	limit := ki.request.Proto.Limit
+	for ; rowNum < int(ki.request.Proto.MessageLimit) && (limit == 0 || ki.numRows+int64(rowNum-numOfSchemaFiles) < limit) && ki.iter.Next(); rowNum++ {
 		row := ki.iter.GetFields()
 
 		// Skip table schema object
@@ -212,6 +290,10 @@ func (ki *Iterator) Next() bool {
 			numOfSchemaFiles++
 			continue
 		}
+
+		if len(ki.partitionColumns) > 0 {
+			row = ki.addPartitionValues(row, byName)
+		}
 		// Indicates whether the key column exists as an attribute in addition to the object name (__name)
 		_, hasKeyColumnAttribute := row[ki.schema.Key]
 
@@ -270,6 +352,11 @@ func (ki *Iterator) Next() bool {
 		return false
 	}
 
+	if err := ki.setTimeZones(columns, byName); err != nil {
+		ki.err = err
+		return false
+	}
+
 	var indices []frames.Column
 
 	// If the only column that was requested is the key column, don't set it as an index.
@@ -288,8 +375,20 @@ func (ki *Iterator) Next() bool {
 	if !hasAnyNulls {
 		nullColumns = nil
 	}
+	ki.numRows += int64(rowNum - numOfSchemaFiles)
+
+	var labels map[string]interface{}
+	if state := ki.iter.State(); !state.Done() {
+		marker, err := encodeReadMarker(ki.request.Proto, state)
+		if err != nil {
+			ki.err = errors.Wrap(err, "can't encode read marker")
+			return false
+		}
+		labels = map[string]interface{}{frames.MarkerLabel: marker}
+	}
+
 	var err error
-	ki.currFrame, err = frames.NewFrameWithNullValues(columns, indices, nil, nullColumns)
+	ki.currFrame, err = frames.NewFrameWithNullValues(columns, indices, labels, nullColumns)
 	if err != nil {
 		ki.err = err
 		return false
@@ -298,6 +397,78 @@ func (ki *Iterator) Next() bool {
 	return true
 }
 
+// setTimeZones converts the time columns to the requested time zone, or to
+// the time zone in their schema field
+func (ki *Iterator) setTimeZones(columns []frames.Column, byName map[string]frames.Column) error {
+	for i, col := range columns {
+		if col.DType() != frames.TimeType {
+			continue
+		}
+
+		timeZone := ki.request.Proto.TimeZone
+		if timeZone == "" {
+			field, err := ki.schema.GetField(col.Name())
+			if err != nil || field.TimeZone == "" {
+				continue
+			}
+			timeZone = field.TimeZone
+		}
+
+		converted, err := frames.ColumnInTimeZone(col, timeZone)
+		if err != nil {
+			return err
+		}
+		columns[i] = converted
+		byName[col.Name()] = converted
+	}
+
+	return nil
+}
+
+// addPartitionValues returns row with the partition columns it's missing,
+// taken from the item partition path
+func (ki *Iterator) addPartitionValues(row map[string]interface{}, byName map[string]frames.Column) map[string]interface{} {
+	if partition := ki.iter.CurrentPartition(); partition != ki.partition || ki.partitionValues == nil {
+		ki.partition = partition
+		ki.partitionValues = make(map[string]interface{})
+		for _, pair := range partitionPathValues(ki.tablePath, partition) {
+			dtype, ok := ki.partitionTypes[pair[0]]
+			if !ok {
+				continue
+			}
+
+			// Null partitions and types we can't parse are left as nulls
+			if value, err := parsePartitionValue(pair[1], dtype); err == nil && value != nil {
+				ki.partitionValues[pair[0]] = value
+			}
+		}
+	}
+
+	var out map[string]interface{}
+	for name, value := range ki.partitionValues {
+		if _, ok := row[name]; ok {
+			continue
+		}
+
+		if _, ok := byName[name]; !ok { // Not requested
+			continue
+		}
+
+		if out == nil {
+			out = make(map[string]interface{}, len(row)+len(ki.partitionValues))
+			for key, field := range row {
+				out[key] = field
+			}
+		}
+		out[name] = value
+	}
+
+	if out == nil {
+		return row
+	}
+	return out
+}
+
 func (ki *Iterator) handleIndices(index string, data map[string]frames.Column, shouldDup bool, indices *[]frames.Column, columns *[]frames.Column) {
 	col, ok := data[index]
 	if ok {
@@ -339,7 +510,9 @@ func filterPartitions(dirs []v3io.CommonPrefix) []v3io.CommonPrefix {
 	return tmp
 }
 
-func (kv *Backend) getPartitions(path string, container v3io.Container) ([]string, error) {
+// getPartitions returns the leaf partition directories under path, skipping
+// partitions that don't match the filter predicates
+func (kv *Backend) getPartitions(path string, container v3io.Container, filter *partitionFilter) ([]string, error) {
 	var partitions []string
 	var done bool
 	var marker string
@@ -356,7 +529,10 @@ func (kv *Backend) getPartitions(path string, container v3io.Container) ([]strin
 		out.CommonPrefixes = filterPartitions(out.CommonPrefixes)
 		if len(out.CommonPrefixes) > 0 {
 			for _, partition := range out.CommonPrefixes {
-				parts, err := kv.getPartitions(partition.Prefix, container)
+				if !filter.matchDir(partition.Prefix) {
+					continue
+				}
+				parts, err := kv.getPartitions(partition.Prefix, container, filter)
 				if err != nil {
 					return nil, err
 				}
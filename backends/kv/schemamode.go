@@ -0,0 +1,301 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+// validateSchemaMode checks a write schema mode, empty is the default
+func validateSchemaMode(mode string) error {
+	switch mode {
+	case "", frames.SchemaModeEvolve, frames.SchemaModeStrict, frames.SchemaModeCoerce:
+		return nil
+	}
+
+	return fmt.Errorf("bad schema mode '%s', should be %s, %s or %s",
+		mode, frames.SchemaModeEvolve, frames.SchemaModeStrict, frames.SchemaModeCoerce)
+}
+
+// enforceSchema checks the written columns and labels against the table
+// schema in strict and coerce modes, before the schema is updated. In coerce
+// mode columns and labels of another type are cast to their schema type.
+// Tables without fields get the schema of their first write.
+func (a *Appender) enforceSchema(frame frames.Frame, columns map[string]frames.Column, labels map[string]interface{}) error {
+	schema := a.schema.(*v3ioutils.OldV3ioSchema)
+	if a.schemaMode == frames.SchemaModeEvolve || len(schema.Fields) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	labelNames := make([]string, 0, len(labels))
+	for name := range labels {
+		labelNames = append(labelNames, name)
+	}
+	sort.Strings(labelNames)
+
+	var unknown, mismatched, failed []string
+	for _, name := range names {
+		col := columns[name]
+		if col.DType() == frames.NullType {
+			continue
+		}
+
+		field, err := schema.GetField(name)
+		if err != nil {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		dtype := v3ioutils.ConvertDTypeToString(col.DType())
+		if dtype == field.Type {
+			continue
+		}
+		if a.schemaMode == frames.SchemaModeStrict {
+			mismatched = append(mismatched, fmt.Sprintf("%s (%s, not %s)", name, dtype, field.Type))
+			continue
+		}
+
+		if columns[name], err = coerceColumn(frame, col, name, field.Type); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", name, err))
+		}
+	}
+
+	for _, name := range labelNames {
+		field, err := schema.GetField(name)
+		if err != nil {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		dtype := valueType(labels[name])
+		if dtype == field.Type {
+			continue
+		}
+		if a.schemaMode == frames.SchemaModeStrict {
+			mismatched = append(mismatched, fmt.Sprintf("%s (%s, not %s)", name, dtype, field.Type))
+			continue
+		}
+
+		if labels[name], err = coerceValue(labels[name], field.Type); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", name, err))
+		}
+	}
+
+	var problems []string
+	if len(unknown) > 0 {
+		problems = append(problems, "columns not in the schema: "+strings.Join(unknown, ", "))
+	}
+	if len(mismatched) > 0 {
+		problems = append(problems, "columns of another type: "+strings.Join(mismatched, ", "))
+	}
+	if len(failed) > 0 {
+		problems = append(problems, "columns that can't be cast: "+strings.Join(failed, ", "))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("write doesn't match the schema of table '%s' (%s mode); %s",
+			a.tablePath, a.schemaMode, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// coerceColumn returns col cast to a schema type, null rows are not cast
+func coerceColumn(frame frames.Frame, col frames.Column, name string, fieldType string) (frames.Column, error) {
+	values := make([]interface{}, col.Len())
+	for i := range values {
+		if frame.IsNull(i, name) {
+			continue
+		}
+
+		value, err := utils.ColAt(col, i)
+		if err != nil {
+			return nil, err
+		}
+		if values[i], err = coerceValue(value, fieldType); err != nil {
+			return nil, fmt.Errorf("row %d: %v", i, err)
+		}
+	}
+
+	var data interface{}
+	switch fieldType {
+	case v3ioutils.LongType:
+		typed := make([]int64, len(values))
+		for i, value := range values {
+			typed[i], _ = value.(int64)
+		}
+		data = typed
+	case v3ioutils.DoubleType:
+		typed := make([]float64, len(values))
+		for i, value := range values {
+			typed[i], _ = value.(float64)
+		}
+		data = typed
+	case v3ioutils.StringType:
+		typed := make([]string, len(values))
+		for i, value := range values {
+			typed[i], _ = value.(string)
+		}
+		data = typed
+	case v3ioutils.TimeType:
+		typed := make([]time.Time, len(values))
+		for i, value := range values {
+			typed[i], _ = value.(time.Time)
+		}
+		data = typed
+	case v3ioutils.BoolType:
+		typed := make([]bool, len(values))
+		for i, value := range values {
+			typed[i], _ = value.(bool)
+		}
+		data = typed
+	default:
+		return nil, fmt.Errorf("unknown schema type %s", fieldType)
+	}
+
+	return frames.NewSliceColumn(name, data)
+}
+
+// coerceValue casts a value to a schema type
+func coerceValue(value interface{}, fieldType string) (interface{}, error) {
+	switch fieldType {
+	case v3ioutils.LongType:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case int:
+			return int64(v), nil
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < math.MaxInt64 {
+				return int64(v), nil
+			}
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case string:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+	case v3ioutils.DoubleType:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		}
+	case v3ioutils.StringType:
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format(time.RFC3339Nano), nil
+		case string, int64, int, float64, bool:
+			return fmt.Sprint(v), nil
+		}
+	case v3ioutils.TimeType:
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, nil
+			}
+		}
+	case v3ioutils.BoolType:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("can't cast %v (%s) to %s", value, valueType(value), fieldType)
+}
+
+// valueType returns the schema type of a value
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case int, int32, int64:
+		return v3ioutils.LongType
+	case float32, float64:
+		return v3ioutils.DoubleType
+	case string:
+		return v3ioutils.StringType
+	case time.Time:
+		return v3ioutils.TimeType
+	case bool:
+		return v3ioutils.BoolType
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+// setSchemaMode is the schema_mode exec command, it sets the schema mode of
+// the table writes (the mode argument)
+func (b *Backend) setSchemaMode(request *frames.ExecRequest) error {
+	mode := ""
+	if val, ok := request.Proto.Args["mode"]; ok {
+		mode = val.GetSval()
+	}
+	if mode == "" {
+		return fmt.Errorf("missing 'mode' argument")
+	}
+	if err := validateSchemaMode(mode); err != nil {
+		return err
+	}
+
+	container, tablePath, err := b.newConnection(request.Proto.Session, request.Password.Get(), request.Token.Get(), request.Proto.Table, true)
+	if err != nil {
+		return err
+	}
+
+	schema, err := v3ioutils.GetSchema(tablePath, container)
+	if err != nil {
+		return fmt.Errorf("can't read the schema of table '%s': %v", tablePath, err)
+	}
+
+	oldSchema := schema.(*v3ioutils.OldV3ioSchema)
+	oldSchema.Mode = mode
+	return oldSchema.Save(container, tablePath)
+}
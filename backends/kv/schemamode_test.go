@@ -0,0 +1,194 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+type SchemaModeTestSuite struct {
+	suite.Suite
+	backend *testBackend
+}
+
+func (suite *SchemaModeTestSuite) SetupTest() {
+	suite.backend = newTestBackend(suite.T())
+
+	suite.Require().NoError(suite.write("", "a", map[string]interface{}{"n": []int64{1}, "x": []float64{1.5}, "s": []string{"one"}}))
+}
+
+// write writes an item with columns
+func (suite *SchemaModeTestSuite) write(mode string, key string, columns map[string]interface{}) error {
+	keyCol, err := frames.NewSliceColumn("key", []string{key})
+	suite.Require().NoError(err)
+	var cols []frames.Column
+	for name, data := range columns {
+		col, err := frames.NewSliceColumn(name, data)
+		suite.Require().NoError(err)
+		cols = append(cols, col)
+	}
+	frame, err := frames.NewFrame(cols, []frames.Column{keyCol}, nil)
+	suite.Require().NoError(err)
+
+	request := &frames.WriteRequest{
+		Session:       &frames.Session{Container: "bigdata"},
+		Password:      frames.InitSecretString(""),
+		Token:         frames.InitSecretString(""),
+		Table:         "table",
+		ImmidiateData: frame,
+		SaveMode:      frames.OverwriteItem,
+		SchemaMode:    mode,
+	}
+	appender, err := suite.backend.Write(request)
+	if err != nil {
+		return err
+	}
+	return appender.WaitForComplete(time.Second)
+}
+
+func (suite *SchemaModeTestSuite) schema() *v3ioutils.OldV3ioSchema {
+	schema, err := v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().NoError(err)
+	return schema.(*v3ioutils.OldV3ioSchema)
+}
+
+func (suite *SchemaModeTestSuite) TestEvolve() {
+	suite.Require().NoError(suite.write("", "b", map[string]interface{}{"n": []float64{2.5}, "new": []bool{true}}))
+
+	field, err := suite.schema().GetField("n")
+	suite.Require().NoError(err)
+	suite.Require().Equal(v3ioutils.DoubleType, field.Type)
+	_, err = suite.schema().GetField("new")
+	suite.Require().NoError(err)
+}
+
+func (suite *SchemaModeTestSuite) TestStrict() {
+	err := suite.write(frames.SchemaModeStrict, "b", map[string]interface{}{"n": []float64{2.5}, "nn": []int64{1}, "s": []string{"two"}})
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "not in the schema: nn")
+	suite.Require().Contains(err.Error(), "another type: n (double, not long)")
+	suite.Require().False(suite.backend.itemExists("table/b"))
+	_, err = suite.schema().GetField("nn")
+	suite.Require().Error(err)
+
+	suite.Require().NoError(suite.write(frames.SchemaModeStrict, "b", map[string]interface{}{"n": []int64{2}, "s": []string{"two"}}))
+	suite.Require().Equal("two", suite.backend.getItem("table/b")["s"])
+}
+
+func (suite *SchemaModeTestSuite) TestCoerce() {
+	err := suite.write(frames.SchemaModeCoerce, "b", map[string]interface{}{"n": []string{"2"}, "x": []int64{3}, "s": []float64{4.5}})
+	suite.Require().NoError(err)
+
+	item := suite.backend.getItem("table/b")
+	suite.Require().Equal(2, item["n"])
+	suite.Require().Equal(3.0, item["x"])
+	suite.Require().Equal("4.5", item["s"])
+	field, err := suite.schema().GetField("n")
+	suite.Require().NoError(err)
+	suite.Require().Equal(v3ioutils.LongType, field.Type)
+
+	err = suite.write(frames.SchemaModeCoerce, "c", map[string]interface{}{"n": []float64{2.5}, "nn": []int64{1}})
+	suite.Require().Error(err)
+	suite.Require().Contains(err.Error(), "not in the schema: nn")
+	suite.Require().Contains(err.Error(), "can't be cast: n (row 0: can't cast 2.5 (double) to long)")
+	suite.Require().False(suite.backend.itemExists("table/c"))
+}
+
+func (suite *SchemaModeTestSuite) TestTableMode() {
+	request := &frames.ExecRequest{
+		Proto: &pb.ExecRequest{
+			Session: &frames.Session{Container: "bigdata"},
+			Backend: "kv",
+			Table:   "table",
+			Command: "schema_mode",
+			Args: map[string]*pb.Value{
+				"mode": {Value: &pb.Value_Sval{Sval: frames.SchemaModeStrict}},
+			},
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+	_, err := suite.backend.Exec(request)
+	suite.Require().NoError(err)
+	suite.Require().Equal(frames.SchemaModeStrict, suite.schema().Mode)
+
+	suite.Require().Error(suite.write("", "b", map[string]interface{}{"nn": []int64{1}}))
+	// The request mode overrides the table mode
+	suite.Require().NoError(suite.write(frames.SchemaModeEvolve, "b", map[string]interface{}{"nn": []int64{1}}))
+	suite.Require().Equal(frames.SchemaModeStrict, suite.schema().Mode)
+
+	request.Proto.Args["mode"] = &pb.Value{Value: &pb.Value_Sval{Sval: "loose"}}
+	_, err = suite.backend.Exec(request)
+	suite.Require().Error(err)
+}
+
+func (suite *SchemaModeTestSuite) TestBadMode() {
+	suite.Require().Error(suite.write("loose", "b", map[string]interface{}{"n": []int64{1}}))
+}
+
+func TestSchemaModeTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaModeTestSuite))
+}
+
+func TestCoerceValue(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		value     interface{}
+		fieldType string
+		expected  interface{}
+	}{
+		{int64(1), v3ioutils.DoubleType, 1.0},
+		{2.0, v3ioutils.LongType, int64(2)},
+		{true, v3ioutils.LongType, int64(1)},
+		{"3", v3ioutils.LongType, int64(3)},
+		{"3.5", v3ioutils.DoubleType, 3.5},
+		{"true", v3ioutils.BoolType, true},
+		{int64(7), v3ioutils.StringType, "7"},
+		{ts, v3ioutils.StringType, "2020-01-02T03:04:05Z"},
+		{"2020-01-02T03:04:05Z", v3ioutils.TimeType, ts},
+		{2.5, v3ioutils.LongType, nil},
+		{"x", v3ioutils.DoubleType, nil},
+		{int64(1), v3ioutils.TimeType, nil},
+	}
+
+	for _, c := range cases {
+		value, err := coerceValue(c.value, c.fieldType)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("%v to %s: no error", c.value, c.fieldType)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v to %s: %v", c.value, c.fieldType, err)
+			continue
+		}
+		if value != c.expected {
+			t.Errorf("%v to %s: %v != %v", c.value, c.fieldType, value, c.expected)
+		}
+	}
+}
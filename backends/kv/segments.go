@@ -0,0 +1,145 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+	"github.com/v3io/frames/v3ioutils"
+	"github.com/v3io/v3io-go/pkg/dataplane"
+)
+
+const (
+	defaultItemsPerSegment = 100000
+	defaultMaxSegments     = 1024
+)
+
+// requestSegments returns the segments to read of a segmented read request
+// (TotalSegments > 0), all segments if Segments is empty
+func requestSegments(request *pb.ReadRequest) ([]int, error) {
+	if request.TotalSegments < 0 {
+		return nil, fmt.Errorf("negative total_segments - %d", request.TotalSegments)
+	}
+
+	if request.TotalSegments == 0 {
+		if len(request.Segments) > 0 {
+			return nil, fmt.Errorf("segments require total_segments")
+		}
+		return nil, nil
+	}
+
+	if len(request.ShardingKeys) > 0 {
+		return nil, fmt.Errorf("total_segments can't be used with sharding_keys")
+	}
+
+	seen := make(map[int64]bool)
+	segments := make([]int, 0, len(request.Segments))
+	for _, segment := range request.Segments {
+		if segment < 0 || segment >= request.TotalSegments {
+			return nil, fmt.Errorf("segment %d out of range [0, %d)", segment, request.TotalSegments)
+		}
+
+		if seen[segment] {
+			return nil, fmt.Errorf("duplicate segment - %d", segment)
+		}
+		seen[segment] = true
+		segments = append(segments, int(segment))
+	}
+
+	return segments, nil
+}
+
+// suggestSegments counts the table items and returns a frame with the
+// number of items and the suggested number of segments for a segmented read
+// (see ReadRequest.TotalSegments). Optional arguments are "filter",
+// "items_per_segment" and "max_segments".
+func (b *Backend) suggestSegments(request *frames.ExecRequest) (frames.Frame, error) {
+	itemsPerSegment := int64(defaultItemsPerSegment)
+	if val, ok := request.Proto.Args["items_per_segment"]; ok {
+		if itemsPerSegment = val.GetIval(); itemsPerSegment <= 0 {
+			return nil, fmt.Errorf("items_per_segment must be positive")
+		}
+	}
+
+	maxSegments := int64(defaultMaxSegments)
+	if val, ok := request.Proto.Args["max_segments"]; ok {
+		if maxSegments = val.GetIval(); maxSegments <= 0 {
+			return nil, fmt.Errorf("max_segments must be positive")
+		}
+	}
+
+	filter := ""
+	if val, ok := request.Proto.Args["filter"]; ok {
+		filter = val.GetSval()
+	}
+
+	container, tablePath, err := b.newConnection(request.Proto.Session, request.Password.Get(), request.Token.Get(), request.Proto.Table, true)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions, err := b.getPartitions(tablePath, container, parsePartitionFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	input := v3io.GetItemsInput{Filter: filter, AttributeNames: []string{indexColKey}}
+	iter, err := v3ioutils.NewAsyncItemsCursor(container, &input, b.numWorkers, nil, b.logger, 0, partitions, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var numItems int64
+	for iter.Next() {
+		if name, _ := iter.GetField(indexColKey).(string); name != ".#schema" {
+			numItems++
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, errors.Wrap(err, "can't count items")
+	}
+
+	segments := (numItems + itemsPerSegment - 1) / itemsPerSegment
+	if segments < 1 {
+		segments = 1
+	}
+	if segments > maxSegments {
+		segments = maxSegments
+	}
+
+	b.logger.DebugWith("suggested segments", "table", tablePath, "items", numItems, "segments", segments)
+
+	var columns []frames.Column
+	values := map[string]int64{"items": numItems, "partitions": int64(len(partitions)), "segments": segments}
+	for _, name := range []string{"items", "partitions", "segments"} {
+		col, err := frames.NewSliceColumn(name, []int64{values[name]})
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+
+	return frames.NewFrame(columns, nil, nil)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames/pb"
+)
+
+type SegmentsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SegmentsTestSuite) TestRequestSegments() {
+	segments, err := requestSegments(&pb.ReadRequest{})
+	suite.Require().NoError(err)
+	suite.Require().Empty(segments)
+
+	segments, err = requestSegments(&pb.ReadRequest{TotalSegments: 4, Segments: []int64{3, 1}})
+	suite.Require().NoError(err)
+	suite.Require().Equal([]int{3, 1}, segments)
+
+	segments, err = requestSegments(&pb.ReadRequest{TotalSegments: 4})
+	suite.Require().NoError(err)
+	suite.Require().Empty(segments)
+}
+
+func (suite *SegmentsTestSuite) TestRequestSegmentsErrors() {
+	badRequests := []*pb.ReadRequest{
+		{TotalSegments: -1},
+		{Segments: []int64{0}},
+		{TotalSegments: 2, Segments: []int64{2}},
+		{TotalSegments: 2, Segments: []int64{-1}},
+		{TotalSegments: 2, Segments: []int64{1, 1}},
+		{TotalSegments: 2, ShardingKeys: []string{"a"}},
+	}
+
+	for _, request := range badRequests {
+		_, err := requestSegments(request)
+		suite.Require().Error(err, "request: %+v", request)
+	}
+}
+
+func TestSegmentsTestSuite(t *testing.T) {
+	suite.Run(t, new(SegmentsTestSuite))
+}
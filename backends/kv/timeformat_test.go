@@ -0,0 +1,161 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+type TimeFormatTestSuite struct {
+	suite.Suite
+	backend *testBackend
+}
+
+// Around the New York DST change at 2021-03-14 07:00 UTC
+var (
+	beforeDST = time.Date(2021, 3, 14, 6, 59, 59, 123456789, time.UTC)
+	afterDST  = time.Date(2021, 3, 14, 7, 0, 0, 987654321, time.UTC)
+)
+
+func (suite *TimeFormatTestSuite) SetupTest() {
+	suite.backend = newTestBackend(suite.T())
+}
+
+func (suite *TimeFormatTestSuite) write(timeZone, precision string) error {
+	keys, err := frames.NewSliceColumn("key", []string{"a", "b"})
+	suite.Require().NoError(err)
+	times, err := frames.NewSliceColumn("t", []time.Time{beforeDST, afterDST})
+	suite.Require().NoError(err)
+	frame, err := frames.NewFrame([]frames.Column{times}, []frames.Column{keys}, nil)
+	suite.Require().NoError(err)
+
+	request := &frames.WriteRequest{
+		Session:       &frames.Session{Container: "bigdata"},
+		Password:      frames.InitSecretString(""),
+		Token:         frames.InitSecretString(""),
+		Table:         "table",
+		ImmidiateData: frame,
+		SaveMode:      frames.OverwriteItem,
+		TimeZone:      timeZone,
+		TimePrecision: precision,
+	}
+	appender, err := suite.backend.Write(request)
+	if err != nil {
+		return err
+	}
+	return appender.WaitForComplete(time.Second)
+}
+
+// read returns the times of the "t" column by key
+func (suite *TimeFormatTestSuite) read(timeZone string) map[string]time.Time {
+	request := &frames.ReadRequest{
+		Proto: &pb.ReadRequest{
+			Session:  &frames.Session{Container: "bigdata"},
+			Table:    "table",
+			TimeZone: timeZone,
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+	iter, err := suite.backend.Read(request)
+	suite.Require().NoError(err)
+
+	times := make(map[string]time.Time)
+	for iter.Next() {
+		frame := iter.At()
+		keys := frame.Indices()[0]
+		col, err := frame.Column("t")
+		suite.Require().NoError(err)
+		for i := 0; i < frame.Len(); i++ {
+			key, err := keys.StringAt(i)
+			suite.Require().NoError(err)
+			times[key], err = col.TimeAt(i)
+			suite.Require().NoError(err)
+		}
+	}
+	suite.Require().NoError(iter.Err())
+	return times
+}
+
+func (suite *TimeFormatTestSuite) TestNanoseconds() {
+	suite.Require().NoError(suite.write("", ""))
+	times := suite.read("UTC")
+	suite.Require().True(times["a"].Equal(beforeDST), times["a"])
+	suite.Require().True(times["b"].Equal(afterDST), times["b"])
+	suite.Require().Equal("2021-03-14T06:59:59.123456789Z", times["a"].Format(time.RFC3339Nano))
+}
+
+func (suite *TimeFormatTestSuite) TestSchemaTimeZone() {
+	suite.Require().NoError(suite.write("America/New_York", "ms"))
+
+	schema, err := v3ioutils.GetSchema("table/", suite.backend.container)
+	suite.Require().NoError(err)
+	field, err := schema.(*v3ioutils.OldV3ioSchema).GetField("t")
+	suite.Require().NoError(err)
+	suite.Require().Equal("America/New_York", field.TimeZone)
+	suite.Require().Equal("ms", field.Precision)
+
+	times := suite.read("")
+	suite.Require().Equal("2021-03-14T01:59:59.123-05:00", times["a"].Format(time.RFC3339Nano))
+	suite.Require().Equal("2021-03-14T03:00:00.987-04:00", times["b"].Format(time.RFC3339Nano))
+
+	// The requested time zone overrides the schema one
+	times = suite.read("Europe/London")
+	suite.Require().Equal("2021-03-14T06:59:59.123Z", times["a"].Format(time.RFC3339Nano))
+}
+
+func (suite *TimeFormatTestSuite) TestChangePrecision() {
+	suite.Require().NoError(suite.write("", "s"))
+	// Writing without a time format keeps the schema one
+	suite.Require().NoError(suite.write("", ""))
+	times := suite.read("UTC")
+	suite.Require().Equal("2021-03-14T07:00:00Z", times["b"].Format(time.RFC3339Nano))
+
+	suite.Require().Error(suite.write("", "us"))
+}
+
+func (suite *TimeFormatTestSuite) TestBadTimeFormat() {
+	suite.Require().Error(suite.write("Nowhere/Special", ""))
+	suite.Require().Error(suite.write("", "minutes"))
+
+	suite.Require().NoError(suite.write("", ""))
+	request := &frames.ReadRequest{
+		Proto: &pb.ReadRequest{
+			Session:  &frames.Session{Container: "bigdata"},
+			Table:    "table",
+			TimeZone: "Nowhere/Special",
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+	_, err := suite.backend.Read(request)
+	suite.Require().Error(err)
+}
+
+func TestTimeFormatTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeFormatTestSuite))
+}
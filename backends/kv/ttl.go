@@ -0,0 +1,212 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+// Items written with a TTL (see frames.WriteRequest.TTL) have an expiry
+// attribute holding their expiry time in epoch nanoseconds. Reads skip
+// expired items unless the request IncludeExpired is set, and the
+// purge_expired exec command deletes them.
+
+const expiryAttr = "_expiry"
+
+// validateTTL checks the TTL of a write request, a positive duration or a
+// column name
+func validateTTL(ttl string) error {
+	if ttl == "" {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(ttl)
+	if err != nil {
+		if !validColumnNamePattern.MatchString(ttl) {
+			return fmt.Errorf("bad ttl '%s', should be a duration (e.g. 1h) or a time column", ttl)
+		}
+		return nil
+	}
+
+	if duration <= 0 {
+		return fmt.Errorf("bad ttl '%s', should be positive", ttl)
+	}
+
+	return nil
+}
+
+// expiryFunc returns a function returning the expiry time (epoch nanoseconds)
+// of a frame row, and false if the row has no expiry time. It returns nil if
+// the write has no TTL.
+func (a *Appender) expiryFunc(frame frames.Frame) (func(int) (int64, bool), error) {
+	ttl := a.request.TTL
+	if ttl == "" {
+		return nil, nil
+	}
+
+	if duration, err := time.ParseDuration(ttl); err == nil {
+		expiry := time.Now().Add(duration).UnixNano()
+		return func(int) (int64, bool) {
+			return expiry, true
+		}, nil
+	}
+
+	col, err := frame.Column(ttl)
+	if err != nil {
+		return nil, fmt.Errorf("ttl column '%s' does not exist in the dataframe", ttl)
+	}
+	if col.DType() != frames.TimeType {
+		return nil, fmt.Errorf("ttl column '%s' is not a time column", ttl)
+	}
+
+	return func(r int) (int64, bool) {
+		if frame.IsNull(r, ttl) {
+			return 0, false
+		}
+		t, err := col.TimeAt(r)
+		if err != nil {
+			return 0, false
+		}
+		return t.UnixNano(), true
+	}, nil
+}
+
+// expiryColumn returns the expiry attribute column of a frame and the null
+// function of the frame with the column, or nil if the write has no TTL
+func (a *Appender) expiryColumn(frame frames.Frame) (frames.Column, func(int, string) bool, error) {
+	expiry, err := a.expiryFunc(frame)
+	if err != nil || expiry == nil {
+		return nil, frame.IsNull, err
+	}
+
+	values := make([]int64, frame.Len())
+	nulls := make(map[int]bool)
+	for r := range values {
+		var ok bool
+		if values[r], ok = expiry(r); !ok {
+			nulls[r] = true
+		}
+	}
+
+	col, err := frames.NewSliceColumn(expiryAttr, values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isNull := func(r int, name string) bool {
+		if name == expiryAttr {
+			return nulls[r]
+		}
+		return frame.IsNull(r, name)
+	}
+
+	return col, isNull, nil
+}
+
+// addExpiryField adds the expiry attribute to the table schema
+func (a *Appender) addExpiryField() error {
+	schema := a.schema.(*v3ioutils.OldV3ioSchema)
+	newSchema := v3ioutils.NewSchema(schema.Key, schema.SortingKey)
+	if err := newSchema.AddField(expiryAttr, int64(0), true); err != nil {
+		return err
+	}
+
+	return a.schema.UpdateSchema(a.container, a.tablePath, newSchema)
+}
+
+// withExpiry returns an update expression that also sets the expiry time
+func withExpiry(expression string, expiry int64) string {
+	expression = strings.TrimSuffix(strings.TrimSpace(expression), ";")
+	return fmt.Sprintf("%s;%s=%d;", expression, expiryAttr, expiry)
+}
+
+// notExpiredFilter returns filter with a condition that skips items that
+// expired before now
+func notExpiredFilter(filter string, now time.Time) string {
+	notExpired := fmt.Sprintf("(NOT exists(%s) OR %s > %d)", expiryAttr, expiryAttr, now.UnixNano())
+	if filter == "" {
+		return notExpired
+	}
+
+	return fmt.Sprintf("(%s) AND %s", filter, notExpired)
+}
+
+// hidesExpired returns true if a read should skip expired items, the table
+// has expiry times and the request doesn't include expired items
+func hidesExpired(request *frames.ReadRequest, schema *v3ioutils.OldV3ioSchema) bool {
+	if request.Proto.IncludeExpired {
+		return false
+	}
+
+	_, err := schema.GetField(expiryAttr)
+	return err == nil
+}
+
+// isExpired returns true if an item expired before now (epoch nanoseconds)
+func isExpired(item map[string]interface{}, now int64) bool {
+	switch expiry := item[expiryAttr].(type) {
+	case int:
+		return int64(expiry) <= now
+	case int64:
+		return expiry <= now
+	case float64:
+		return expiry <= float64(now)
+	}
+
+	return false
+}
+
+// purgeExpired is the purge_expired exec command, it deletes the items that
+// expired
+func (kv *Backend) purgeExpired(request *frames.ExecRequest) error {
+	container, tablePath, err := kv.newConnection(request.Proto.Session, request.Password.Get(), request.Token.Get(), request.Proto.Table, true)
+	if err != nil {
+		return err
+	}
+
+	partitions, err := kv.getPartitions(tablePath, container, nil)
+	if err != nil {
+		return err
+	}
+
+	getItemsWorkers, deleteWorkers := kv.numWorkers, kv.numWorkers*kv.updateWorkersPerVN
+	if getItemsWorkers < 1 {
+		getItemsWorkers = 1
+	}
+	if deleteWorkers < 1 {
+		deleteWorkers = 1
+	}
+
+	filter := fmt.Sprintf("%s <= %d", expiryAttr, time.Now().UnixNano())
+	for _, partition := range partitions {
+		err := v3ioutils.DeleteTable(kv.logger, container, partition, filter, getItemsWorkers, deleteWorkers, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+)
+
+type TTLTestSuite struct {
+	suite.Suite
+	backend *testBackend
+}
+
+func (suite *TTLTestSuite) SetupTest() {
+	suite.backend = newTestBackend(suite.T())
+}
+
+// write writes items with a city and an expiry time column
+func (suite *TTLTestSuite) write(keys []string, cities []string, expires []time.Time, ttl string) error {
+	keyCol, err := frames.NewSliceColumn("key", keys)
+	suite.Require().NoError(err)
+	cityCol, err := frames.NewSliceColumn("city", cities)
+	suite.Require().NoError(err)
+	expiresCol, err := frames.NewSliceColumn("expires", expires)
+	suite.Require().NoError(err)
+	frame, err := frames.NewFrame([]frames.Column{cityCol, expiresCol}, []frames.Column{keyCol}, nil)
+	suite.Require().NoError(err)
+
+	request := &frames.WriteRequest{
+		Session:       &frames.Session{Container: "bigdata"},
+		Password:      frames.InitSecretString(""),
+		Token:         frames.InitSecretString(""),
+		Table:         "table",
+		ImmidiateData: frame,
+		SaveMode:      frames.OverwriteItem,
+		TTL:           ttl,
+	}
+	appender, err := suite.backend.Write(request)
+	if err != nil {
+		return err
+	}
+	return appender.WaitForComplete(time.Second)
+}
+
+func (suite *TTLTestSuite) exec(command string, attribute string) {
+	request := &frames.ExecRequest{
+		Proto: &pb.ExecRequest{
+			Session: &frames.Session{Container: "bigdata"},
+			Backend: "kv",
+			Table:   "table",
+			Command: command,
+			Args: map[string]*pb.Value{
+				"attribute": {Value: &pb.Value_Sval{Sval: attribute}},
+			},
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+
+	_, err := suite.backend.Exec(request)
+	suite.Require().NoError(err)
+}
+
+// read returns the keys of the items matching filter
+func (suite *TTLTestSuite) read(filter string, includeExpired bool) []string {
+	request := &frames.ReadRequest{
+		Proto: &pb.ReadRequest{
+			Session:        &frames.Session{Container: "bigdata"},
+			Table:          "table",
+			Filter:         filter,
+			IncludeExpired: includeExpired,
+		},
+		Password: frames.InitSecretString(""),
+		Token:    frames.InitSecretString(""),
+	}
+	iter, err := suite.backend.Read(request)
+	suite.Require().NoError(err)
+
+	keys := []string{}
+	for iter.Next() {
+		col := iter.At().Indices()[0]
+		for i := 0; i < col.Len(); i++ {
+			key, err := col.StringAt(i)
+			suite.Require().NoError(err)
+			keys = append(keys, key)
+		}
+	}
+	suite.Require().NoError(iter.Err())
+	sort.Strings(keys)
+	return keys
+}
+
+func (suite *TTLTestSuite) TestTTLColumn() {
+	now := time.Now()
+	err := suite.write([]string{"a", "b", "c"}, []string{"NY", "NY", "LA"},
+		[]time.Time{now.Add(-time.Hour), now.Add(time.Hour), now.Add(-time.Minute)}, "expires")
+	suite.Require().NoError(err)
+
+	suite.Require().Equal([]string{"b"}, suite.read("", false))
+	suite.Require().Equal([]string{"a", "b", "c"}, suite.read("", true))
+	suite.Require().Equal([]string{"b"}, suite.read("city == 'NY'", false))
+	suite.Require().Equal([]string{}, suite.read("city == 'LA'", false))
+
+	suite.exec("create_index", "city")
+	suite.Require().Equal([]string{"b"}, suite.read("city == 'NY'", false))
+	suite.Require().Equal([]string{"a", "b"}, suite.read("city == 'NY'", true))
+
+	suite.exec("purge_expired", "")
+	suite.Require().Equal([]string{"b"}, suite.read("", true))
+}
+
+func (suite *TTLTestSuite) TestTTLDuration() {
+	before := time.Now().Add(time.Hour).UnixNano()
+	err := suite.write([]string{"a"}, []string{"NY"}, []time.Time{time.Now().Add(-time.Hour)}, "1h")
+	suite.Require().NoError(err)
+
+	suite.Require().Equal([]string{"a"}, suite.read("", false))
+	expiry, ok := suite.backend.getItem("table/a")[expiryAttr].(int)
+	suite.Require().True(ok)
+	suite.Require().True(int64(expiry) >= before)
+}
+
+func (suite *TTLTestSuite) TestTTLExpression() {
+	err := suite.write([]string{"a"}, []string{"NY"}, []time.Time{time.Now()}, "")
+	suite.Require().NoError(err)
+
+	keyCol, err := frames.NewSliceColumn("key", []string{"a"})
+	suite.Require().NoError(err)
+	cityCol, err := frames.NewSliceColumn("new_city", []string{"LA"})
+	suite.Require().NoError(err)
+	frame, err := frames.NewFrame([]frames.Column{cityCol}, []frames.Column{keyCol}, nil)
+	suite.Require().NoError(err)
+	request := &frames.WriteRequest{
+		Session:    &frames.Session{Container: "bigdata"},
+		Password:   frames.InitSecretString(""),
+		Token:      frames.InitSecretString(""),
+		Table:      "table",
+		Expression: "city={new_city}",
+		SaveMode:   frames.UpdateItem,
+		TTL:        "1h",
+	}
+	appender, err := suite.backend.Write(request)
+	suite.Require().NoError(err)
+	suite.Require().NoError(appender.Add(frame))
+	suite.Require().NoError(appender.WaitForComplete(time.Second))
+
+	item := suite.backend.getItem("table/a")
+	suite.Require().Equal("LA", item["city"])
+	suite.Require().Contains(item, expiryAttr)
+	suite.Require().Equal([]string{"a"}, suite.read("", false))
+}
+
+func (suite *TTLTestSuite) TestBadTTL() {
+	for _, ttl := range []string{"-1h", "3600", "city", "no_such_column"} {
+		err := suite.write([]string{"a"}, []string{"NY"}, []time.Time{time.Now()}, ttl)
+		suite.Require().Error(err, ttl)
+	}
+}
+
+func TestTTLTestSuite(t *testing.T) {
+	suite.Run(t, new(TTLTestSuite))
+}
@@ -24,7 +24,9 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nuclio/logger"
@@ -48,6 +50,13 @@ type Appender struct {
 	schema        v3ioutils.V3ioSchema
 	asyncErr      error
 	rowsProcessed int
+	pending       sync.WaitGroup // update requests not done yet
+	rowErrors     []frames.RowError
+	rowErrorsLock sync.Mutex
+	indexes       []v3ioutils.OldSchemaField // of the table, see index.go
+	schemaMode    string                     // see enforceSchema
+	collect       bool                       // collect update requests instead of sending them
+	collected     []*v3io.UpdateItemInput
 }
 
 const (
@@ -60,13 +69,20 @@ const (
 
 var (
 	validColumnNamePattern = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+	errorCodePattern       = regexp.MustCompile(`"?ErrorCode"?\s*:\s*(-?\d+)`)
+	errorMessagePattern    = regexp.MustCompile(`"ErrorMessage"\s*:\s*"([^"]*)"`)
 )
 
 var allowedWriteRequestFields = map[string]bool{
-	"Expression":    true,
-	"Condition":     true,
-	"PartitionKeys": true,
-	"SaveMode":      true,
+	"Expression":      true,
+	"Condition":       true,
+	"PartitionKeys":   true,
+	"SaveMode":        true,
+	"ContinueOnError": true,
+	"TimeZone":        true,
+	"TimePrecision":   true,
+	"TTL":             true,
+	"SchemaMode":      true,
 }
 
 // Write supports writing to the backend
@@ -77,6 +93,21 @@ func (kv *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, er
 		return nil, err
 	}
 
+	err = v3ioutils.ValidateTimeFormat(request.TimeZone, request.TimePrecision)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateTTL(request.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateSchemaMode(request.SchemaMode)
+	if err != nil {
+		return nil, err
+	}
+
 	container, tablePath, err := kv.newConnection(request.Session, request.Password.Get(), request.Token.Get(), request.Table, true)
 	if err != nil {
 		return nil, err
@@ -120,6 +151,14 @@ func (kv *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, er
 		schema = v3ioutils.NewSchema(v3ioutils.DefaultKeyColumn, "")
 	}
 
+	schemaMode := request.SchemaMode
+	if schemaMode == "" {
+		schemaMode = schema.(*v3ioutils.OldV3ioSchema).Mode
+	}
+	if schemaMode == "" {
+		schemaMode = frames.SchemaModeEvolve
+	}
+
 	numUpdateWorkers := kv.numWorkers * kv.updateWorkersPerVN
 
 	appender := Appender{
@@ -130,6 +169,8 @@ func (kv *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, er
 		doneChan:    make(chan struct{}, 1),
 		logger:      kv.logger,
 		schema:      schema,
+		indexes:     indexFields(schema.(*v3ioutils.OldV3ioSchema)),
+		schemaMode:  schemaMode,
 	}
 
 	internalDoneChan := make(chan struct{}, numUpdateWorkers)
@@ -268,21 +309,53 @@ func (a *Appender) Add(frame frames.Frame) error {
 		if err != nil {
 			return err
 		}
+		columns[validColName(name)] = col
+	}
+	labels := make(map[string]interface{}, len(frame.Labels()))
+	for name, val := range frame.Labels() {
+		labels[name] = val
+	}
+	err = a.enforceSchema(frame, columns, labels)
+	if err != nil {
+		return err
+	}
+	for _, name := range frame.Names() {
 		name = validColName(name)
-		err = newSchema.AddColumn(name, col, true)
+		err = newSchema.AddColumn(name, columns[name], true)
 		if err != nil {
 			return err
 		}
-		columns[name] = col
 	}
-	for name, val := range frame.Labels() {
+	expiryCol, isNull, err := a.expiryColumn(frame)
+	if err != nil {
+		return err
+	}
+	if expiryCol != nil {
+		err = newSchema.AddColumn(expiryAttr, expiryCol, true)
+		if err != nil {
+			return err
+		}
+		columns[expiryAttr] = expiryCol
+	}
+	for name, val := range labels {
 		err := newSchema.AddField(name, val, true)
 		if err != nil {
 			return err
 		}
 	}
+	a.setTimeFormat(newSchema.(*v3ioutils.OldV3ioSchema), columns)
 
-	err = a.schema.UpdateSchema(a.container, a.tablePath, newSchema)
+	if a.collect {
+		// Batch writes save the schema once the batch is committed
+		_, err = a.schema.(*v3ioutils.OldV3ioSchema).Merge(newSchema)
+	} else {
+		err = a.schema.UpdateSchema(a.container, a.tablePath, newSchema)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = a.truncateTimes(columns)
 	if err != nil {
 		return err
 	}
@@ -300,12 +373,12 @@ func (a *Appender) Add(frame frames.Frame) error {
 
 		if a.request.SaveMode == frames.UpdateItem {
 			var expressionStr string
-			expressionStr, keyVal, sortingKeyVal, err = getUpdateExpressionFromRow(columns, r, frame.IsNull,
+			expressionStr, keyVal, sortingKeyVal, err = getUpdateExpressionFromRow(columns, r, isNull,
 				indexVal, sortingFunc,
 				indexName, sortingKeyName)
 			expression = &expressionStr
 		} else {
-			rowMap, keyVal, sortingKeyVal, err = getMapFromRow(columns, r, frame.IsNull,
+			rowMap, keyVal, sortingKeyVal, err = getMapFromRow(columns, r, isNull,
 				indexVal, sortingFunc,
 				indexName, sortingKeyName)
 		}
@@ -355,13 +428,25 @@ func (a *Appender) Add(frame frames.Frame) error {
 			Condition:  condition,
 			UpdateMode: a.request.SaveMode.GetNginxModeName()}
 		a.logger.DebugWith("write", "input", input)
-		a.requestChan <- &input
+		a.send(&input)
 	}
 
 	a.rowsProcessed += frame.Len()
 	return nil
 }
 
+// send sends an update request to the update workers, or collects it (see
+// BatchWrite)
+func (a *Appender) send(input *v3io.UpdateItemInput) {
+	if a.collect {
+		a.collected = append(a.collected, input)
+		return
+	}
+
+	a.pending.Add(1)
+	a.requestChan <- input
+}
+
 func (a *Appender) formatKeyName(key interface{}, sortingVal interface{}) string {
 	var formattedKey string
 	formattedKey = valueToKeyString(key)
@@ -371,20 +456,94 @@ func (a *Appender) formatKeyName(key interface{}, sortingVal interface{}) string
 	return formattedKey
 }
 
+// keyFunc returns a function returning the item name (key and sorting key)
+// of a frame row
+func (a *Appender) keyFunc(frame frames.Frame) (func(int) string, error) {
+	indexVal, err := a.indexValFunc(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(frame.Indices()) < 2 {
+		return func(r int) string {
+			return a.formatKeyName(indexVal(r), nil)
+		}, nil
+	}
+
+	sortingFunc, err := a.funcFromCol(frame.Indices()[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r int) string {
+		return a.formatKeyName(indexVal(r), sortingFunc(r))
+	}, nil
+}
+
+// setTimeFormat sets the request time zone and precision on the schema
+// fields of the written time columns
+func (a *Appender) setTimeFormat(schema *v3ioutils.OldV3ioSchema, columns map[string]frames.Column) {
+	if a.request.TimeZone == "" && a.request.TimePrecision == "" {
+		return
+	}
+
+	for i, field := range schema.Fields {
+		if col, ok := columns[field.Name]; ok && col.DType() == frames.TimeType {
+			schema.Fields[i].TimeZone = a.request.TimeZone
+			schema.Fields[i].Precision = a.request.TimePrecision
+		}
+	}
+}
+
+// truncateTimes truncates the time columns to their schema precision
+func (a *Appender) truncateTimes(columns map[string]frames.Column) error {
+	schema := a.schema.(*v3ioutils.OldV3ioSchema)
+	for name, col := range columns {
+		if col.DType() != frames.TimeType {
+			continue
+		}
+
+		field, err := schema.GetField(name)
+		if err != nil || field.Precision == "" || field.Precision == "ns" {
+			continue
+		}
+
+		times, err := col.Times()
+		if err != nil {
+			return err
+		}
+
+		truncated := make([]time.Time, len(times))
+		for i, t := range times {
+			truncated[i] = field.TruncateTime(t)
+		}
+
+		columns[name], err = frames.NewSliceColumn(name, truncated)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // update updates rows from a frame
 func (a *Appender) update(frame frames.Frame) error {
-	indexVal, err := a.indexValFunc(frame)
+	keyFunc, err := a.keyFunc(frame)
 	if err != nil {
 		return err
 	}
 
-	var sortingFunc func(int) interface{}
-	if len(frame.Indices()) > 1 {
-		sortingFunc, err = a.funcFromCol(frame.Indices()[1])
-		if err != nil {
+	expiry, err := a.expiryFunc(frame)
+	if err != nil {
+		return err
+	}
+	if expiry != nil {
+		if err := a.addExpiryField(); err != nil {
 			return err
 		}
 	}
+
 	for r := 0; r < frame.Len(); r++ {
 
 		var expr *string
@@ -394,6 +553,11 @@ func (a *Appender) update(frame frames.Frame) error {
 				a.logger.ErrorWith("error generating expression", "error", err)
 				return err
 			}
+			if expiry != nil {
+				if expiryTime, ok := expiry(r); ok {
+					exprString = withExpiry(exprString, expiryTime)
+				}
+			}
 			expr = &exprString
 		}
 
@@ -406,18 +570,12 @@ func (a *Appender) update(frame frames.Frame) error {
 			}
 		}
 
-		key := indexVal(r)
-		var sortingVal interface{}
-		if len(frame.Indices()) > 1 {
-			sortingVal = sortingFunc(r)
-		}
-
-		input := v3io.UpdateItemInput{Path: a.tablePath + a.formatKeyName(key, sortingVal),
+		input := v3io.UpdateItemInput{Path: a.tablePath + keyFunc(r),
 			Expression: expr,
 			Condition:  cond,
 			UpdateMode: a.request.SaveMode.GetNginxModeName()}
 		a.logger.DebugWith("write update", "input", input)
-		a.requestChan <- &input
+		a.send(&input)
 	}
 
 	return nil
@@ -489,6 +647,27 @@ func (a *Appender) WaitForComplete(timeout time.Duration) error {
 	}
 }
 
+// Flush waits for the rows added so far to be updated
+func (a *Appender) Flush(timeout time.Duration) error {
+	maxWaitTime := timeout
+	if maxWaitTime <= 0 {
+		maxWaitTime = 24 * time.Hour
+	}
+
+	done := make(chan struct{})
+	go func() {
+		a.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return a.asyncErr
+	case <-time.After(maxWaitTime):
+		return errors.Errorf("The operation timed out after %.2f seconds.", maxWaitTime.Seconds())
+	}
+}
+
 func (a *Appender) Close() {
 }
 
@@ -548,8 +727,10 @@ func (a *Appender) updateItemWorker(doneChan chan<- struct{}) {
 
 		resp, err := a.container.UpdateItemSync(req)
 		if err != nil {
-			// If condition evaluated to false, log this and discard error
-			if isFalseConditionError(err) {
+			if a.request.ContinueOnError {
+				a.addRowError(req.Path, err)
+			} else if isFalseConditionError(err) {
+				// If condition evaluated to false, log this and discard error
 				a.logger.Info("condition for item '%v' evaluated to false", req)
 			} else if isOnlyNewItemUpdateModeItemExistError(err, req.UpdateMode) {
 				a.logger.Info("trying to write to an existing item with update mode 'CreateNewItemsOnly' (item: '%v')", req)
@@ -559,12 +740,65 @@ func (a *Appender) updateItemWorker(doneChan chan<- struct{}) {
 			}
 		} else {
 			resp.Release()
+			if len(a.indexes) > 0 {
+				if err := a.indexItem(req.Path); err != nil {
+					if a.request.ContinueOnError {
+						a.addRowError(req.Path, err)
+					} else {
+						a.logger.ErrorWith("failed to index item", "error", err)
+						a.asyncErr = err
+					}
+				}
+			}
 		}
+		a.pending.Done()
 	}
 
 	doneChan <- struct{}{}
 }
 
+// addRowError adds the error of an item update
+func (a *Appender) addRowError(path string, err error) {
+	a.logger.DebugWith("failed to update item", "path", path, "error", err)
+	rowError := frames.RowError{
+		Key:     strings.TrimPrefix(path, a.tablePath),
+		Code:    rowErrorCode(err),
+		Message: err.Error(),
+	}
+
+	if matches := errorMessagePattern.FindAllStringSubmatch(rowError.Message, -1); len(matches) > 0 {
+		rowError.Message = matches[len(matches)-1][1]
+	}
+
+	a.rowErrorsLock.Lock()
+	a.rowErrors = append(a.rowErrors, rowError)
+	a.rowErrorsLock.Unlock()
+}
+
+// TakeRowErrors returns the rows that failed since the last call
+func (a *Appender) TakeRowErrors() []frames.RowError {
+	a.rowErrorsLock.Lock()
+	defer a.rowErrorsLock.Unlock()
+
+	rowErrors := a.rowErrors
+	a.rowErrors = nil
+	return rowErrors
+}
+
+// rowErrorCode returns the (last) V3IO error code of a failed update, or the
+// HTTP status code if there's none
+func rowErrorCode(err error) string {
+	if matches := errorCodePattern.FindAllStringSubmatch(err.Error(), -1); len(matches) > 0 {
+		return matches[len(matches)-1][1]
+	}
+
+	if errorWithStatus, ok := err.(v3ioerrors.ErrorWithStatusCode); ok {
+		return strconv.Itoa(errorWithStatus.StatusCode())
+	}
+
+	return ""
+}
+
 // Check whether the current error was caused specifically because the condition was evaluated to false.
 func isFalseConditionError(err error) bool {
 	errString := err.Error()
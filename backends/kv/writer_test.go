@@ -25,9 +25,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
 	"github.com/v3io/frames"
 	"github.com/v3io/frames/test"
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+	v3ioerrors "github.com/v3io/v3io-go/pkg/errors"
 )
 
 type WriterTestSuite struct {
@@ -177,6 +180,56 @@ func (suite *WriterTestSuite) TestValidateFrameInputColumnNameTooLong() {
 	suite.Require().Equal(fmt.Sprintf("column '%v' exceeding maximum allowed attribute name of %v", columnName, maximumAttributeNameLength), err.Error())
 }
 
+// rowErrorsContainer fails updates of items in errs
+type rowErrorsContainer struct {
+	v3io.Container
+	errs map[string]error
+}
+
+func (c *rowErrorsContainer) UpdateItemSync(input *v3io.UpdateItemInput) (*v3io.Response, error) {
+	if err, ok := c.errs[input.Path]; ok {
+		return nil, err
+	}
+	return &v3io.Response{}, nil
+}
+
+func (suite *WriterTestSuite) TestContinueOnError() {
+	logger, err := frames.NewLogger("error")
+	suite.Require().NoError(err)
+
+	conditionErr := errors.New(`Failed POST with status 400: {"ErrorCode": -201326594, "ErrorMessage": "Condition evaluated to false"}`)
+	statusErr := v3ioerrors.NewErrorWithStatusCode(errors.New("service unavailable"), 503)
+	appender := &Appender{
+		request:   &frames.WriteRequest{ContinueOnError: true},
+		tablePath: "table/",
+		container: &rowErrorsContainer{
+			errs: map[string]error{
+				"table/b": conditionErr,
+				"table/c": statusErr,
+			},
+		},
+		requestChan: make(chan *v3io.UpdateItemInput, 3),
+		logger:      logger,
+	}
+
+	for _, key := range []string{"a", "b", "c"} {
+		appender.pending.Add(1)
+		appender.requestChan <- &v3io.UpdateItemInput{Path: "table/" + key}
+	}
+	close(appender.requestChan)
+
+	doneChan := make(chan struct{}, 1)
+	appender.updateItemWorker(doneChan)
+
+	suite.Require().NoError(appender.asyncErr)
+	expected := []frames.RowError{
+		{Key: "b", Code: "-201326594", Message: "Condition evaluated to false"},
+		{Key: "c", Code: "503", Message: "service unavailable"},
+	}
+	suite.Require().Equal(expected, appender.TakeRowErrors())
+	suite.Require().Empty(appender.TakeRowErrors())
+}
+
 func TestWriterTestSuite(t *testing.T) {
 	suite.Run(t, new(WriterTestSuite))
 }
@@ -169,7 +169,7 @@ func (i *streamIterator) Next() bool {
 	i.nextLocation = output.NextLocation
 	i.isLast = i.isLast || (output.RecordsBehindLatest == 0)
 
-	// TODO: add timeout option, keep polling on stream for t more time
+	// Continuous polling is done by the HTTP tail endpoint (see http/tail.go)
 	return true
 }
 
@@ -109,6 +109,11 @@ func (a *streamAppender) WaitForComplete(timeout time.Duration) error {
 	return nil
 }
 
+// Flush returns immediately, records are put synchronously by Add
+func (a *streamAppender) Flush(timeout time.Duration) error {
+	return nil
+}
+
 func (a *streamAppender) Close() {
 	a.closed = true
 }
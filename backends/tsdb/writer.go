@@ -287,6 +287,12 @@ func (a *tsdbAppender) WaitForComplete(timeout time.Duration) error {
 	return err
 }
 
+// Flush waits for the samples added so far to be written
+func (a *tsdbAppender) Flush(timeout time.Duration) error {
+	_, err := a.appender.WaitForCompletion(timeout)
+	return err
+}
+
 func (a *tsdbAppender) Close() {
 	a.appender.Close()
 }
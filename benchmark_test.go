@@ -72,7 +72,7 @@ func BenchmarkRead_gRPC(b *testing.B) {
 	b.StartTimer()
 
 	for i := 0; i < b.N; i++ {
-		read(c, b)
+		read(frames.NewV1Client(c), b)
 	}
 }
 
@@ -87,7 +87,7 @@ func BenchmarkRead_HTTP(b *testing.B) {
 	b.StartTimer()
 
 	for i := 0; i < b.N; i++ {
-		read(c, b)
+		read(frames.NewV1Client(c), b)
 	}
 }
 
@@ -118,7 +118,7 @@ func BenchmarkWrite_gRPC(b *testing.B) {
 	frame := csvFrame(b, wRows)
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
-		write(c, wreq, frame, b)
+		write(frames.NewV1Client(c), wreq, frame, b)
 	}
 }
 
@@ -134,6 +134,6 @@ func BenchmarkWrite_HTTP(b *testing.B) {
 	frame := csvFrame(b, wRows)
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
-		write(c, wreq, frame, b)
+		write(frames.NewV1Client(c), wreq, frame, b)
 	}
 }
@@ -21,9 +21,13 @@ such restriction.
 package frames
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/v3io/frames/pb"
@@ -41,6 +45,206 @@ type Client interface {
 	Delete(request *pb.DeleteRequest) error
 	// Exec executes a command on the backend
 	Exec(request *pb.ExecRequest) (Frame, error)
+	// History returns the request history log
+	History(request *pb.HistoryRequest) (FrameIterator, error)
+	// Version returns the server version
+	Version() (string, error)
+	// BatchWrite writes frames to several tables, either all of them are
+	// written or none is
+	BatchWrite(request *BatchWriteRequest) (*BatchWriteResult, error)
+}
+
+// ClientV2 is a context aware client interface. Idempotent calls (Read,
+// Create with IfExists=IGNORE, Delete with IfMissing=IGNORE, History and
+// Version) are retried according to the client RetryPolicy.
+type ClientV2 interface {
+	// Read reads data from server
+	Read(ctx context.Context, request *pb.ReadRequest) (FrameIterator, error)
+	// Write writes data to server, ctx is used for the whole write
+	Write(ctx context.Context, request *WriteRequest) (FrameAppender, error)
+	// Create creates a table
+	Create(ctx context.Context, request *pb.CreateRequest) error
+	// Delete deletes data or table
+	Delete(ctx context.Context, request *pb.DeleteRequest) error
+	// Exec executes a command on the backend
+	Exec(ctx context.Context, request *pb.ExecRequest) (Frame, error)
+	// History returns the request history log
+	History(ctx context.Context, request *pb.HistoryRequest) (FrameIterator, error)
+	// Version returns the server version
+	Version(ctx context.Context) (string, error)
+	// BatchWrite writes frames to several tables, either all of them are
+	// written or none is. The result has the outcome of every table, also
+	// when the batch is aborted (with an error). Credentials are taken from
+	// the request session.
+	BatchWrite(ctx context.Context, request *BatchWriteRequest) (*BatchWriteResult, error)
+}
+
+// NewV1Client returns a Client calling client with a background context
+func NewV1Client(client ClientV2) Client {
+	return &v1Client{client}
+}
+
+type v1Client struct {
+	client ClientV2
+}
+
+func (c *v1Client) Read(request *pb.ReadRequest) (FrameIterator, error) {
+	return c.client.Read(context.Background(), request)
+}
+
+func (c *v1Client) Write(request *WriteRequest) (FrameAppender, error) {
+	return c.client.Write(context.Background(), request)
+}
+
+func (c *v1Client) Create(request *pb.CreateRequest) error {
+	return c.client.Create(context.Background(), request)
+}
+
+func (c *v1Client) Delete(request *pb.DeleteRequest) error {
+	return c.client.Delete(context.Background(), request)
+}
+
+func (c *v1Client) Exec(request *pb.ExecRequest) (Frame, error) {
+	return c.client.Exec(context.Background(), request)
+}
+
+func (c *v1Client) History(request *pb.HistoryRequest) (FrameIterator, error) {
+	return c.client.History(context.Background(), request)
+}
+
+func (c *v1Client) Version() (string, error) {
+	return c.client.Version(context.Background())
+}
+
+func (c *v1Client) BatchWrite(request *BatchWriteRequest) (*BatchWriteResult, error) {
+	return c.client.BatchWrite(context.Background(), request)
+}
+
+// ClientConfig is common client configuration, set by ClientOption
+type ClientConfig struct {
+	// Compression of requests, also requested from the server for responses
+	// (NoCompression, GzipCompression or ZstdCompression)
+	Compression string
+	// Retry policy of idempotent calls
+	Retry RetryPolicy
+	// Maximal number of connections per server (0 is transport default)
+	MaxConnsPerHost int
+	// TLS configuration, nil for plain connections (the HTTP client then
+	// accepts any server certificate for https URLs)
+	TLSConfig *tls.Config
+	// Timeout for establishing connections (0 is transport default)
+	DialTimeout time.Duration
+	// Timeout of Create, Delete, Exec and Version calls when the context has
+	// no deadline (0 means no timeout)
+	Timeout time.Duration
+	// Maximal message size in bytes (0 is transport default)
+	MaxMessageSize int
+}
+
+// ClientOption is a client configuration option
+type ClientOption func(*ClientConfig)
+
+// WithCompression sets the client compression
+func WithCompression(compression string) ClientOption {
+	return func(config *ClientConfig) {
+		config.Compression = compression
+	}
+}
+
+// WithRetryPolicy sets the retry policy of idempotent calls
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(config *ClientConfig) {
+		config.Retry = policy
+	}
+}
+
+// WithMaxConnsPerHost sets the maximal number of connections per server
+func WithMaxConnsPerHost(n int) ClientOption {
+	return func(config *ClientConfig) {
+		config.MaxConnsPerHost = n
+	}
+}
+
+// WithTLSConfig sets the TLS configuration
+func WithTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(config *ClientConfig) {
+		config.TLSConfig = tlsConfig
+	}
+}
+
+// WithDialTimeout sets the connection timeout
+func WithDialTimeout(timeout time.Duration) ClientOption {
+	return func(config *ClientConfig) {
+		config.DialTimeout = timeout
+	}
+}
+
+// WithTimeout sets the timeout of Create, Delete, Exec and Version calls
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(config *ClientConfig) {
+		config.Timeout = timeout
+	}
+}
+
+// WithMaxMessageSize sets the maximal message size
+func WithMaxMessageSize(size int) ClientOption {
+	return func(config *ClientConfig) {
+		config.MaxMessageSize = size
+	}
+}
+
+// NewClientConfig returns client configuration from options
+func NewClientConfig(options ...ClientOption) (*ClientConfig, error) {
+	config := &ClientConfig{
+		Retry: DefaultRetryPolicy(),
+	}
+	for _, option := range options {
+		option(config)
+	}
+
+	if err := ValidateCompression(config.Compression); err != nil {
+		return nil, err
+	}
+
+	if err := config.Retry.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.MaxConnsPerHost < 0 {
+		return nil, fmt.Errorf("bad MaxConnsPerHost - %d", config.MaxConnsPerHost)
+	}
+
+	if config.DialTimeout < 0 || config.Timeout < 0 {
+		return nil, fmt.Errorf("negative timeout")
+	}
+
+	if config.MaxMessageSize < 0 {
+		return nil, fmt.Errorf("bad MaxMessageSize - %d", config.MaxMessageSize)
+	}
+
+	return config, nil
+}
+
+// CallContext returns ctx with the configured call timeout, unless ctx
+// already has a deadline
+func (config *ClientConfig) CallContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || config.Timeout == 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, config.Timeout)
+}
+
+// SplitAddresses splits a comma separated list of server addresses
+func SplitAddresses(addresses string) []string {
+	var out []string
+	for _, address := range strings.Split(addresses, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			out = append(out, address)
+		}
+	}
+
+	return out
 }
 
 // SessionFromEnv return a session from V3IO_SESSION environment variable (JSON encoded)
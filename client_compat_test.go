@@ -0,0 +1,136 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/v3io/frames"
+	_ "github.com/v3io/frames/embedded"
+	"github.com/v3io/frames/grpc"
+	"github.com/v3io/frames/http"
+	"github.com/v3io/frames/pb"
+)
+
+const compatVersion = "compat-test"
+
+// newCompatClients starts in-process gRPC and HTTP servers (with a CSV
+// backend) and returns a client for each, and an embedded client, all created
+// by frames.NewClient
+func newCompatClients(t *testing.T) map[string]frames.Client {
+	root := setupRoot(t)
+
+	grpcPort, httpPort := freePort(t), freePort(t)
+	grpcServer, err := grpc.NewServer(genConfig(root, nil), fmt.Sprintf(":%d", grpcPort), nil, nil, compatVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := grpcServer.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer, err := http.NewServer(genConfig(root, nil), fmt.Sprintf(":%d", httpPort), nil, nil, compatVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := httpServer.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForServer(t, grpcPort)
+	waitForServer(t, httpPort)
+
+	configPath := fmt.Sprintf("%s/%s", root, configFile)
+	encodeConfig(t, genConfig(root, nil), configPath)
+
+	urls := map[string]string{
+		"grpc":     fmt.Sprintf("grpc://localhost:%d", grpcPort),
+		"http":     fmt.Sprintf("http://localhost:%d", httpPort),
+		"embedded": "embedded://" + configPath,
+	}
+
+	clients := make(map[string]frames.Client)
+	for name, url := range urls {
+		client, err := frames.NewClient(url, &frames.Session{}, nil)
+		if err != nil {
+			t.Fatalf("%s: can't create client - %s", name, err)
+		}
+		clients[name] = frames.NewV1Client(client)
+	}
+
+	return clients
+}
+
+func TestClientCompat(t *testing.T) {
+	clients := newCompatClients(t)
+	rowCounts := make(map[string]int)
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			version, err := client.Version()
+			if err != nil {
+				t.Fatalf("can't get version - %s", err)
+			}
+
+			if name != "embedded" && version != compatVersion {
+				t.Fatalf("bad version: %q != %q", version, compatVersion)
+			}
+
+			it, err := client.Read(&pb.ReadRequest{Backend: "csv", Table: "weather.csv", MessageLimit: 100})
+			if err != nil {
+				t.Fatalf("can't read - %s", err)
+			}
+
+			for it.Next() {
+				rowCounts[name] += it.At().Len()
+			}
+
+			if err := it.Err(); err != nil {
+				t.Fatalf("read error - %s", err)
+			}
+
+			// The servers run without a history server, both transports
+			// should report the server error
+			err = readHistory(client)
+			if err == nil || !strings.Contains(err.Error(), "history server") {
+				t.Fatalf("bad history error - %v", err)
+			}
+		})
+	}
+
+	if rowCounts["grpc"] == 0 || rowCounts["grpc"] != rowCounts["http"] || rowCounts["grpc"] != rowCounts["embedded"] {
+		t.Fatalf("row count mismatch - %v", rowCounts)
+	}
+}
+
+func readHistory(client frames.Client) error {
+	it, err := client.History(&pb.HistoryRequest{Backend: "csv", Table: "weather.csv"})
+	if err != nil {
+		return err
+	}
+
+	for it.Next() {
+	}
+
+	return it.Err()
+}
@@ -21,7 +21,7 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   package='pb',
   syntax='proto3',
   serialized_options=None,
-  serialized_pb=_b('\n\x0c\x66rames.proto\x12\x02pb\"\xc8\x01\n\x06\x43olumn\x12\x1d\n\x04kind\x18\x01 \x01(\x0e\x32\x0f.pb.Column.Kind\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x18\n\x05\x64type\x18\x03 \x01(\x0e\x32\t.pb.DType\x12\x0c\n\x04size\x18\x04 \x01(\x03\x12\x0c\n\x04ints\x18\x05 \x03(\x03\x12\x0e\n\x06\x66loats\x18\x06 \x03(\x01\x12\x0f\n\x07strings\x18\x07 \x03(\t\x12\r\n\x05times\x18\x08 \x03(\x03\x12\r\n\x05\x62ools\x18\t \x03(\x08\"\x1c\n\x04Kind\x12\t\n\x05SLICE\x10\x00\x12\t\n\x05LABEL\x10\x01\"`\n\x05Value\x12\x0e\n\x04ival\x18\x01 \x01(\x03H\x00\x12\x0e\n\x04\x66val\x18\x02 \x01(\x01H\x00\x12\x0e\n\x04sval\x18\x03 \x01(\tH\x00\x12\x0e\n\x04tval\x18\x04 \x01(\x03H\x00\x12\x0e\n\x04\x62val\x18\x05 \x01(\x08H\x00\x42\x07\n\x05value\"|\n\rNullValuesMap\x12\x37\n\x0bnullColumns\x18\x01 \x03(\x0b\x32\".pb.NullValuesMap.NullColumnsEntry\x1a\x32\n\x10NullColumnsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x08:\x02\x38\x01\"\xd9\x01\n\x05\x46rame\x12\x1b\n\x07\x63olumns\x18\x01 \x03(\x0b\x32\n.pb.Column\x12\x1b\n\x07indices\x18\x02 \x03(\x0b\x32\n.pb.Column\x12%\n\x06labels\x18\x03 \x03(\x0b\x32\x15.pb.Frame.LabelsEntry\x12\r\n\x05\x65rror\x18\x04 \x01(\t\x12&\n\x0bnull_values\x18\x05 \x03(\x0b\x32\x11.pb.NullValuesMap\x1a\x38\n\x0bLabelsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x18\n\x05value\x18\x02 \x01(\x0b\x32\t.pb.Value:\x02\x38\x01\"\xc5\x01\n\x0bSchemaField\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0b\n\x03\x64oc\x18\x02 \x01(\t\x12\x1a\n\x07\x64\x65\x66\x61ult\x18\x03 \x01(\x0b\x32\t.pb.Value\x12\x0c\n\x04type\x18\x04 \x01(\t\x12\x33\n\nproperties\x18\x05 \x03(\x0b\x32\x1f.pb.SchemaField.PropertiesEntry\x1a<\n\x0fPropertiesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x18\n\x05value\x18\x02 \x01(\x0b\x32\t.pb.Value:\x02\x38\x01\"6\n\tSchemaKey\x12\x14\n\x0csharding_key\x18\x01 \x03(\t\x12\x13\n\x0bsorting_key\x18\x02 \x03(\t\"\x97\x01\n\x0bTableSchema\x12\x0c\n\x04type\x18\x01 \x01(\t\x12\x11\n\tnamespace\x18\x02 \x01(\t\x12\x0c\n\x04name\x18\x03 \x01(\t\x12\x0b\n\x03\x64oc\x18\x04 \x01(\t\x12\x0f\n\x07\x61liases\x18\x05 \x03(\t\x12\x1f\n\x06\x66ields\x18\x06 \x03(\x0b\x32\x0f.pb.SchemaField\x12\x1a\n\x03key\x18\x07 \x01(\x0b\x32\r.pb.SchemaKey\"\x0c\n\nJoinStruct\"r\n\x07Session\x12\x0b\n\x03url\x18\x01 \x01(\t\x12\x11\n\tcontainer\x18\x02 \x01(\t\x12\x0c\n\x04path\x18\x03 \x01(\t\x12\x0c\n\x04user\x18\x04 \x01(\t\x12\x10\n\x08password\x18\x05 \x01(\t\x12\r\n\x05token\x18\x06 \x01(\t\x12\n\n\x02id\x18\x07 \x01(\t\"\xdd\x04\n\x0bReadRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\x1f\n\x06schema\x18\x03 \x01(\x0b\x32\x0f.pb.TableSchema\x12\x13\n\x0b\x64\x61ta_format\x18\x04 \x01(\t\x12\x12\n\nrow_layout\x18\x05 \x01(\x08\x12\x13\n\x0bmulti_index\x18\x06 \x01(\x08\x12\r\n\x05query\x18\x07 \x01(\t\x12\r\n\x05table\x18\x08 \x01(\t\x12\x0f\n\x07\x63olumns\x18\t \x03(\t\x12\x0e\n\x06\x66ilter\x18\n \x01(\t\x12\x10\n\x08group_by\x18\x0b \x01(\t\x12\x1c\n\x04join\x18\x0c \x03(\x0b\x32\x0e.pb.JoinStruct\x12\r\n\x05limit\x18\r \x01(\x03\x12\x15\n\rmessage_limit\x18\x0e \x01(\x03\x12\x0e\n\x06marker\x18\x0f \x01(\t\x12\x13\n\x0breset_index\x18\x1d \x01(\x08\x12\x10\n\x08segments\x18\x10 \x03(\x03\x12\x16\n\x0etotal_segments\x18\x11 \x01(\x03\x12\x15\n\rsharding_keys\x18\x12 \x03(\t\x12\x1c\n\x14sort_key_range_start\x18\x13 \x01(\t\x12\x1a\n\x12sort_key_range_end\x18\x14 \x01(\t\x12\r\n\x05start\x18\x15 \x01(\t\x12\x0b\n\x03\x65nd\x18\x16 \x01(\t\x12\x0c\n\x04step\x18\x17 \x01(\t\x12\x13\n\x0b\x61ggregators\x18\x18 \x01(\t\x12\x1a\n\x12\x61ggregation_window\x18\x1c \x01(\t\x12\x0c\n\x04seek\x18\x19 \x01(\t\x12\x10\n\x08shard_id\x18\x1a \x01(\t\x12\x10\n\x08sequence\x18\x1b \x01(\x03\"\xd4\x01\n\x13InitialWriteRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x1f\n\x0cinitial_data\x18\x04 \x01(\x0b\x32\t.pb.Frame\x12\x12\n\nexpression\x18\x05 \x01(\t\x12\x0c\n\x04more\x18\x06 \x01(\x08\x12\x16\n\x0epartition_keys\x18\x07 \x03(\t\x12\x11\n\tcondition\x18\x08 \x01(\t\x12\x11\n\tsave_mode\x18\t \x01(\t\"^\n\x0cWriteRequest\x12*\n\x07request\x18\x01 \x01(\x0b\x32\x17.pb.InitialWriteRequestH\x00\x12\x1a\n\x05\x66rame\x18\x02 \x01(\x0b\x32\t.pb.FrameH\x00\x42\x06\n\x04type\",\n\x0cWriteRespose\x12\x0e\n\x06\x66rames\x18\x01 \x01(\x03\x12\x0c\n\x04rows\x18\x02 \x01(\x03\"\xff\x01\n\rCreateRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x1f\n\x06schema\x18\x04 \x01(\x0b\x32\x0f.pb.TableSchema\x12#\n\tif_exists\x18\x05 \x01(\x0e\x32\x10.pb.ErrorOptions\x12\x0c\n\x04rate\x18\x06 \x01(\t\x12\x12\n\naggregates\x18\x07 \x01(\t\x12\x1f\n\x17\x61ggregation_granularity\x18\x08 \x01(\t\x12\x0e\n\x06shards\x18\t \x01(\x03\x12\x17\n\x0fretention_hours\x18\n \x01(\x03\"\x10\n\x0e\x43reateResponse\"\xb0\x01\n\rDeleteRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x0e\n\x06\x66ilter\x18\x04 \x01(\t\x12$\n\nif_missing\x18\x05 \x01(\x0e\x32\x10.pb.ErrorOptions\x12\r\n\x05start\x18\x06 \x01(\t\x12\x0b\n\x03\x65nd\x18\x07 \x01(\t\x12\x0f\n\x07metrics\x18\x08 \x03(\t\"\x10\n\x0e\x44\x65leteResponse\"\x10\n\x0eVersionRequest\"6\n\x0c\x45xecResponse\x12\x18\n\x05\x66rame\x18\x01 \x01(\x0b\x32\t.pb.Frame\x12\x0c\n\x04rows\x18\x02 \x01(\x03\"\xd1\x01\n\x0b\x45xecRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x0f\n\x07\x63ommand\x18\x04 \x01(\t\x12\'\n\x04\x61rgs\x18\x05 \x03(\x0b\x32\x19.pb.ExecRequest.ArgsEntry\x12\x12\n\nexpression\x18\x06 \x01(\t\x1a\x36\n\tArgsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x18\n\x05value\x18\x02 \x01(\x0b\x32\t.pb.Value:\x02\x38\x01\"\"\n\x0fVersionResponse\x12\x0f\n\x07version\x18\x01 \x01(\t\"\xdb\x01\n\x0eHistoryRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x0c\n\x04user\x18\x04 \x01(\t\x12\x0e\n\x06\x61\x63tion\x18\x05 \x01(\t\x12\x16\n\x0emin_start_time\x18\x06 \x01(\t\x12\x16\n\x0emax_start_time\x18\x07 \x01(\t\x12\x11\n\tcontainer\x18\x08 \x01(\t\x12\x14\n\x0cmin_duration\x18\t \x01(\x03\x12\x14\n\x0cmax_duration\x18\n \x01(\x03*V\n\x05\x44Type\x12\x08\n\x04NONE\x10\x00\x12\x0b\n\x07INTEGER\x10\x01\x12\t\n\x05\x46LOAT\x10\x02\x12\n\n\x06STRING\x10\x03\x12\x08\n\x04TIME\x10\x04\x12\x0b\n\x07\x42OOLEAN\x10\x05\x12\x08\n\x04NULL\x10\x06*$\n\x0c\x45rrorOptions\x12\x08\n\x04\x46\x41IL\x10\x00\x12\n\n\x06IGNORE\x10\x01\x32\xd8\x02\n\x06\x46rames\x12&\n\x04Read\x12\x0f.pb.ReadRequest\x1a\t.pb.Frame\"\x00\x30\x01\x12/\n\x05Write\x12\x10.pb.WriteRequest\x1a\x10.pb.WriteRespose\"\x00(\x01\x12\x31\n\x06\x43reate\x12\x11.pb.CreateRequest\x1a\x12.pb.CreateResponse\"\x00\x12\x31\n\x06\x44\x65lete\x12\x11.pb.DeleteRequest\x1a\x12.pb.DeleteResponse\"\x00\x12+\n\x04\x45xec\x12\x0f.pb.ExecRequest\x1a\x10.pb.ExecResponse\"\x00\x12,\n\x07History\x12\x12.pb.HistoryRequest\x1a\t.pb.Frame\"\x00\x30\x01\x12\x34\n\x07Version\x12\x12.pb.VersionRequest\x1a\x13.pb.VersionResponse\"\x00\x62\x06proto3')
+  serialized_pb=_b('\n\x0c\x66rames.proto\x12\x02pb\"\xdb\x01\n\x06\x43olumn\x12\x1d\n\x04kind\x18\x01 \x01(\x0e\x32\x0f.pb.Column.Kind\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x18\n\x05\x64type\x18\x03 \x01(\x0e\x32\t.pb.DType\x12\x0c\n\x04size\x18\x04 \x01(\x03\x12\x0c\n\x04ints\x18\x05 \x03(\x03\x12\x0e\n\x06\x66loats\x18\x06 \x03(\x01\x12\x0f\n\x07strings\x18\x07 \x03(\t\x12\r\n\x05times\x18\x08 \x03(\x03\x12\r\n\x05\x62ools\x18\t \x03(\x08\x12\x11\n\ttime_zone\x18\n \x01(\t\"\x1c\n\x04Kind\x12\t\n\x05SLICE\x10\x00\x12\t\n\x05LABEL\x10\x01\"`\n\x05Value\x12\x0e\n\x04ival\x18\x01 \x01(\x03H\x00\x12\x0e\n\x04\x66val\x18\x02 \x01(\x01H\x00\x12\x0e\n\x04sval\x18\x03 \x01(\tH\x00\x12\x0e\n\x04tval\x18\x04 \x01(\x03H\x00\x12\x0e\n\x04\x62val\x18\x05 \x01(\x08H\x00\x42\x07\n\x05value\"|\n\rNullValuesMap\x12\x37\n\x0bnullColumns\x18\x01 \x03(\x0b\x32\".pb.NullValuesMap.NullColumnsEntry\x1a\x32\n\x10NullColumnsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x08:\x02\x38\x01\"\xd9\x01\n\x05\x46rame\x12\x1b\n\x07\x63olumns\x18\x01 \x03(\x0b\x32\n.pb.Column\x12\x1b\n\x07indices\x18\x02 \x03(\x0b\x32\n.pb.Column\x12%\n\x06labels\x18\x03 \x03(\x0b\x32\x15.pb.Frame.LabelsEntry\x12\r\n\x05\x65rror\x18\x04 \x01(\t\x12&\n\x0bnull_values\x18\x05 \x03(\x0b\x32\x11.pb.NullValuesMap\x1a\x38\n\x0bLabelsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x18\n\x05value\x18\x02 \x01(\x0b\x32\t.pb.Value:\x02\x38\x01\"\xc5\x01\n\x0bSchemaField\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0b\n\x03\x64oc\x18\x02 \x01(\t\x12\x1a\n\x07\x64\x65\x66\x61ult\x18\x03 \x01(\x0b\x32\t.pb.Value\x12\x0c\n\x04type\x18\x04 \x01(\t\x12\x33\n\nproperties\x18\x05 \x03(\x0b\x32\x1f.pb.SchemaField.PropertiesEntry\x1a<\n\x0fPropertiesEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x18\n\x05value\x18\x02 \x01(\x0b\x32\t.pb.Value:\x02\x38\x01\"6\n\tSchemaKey\x12\x14\n\x0csharding_key\x18\x01 \x03(\t\x12\x13\n\x0bsorting_key\x18\x02 \x03(\t\"\x97\x01\n\x0bTableSchema\x12\x0c\n\x04type\x18\x01 \x01(\t\x12\x11\n\tnamespace\x18\x02 \x01(\t\x12\x0c\n\x04name\x18\x03 \x01(\t\x12\x0b\n\x03\x64oc\x18\x04 \x01(\t\x12\x0f\n\x07\x61liases\x18\x05 \x03(\t\x12\x1f\n\x06\x66ields\x18\x06 \x03(\x0b\x32\x0f.pb.SchemaField\x12\x1a\n\x03key\x18\x07 \x01(\x0b\x32\r.pb.SchemaKey\"\x0c\n\nJoinStruct\"r\n\x07Session\x12\x0b\n\x03url\x18\x01 \x01(\t\x12\x11\n\tcontainer\x18\x02 \x01(\t\x12\x0c\n\x04path\x18\x03 \x01(\t\x12\x0c\n\x04user\x18\x04 \x01(\t\x12\x10\n\x08password\x18\x05 \x01(\t\x12\r\n\x05token\x18\x06 \x01(\t\x12\n\n\x02id\x18\x07 \x01(\t\"\x89\x05\n\x0bReadRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\x1f\n\x06schema\x18\x03 \x01(\x0b\x32\x0f.pb.TableSchema\x12\x13\n\x0b\x64\x61ta_format\x18\x04 \x01(\t\x12\x12\n\nrow_layout\x18\x05 \x01(\x08\x12\x13\n\x0bmulti_index\x18\x06 \x01(\x08\x12\r\n\x05query\x18\x07 \x01(\t\x12\r\n\x05table\x18\x08 \x01(\t\x12\x0f\n\x07\x63olumns\x18\t \x03(\t\x12\x0e\n\x06\x66ilter\x18\n \x01(\t\x12\x10\n\x08group_by\x18\x0b \x01(\t\x12\x1c\n\x04join\x18\x0c \x03(\x0b\x32\x0e.pb.JoinStruct\x12\r\n\x05limit\x18\r \x01(\x03\x12\x15\n\rmessage_limit\x18\x0e \x01(\x03\x12\x0e\n\x06marker\x18\x0f \x01(\t\x12\x13\n\x0breset_index\x18\x1d \x01(\x08\x12\x10\n\x08segments\x18\x10 \x03(\x03\x12\x16\n\x0etotal_segments\x18\x11 \x01(\x03\x12\x15\n\rsharding_keys\x18\x12 \x03(\t\x12\x1c\n\x14sort_key_range_start\x18\x13 \x01(\t\x12\x1a\n\x12sort_key_range_end\x18\x14 \x01(\t\x12\r\n\x05start\x18\x15 \x01(\t\x12\x0b\n\x03\x65nd\x18\x16 \x01(\t\x12\x0c\n\x04step\x18\x17 \x01(\t\x12\x13\n\x0b\x61ggregators\x18\x18 \x01(\t\x12\x1a\n\x12\x61ggregation_window\x18\x1c \x01(\t\x12\x0c\n\x04seek\x18\x19 \x01(\t\x12\x10\n\x08shard_id\x18\x1a \x01(\t\x12\x10\n\x08sequence\x18\x1b \x01(\x03\x12\x11\n\ttime_zone\x18\x1e \x01(\t\x12\x17\n\x0finclude_expired\x18\x1f \x01(\x08\"\xbc\x02\n\x13InitialWriteRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x1f\n\x0cinitial_data\x18\x04 \x01(\x0b\x32\t.pb.Frame\x12\x12\n\nexpression\x18\x05 \x01(\t\x12\x0c\n\x04more\x18\x06 \x01(\x08\x12\x16\n\x0epartition_keys\x18\x07 \x03(\t\x12\x11\n\tcondition\x18\x08 \x01(\t\x12\x11\n\tsave_mode\x18\t \x01(\t\x12\x19\n\x11\x63ontinue_on_error\x18\n \x01(\x08\x12\x11\n\ttime_zone\x18\x0b \x01(\t\x12\x16\n\x0etime_precision\x18\x0c \x01(\t\x12\x0b\n\x03ttl\x18\r \x01(\t\x12\x13\n\x0bschema_mode\x18\x0e \x01(\t\"^\n\x0cWriteRequest\x12*\n\x07request\x18\x01 \x01(\x0b\x32\x17.pb.InitialWriteRequestH\x00\x12\x1a\n\x05\x66rame\x18\x02 \x01(\x0b\x32\t.pb.FrameH\x00\x42\x06\n\x04type\"L\n\x0cWriteRespose\x12\x0e\n\x06\x66rames\x18\x01 \x01(\x03\x12\x0c\n\x04rows\x18\x02 \x01(\x03\x12\x1e\n\x0b\x66\x61iled_rows\x18\x03 \x01(\x0b\x32\t.pb.Frame\"\xff\x01\n\rCreateRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x1f\n\x06schema\x18\x04 \x01(\x0b\x32\x0f.pb.TableSchema\x12#\n\tif_exists\x18\x05 \x01(\x0e\x32\x10.pb.ErrorOptions\x12\x0c\n\x04rate\x18\x06 \x01(\t\x12\x12\n\naggregates\x18\x07 \x01(\t\x12\x1f\n\x17\x61ggregation_granularity\x18\x08 \x01(\t\x12\x0e\n\x06shards\x18\t \x01(\x03\x12\x17\n\x0fretention_hours\x18\n \x01(\x03\"\x10\n\x0e\x43reateResponse\"\xb0\x01\n\rDeleteRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x0e\n\x06\x66ilter\x18\x04 \x01(\t\x12$\n\nif_missing\x18\x05 \x01(\x0e\x32\x10.pb.ErrorOptions\x12\r\n\x05start\x18\x06 \x01(\t\x12\x0b\n\x03\x65nd\x18\x07 \x01(\t\x12\x0f\n\x07metrics\x18\x08 \x03(\t\"\x10\n\x0e\x44\x65leteResponse\"\x10\n\x0eVersionRequest\"6\n\x0c\x45xecResponse\x12\x18\n\x05\x66rame\x18\x01 \x01(\x0b\x32\t.pb.Frame\x12\x0c\n\x04rows\x18\x02 \x01(\x03\"\xeb\x01\n\x0b\x45xecRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x0f\n\x07\x63ommand\x18\x04 \x01(\t\x12\'\n\x04\x61rgs\x18\x05 \x03(\x0b\x32\x19.pb.ExecRequest.ArgsEntry\x12\x12\n\nexpression\x18\x06 \x01(\t\x12\x18\n\x05\x66rame\x18\x07 \x01(\x0b\x32\t.pb.Frame\x1a\x36\n\tArgsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\x18\n\x05value\x18\x02 \x01(\x0b\x32\t.pb.Value:\x02\x38\x01\"\"\n\x0fVersionResponse\x12\x0f\n\x07version\x18\x01 \x01(\t\"\xdb\x01\n\x0eHistoryRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12\r\n\x05table\x18\x03 \x01(\t\x12\x0c\n\x04user\x18\x04 \x01(\t\x12\x0e\n\x06\x61\x63tion\x18\x05 \x01(\t\x12\x16\n\x0emin_start_time\x18\x06 \x01(\t\x12\x16\n\x0emax_start_time\x18\x07 \x01(\t\x12\x11\n\tcontainer\x18\x08 \x01(\t\x12\x14\n\x0cmin_duration\x18\t \x01(\x03\x12\x14\n\x0cmax_duration\x18\n \x01(\x03\"G\n\x08WriteAck\x12\r\n\x05\x66rame\x18\x01 \x01(\x03\x12\x0c\n\x04rows\x18\x02 \x01(\x03\x12\x1e\n\x0b\x66\x61iled_rows\x18\x03 \x01(\x0b\x32\t.pb.Frame\"R\n\x0f\x42\x61tchWriteTable\x12\r\n\x05table\x18\x01 \x01(\t\x12\x18\n\x05\x66rame\x18\x02 \x01(\x0b\x32\t.pb.Frame\x12\x16\n\x0epartition_keys\x18\x03 \x03(\t\"\x82\x01\n\x11\x42\x61tchWriteRequest\x12\x1c\n\x07session\x18\x01 \x01(\x0b\x32\x0b.pb.Session\x12\x0f\n\x07\x62\x61\x63kend\x18\x02 \x01(\t\x12#\n\x06tables\x18\x03 \x03(\x0b\x32\x13.pb.BatchWriteTable\x12\x19\n\x11version_attribute\x18\x04 \x01(\t\"O\n\x10\x42\x61tchTableResult\x12\r\n\x05table\x18\x01 \x01(\t\x12\x0e\n\x06status\x18\x02 \x01(\t\x12\r\n\x05items\x18\x03 \x01(\x03\x12\r\n\x05\x65rror\x18\x04 \x01(\t\"\\\n\x12\x42\x61tchWriteResponse\x12\x11\n\tcommitted\x18\x01 \x01(\x08\x12$\n\x06tables\x18\x02 \x03(\x0b\x32\x14.pb.BatchTableResult\x12\r\n\x05\x65rror\x18\x03 \x01(\t*V\n\x05\x44Type\x12\x08\n\x04NONE\x10\x00\x12\x0b\n\x07INTEGER\x10\x01\x12\t\n\x05\x46LOAT\x10\x02\x12\n\n\x06STRING\x10\x03\x12\x08\n\x04TIME\x10\x04\x12\x0b\n\x07\x42OOLEAN\x10\x05\x12\x08\n\x04NULL\x10\x06*$\n\x0c\x45rrorOptions\x12\x08\n\x04\x46\x41IL\x10\x00\x12\n\n\x06IGNORE\x10\x01\x32\xcc\x03\n\x06\x46rames\x12&\n\x04Read\x12\x0f.pb.ReadRequest\x1a\t.pb.Frame\"\x00\x30\x01\x12/\n\x05Write\x12\x10.pb.WriteRequest\x1a\x10.pb.WriteRespose\"\x00(\x01\x12\x31\n\x06\x43reate\x12\x11.pb.CreateRequest\x1a\x12.pb.CreateResponse\"\x00\x12\x31\n\x06\x44\x65lete\x12\x11.pb.DeleteRequest\x1a\x12.pb.DeleteResponse\"\x00\x12+\n\x04\x45xec\x12\x0f.pb.ExecRequest\x1a\x10.pb.ExecResponse\"\x00\x12,\n\x07History\x12\x12.pb.HistoryRequest\x1a\t.pb.Frame\"\x00\x30\x01\x12\x34\n\x07Version\x12\x12.pb.VersionRequest\x1a\x13.pb.VersionResponse\"\x00\x12\x33\n\x0bWriteStream\x12\x10.pb.WriteRequest\x1a\x0c.pb.WriteAck\"\x00(\x01\x30\x01\x12=\n\nBatchWrite\x12\x15.pb.BatchWriteRequest\x1a\x16.pb.BatchWriteResponse\"\x00\x62\x06proto3')
 )
 
 _DTYPE = _descriptor.EnumDescriptor(
@@ -61,8 +61,8 @@ _DTYPE = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=3189,
-  serialized_end=3275,
+  serialized_start=3879,
+  serialized_end=3965,
 )
 _sym_db.RegisterEnumDescriptor(_DTYPE)
 
@@ -84,8 +84,8 @@ _ERROROPTIONS = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=3277,
-  serialized_end=3313,
+  serialized_start=3967,
+  serialized_end=4003,
 )
 _sym_db.RegisterEnumDescriptor(_ERROROPTIONS)
 
@@ -118,8 +118,8 @@ _COLUMN_KIND = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=193,
-  serialized_end=221,
+  serialized_start=212,
+  serialized_end=240,
 )
 _sym_db.RegisterEnumDescriptor(_COLUMN_KIND)
 
@@ -194,6 +194,13 @@ _COLUMN = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='time_zone', full_name='pb.Column.time_zone', index=9,
+      number=10, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
   ],
   extensions=[
   ],
@@ -208,7 +215,7 @@ _COLUMN = _descriptor.Descriptor(
   oneofs=[
   ],
   serialized_start=21,
-  serialized_end=221,
+  serialized_end=240,
 )
 
 
@@ -269,8 +276,8 @@ _VALUE = _descriptor.Descriptor(
       name='value', full_name='pb.Value.value',
       index=0, containing_type=None, fields=[]),
   ],
-  serialized_start=223,
-  serialized_end=319,
+  serialized_start=242,
+  serialized_end=338,
 )
 
 
@@ -307,8 +314,8 @@ _NULLVALUESMAP_NULLCOLUMNSENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=395,
-  serialized_end=445,
+  serialized_start=414,
+  serialized_end=464,
 )
 
 _NULLVALUESMAP = _descriptor.Descriptor(
@@ -337,8 +344,8 @@ _NULLVALUESMAP = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=321,
-  serialized_end=445,
+  serialized_start=340,
+  serialized_end=464,
 )
 
 
@@ -375,8 +382,8 @@ _FRAME_LABELSENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=609,
-  serialized_end=665,
+  serialized_start=628,
+  serialized_end=684,
 )
 
 _FRAME = _descriptor.Descriptor(
@@ -433,8 +440,8 @@ _FRAME = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=448,
-  serialized_end=665,
+  serialized_start=467,
+  serialized_end=684,
 )
 
 
@@ -471,8 +478,8 @@ _SCHEMAFIELD_PROPERTIESENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=805,
-  serialized_end=865,
+  serialized_start=824,
+  serialized_end=884,
 )
 
 _SCHEMAFIELD = _descriptor.Descriptor(
@@ -529,8 +536,8 @@ _SCHEMAFIELD = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=668,
-  serialized_end=865,
+  serialized_start=687,
+  serialized_end=884,
 )
 
 
@@ -567,8 +574,8 @@ _SCHEMAKEY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=867,
-  serialized_end=921,
+  serialized_start=886,
+  serialized_end=940,
 )
 
 
@@ -640,8 +647,8 @@ _TABLESCHEMA = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=924,
-  serialized_end=1075,
+  serialized_start=943,
+  serialized_end=1094,
 )
 
 
@@ -664,8 +671,8 @@ _JOINSTRUCT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1077,
-  serialized_end=1089,
+  serialized_start=1096,
+  serialized_end=1108,
 )
 
 
@@ -737,8 +744,8 @@ _SESSION = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1091,
-  serialized_end=1205,
+  serialized_start=1110,
+  serialized_end=1224,
 )
 
 
@@ -952,6 +959,20 @@ _READREQUEST = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='time_zone', full_name='pb.ReadRequest.time_zone', index=29,
+      number=30, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='include_expired', full_name='pb.ReadRequest.include_expired', index=30,
+      number=31, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
   ],
   extensions=[
   ],
@@ -964,8 +985,8 @@ _READREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1208,
-  serialized_end=1813,
+  serialized_start=1227,
+  serialized_end=1876,
 )
 
 
@@ -1039,6 +1060,41 @@ _INITIALWRITEREQUEST = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='continue_on_error', full_name='pb.InitialWriteRequest.continue_on_error', index=9,
+      number=10, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='time_zone', full_name='pb.InitialWriteRequest.time_zone', index=10,
+      number=11, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='time_precision', full_name='pb.InitialWriteRequest.time_precision', index=11,
+      number=12, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='ttl', full_name='pb.InitialWriteRequest.ttl', index=12,
+      number=13, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='schema_mode', full_name='pb.InitialWriteRequest.schema_mode', index=13,
+      number=14, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
   ],
   extensions=[
   ],
@@ -1051,8 +1107,8 @@ _INITIALWRITEREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1816,
-  serialized_end=2028,
+  serialized_start=1879,
+  serialized_end=2195,
 )
 
 
@@ -1092,8 +1148,8 @@ _WRITEREQUEST = _descriptor.Descriptor(
       name='type', full_name='pb.WriteRequest.type',
       index=0, containing_type=None, fields=[]),
   ],
-  serialized_start=2030,
-  serialized_end=2124,
+  serialized_start=2197,
+  serialized_end=2291,
 )
 
 
@@ -1118,6 +1174,13 @@ _WRITERESPOSE = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='failed_rows', full_name='pb.WriteRespose.failed_rows', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
   ],
   extensions=[
   ],
@@ -1130,8 +1193,8 @@ _WRITERESPOSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2126,
-  serialized_end=2170,
+  serialized_start=2293,
+  serialized_end=2369,
 )
 
 
@@ -1224,8 +1287,8 @@ _CREATEREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2173,
-  serialized_end=2428,
+  serialized_start=2372,
+  serialized_end=2627,
 )
 
 
@@ -1248,8 +1311,8 @@ _CREATERESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2430,
-  serialized_end=2446,
+  serialized_start=2629,
+  serialized_end=2645,
 )
 
 
@@ -1328,8 +1391,8 @@ _DELETEREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2449,
-  serialized_end=2625,
+  serialized_start=2648,
+  serialized_end=2824,
 )
 
 
@@ -1352,8 +1415,8 @@ _DELETERESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2627,
-  serialized_end=2643,
+  serialized_start=2826,
+  serialized_end=2842,
 )
 
 
@@ -1376,8 +1439,8 @@ _VERSIONREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2645,
-  serialized_end=2661,
+  serialized_start=2844,
+  serialized_end=2860,
 )
 
 
@@ -1414,8 +1477,8 @@ _EXECRESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2663,
-  serialized_end=2717,
+  serialized_start=2862,
+  serialized_end=2916,
 )
 
 
@@ -1452,8 +1515,8 @@ _EXECREQUEST_ARGSENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2875,
-  serialized_end=2929,
+  serialized_start=3100,
+  serialized_end=3154,
 )
 
 _EXECREQUEST = _descriptor.Descriptor(
@@ -1505,6 +1568,13 @@ _EXECREQUEST = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='frame', full_name='pb.ExecRequest.frame', index=6,
+      number=7, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
   ],
   extensions=[
   ],
@@ -1517,8 +1587,8 @@ _EXECREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2720,
-  serialized_end=2929,
+  serialized_start=2919,
+  serialized_end=3154,
 )
 
 
@@ -1548,8 +1618,8 @@ _VERSIONRESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2931,
-  serialized_end=2965,
+  serialized_start=3156,
+  serialized_end=3190,
 )
 
 
@@ -1642,8 +1712,247 @@ _HISTORYREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2968,
-  serialized_end=3187,
+  serialized_start=3193,
+  serialized_end=3412,
+)
+
+
+_WRITEACK = _descriptor.Descriptor(
+  name='WriteAck',
+  full_name='pb.WriteAck',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='frame', full_name='pb.WriteAck.frame', index=0,
+      number=1, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='rows', full_name='pb.WriteAck.rows', index=1,
+      number=2, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='failed_rows', full_name='pb.WriteAck.failed_rows', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3414,
+  serialized_end=3485,
+)
+
+
+_BATCHWRITETABLE = _descriptor.Descriptor(
+  name='BatchWriteTable',
+  full_name='pb.BatchWriteTable',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='table', full_name='pb.BatchWriteTable.table', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='frame', full_name='pb.BatchWriteTable.frame', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='partition_keys', full_name='pb.BatchWriteTable.partition_keys', index=2,
+      number=3, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3487,
+  serialized_end=3569,
+)
+
+
+_BATCHWRITEREQUEST = _descriptor.Descriptor(
+  name='BatchWriteRequest',
+  full_name='pb.BatchWriteRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='session', full_name='pb.BatchWriteRequest.session', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='backend', full_name='pb.BatchWriteRequest.backend', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='tables', full_name='pb.BatchWriteRequest.tables', index=2,
+      number=3, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='version_attribute', full_name='pb.BatchWriteRequest.version_attribute', index=3,
+      number=4, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3572,
+  serialized_end=3702,
+)
+
+
+_BATCHTABLERESULT = _descriptor.Descriptor(
+  name='BatchTableResult',
+  full_name='pb.BatchTableResult',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='table', full_name='pb.BatchTableResult.table', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='status', full_name='pb.BatchTableResult.status', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='items', full_name='pb.BatchTableResult.items', index=2,
+      number=3, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='error', full_name='pb.BatchTableResult.error', index=3,
+      number=4, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3704,
+  serialized_end=3783,
+)
+
+
+_BATCHWRITERESPONSE = _descriptor.Descriptor(
+  name='BatchWriteResponse',
+  full_name='pb.BatchWriteResponse',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='committed', full_name='pb.BatchWriteResponse.committed', index=0,
+      number=1, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='tables', full_name='pb.BatchWriteResponse.tables', index=1,
+      number=2, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='error', full_name='pb.BatchWriteResponse.error', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3785,
+  serialized_end=3877,
 )
 
 _COLUMN.fields_by_name['kind'].enum_type = _COLUMN_KIND
@@ -1691,6 +2000,7 @@ _WRITEREQUEST.fields_by_name['request'].containing_oneof = _WRITEREQUEST.oneofs_
 _WRITEREQUEST.oneofs_by_name['type'].fields.append(
   _WRITEREQUEST.fields_by_name['frame'])
 _WRITEREQUEST.fields_by_name['frame'].containing_oneof = _WRITEREQUEST.oneofs_by_name['type']
+_WRITERESPOSE.fields_by_name['failed_rows'].message_type = _FRAME
 _CREATEREQUEST.fields_by_name['session'].message_type = _SESSION
 _CREATEREQUEST.fields_by_name['schema'].message_type = _TABLESCHEMA
 _CREATEREQUEST.fields_by_name['if_exists'].enum_type = _ERROROPTIONS
@@ -1701,7 +2011,13 @@ _EXECREQUEST_ARGSENTRY.fields_by_name['value'].message_type = _VALUE
 _EXECREQUEST_ARGSENTRY.containing_type = _EXECREQUEST
 _EXECREQUEST.fields_by_name['session'].message_type = _SESSION
 _EXECREQUEST.fields_by_name['args'].message_type = _EXECREQUEST_ARGSENTRY
+_EXECREQUEST.fields_by_name['frame'].message_type = _FRAME
 _HISTORYREQUEST.fields_by_name['session'].message_type = _SESSION
+_WRITEACK.fields_by_name['failed_rows'].message_type = _FRAME
+_BATCHWRITETABLE.fields_by_name['frame'].message_type = _FRAME
+_BATCHWRITEREQUEST.fields_by_name['session'].message_type = _SESSION
+_BATCHWRITEREQUEST.fields_by_name['tables'].message_type = _BATCHWRITETABLE
+_BATCHWRITERESPONSE.fields_by_name['tables'].message_type = _BATCHTABLERESULT
 DESCRIPTOR.message_types_by_name['Column'] = _COLUMN
 DESCRIPTOR.message_types_by_name['Value'] = _VALUE
 DESCRIPTOR.message_types_by_name['NullValuesMap'] = _NULLVALUESMAP
@@ -1724,6 +2040,11 @@ DESCRIPTOR.message_types_by_name['ExecResponse'] = _EXECRESPONSE
 DESCRIPTOR.message_types_by_name['ExecRequest'] = _EXECREQUEST
 DESCRIPTOR.message_types_by_name['VersionResponse'] = _VERSIONRESPONSE
 DESCRIPTOR.message_types_by_name['HistoryRequest'] = _HISTORYREQUEST
+DESCRIPTOR.message_types_by_name['WriteAck'] = _WRITEACK
+DESCRIPTOR.message_types_by_name['BatchWriteTable'] = _BATCHWRITETABLE
+DESCRIPTOR.message_types_by_name['BatchWriteRequest'] = _BATCHWRITEREQUEST
+DESCRIPTOR.message_types_by_name['BatchTableResult'] = _BATCHTABLERESULT
+DESCRIPTOR.message_types_by_name['BatchWriteResponse'] = _BATCHWRITERESPONSE
 DESCRIPTOR.enum_types_by_name['DType'] = _DTYPE
 DESCRIPTOR.enum_types_by_name['ErrorOptions'] = _ERROROPTIONS
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
@@ -1914,6 +2235,41 @@ HistoryRequest = _reflection.GeneratedProtocolMessageType('HistoryRequest', (_me
   })
 _sym_db.RegisterMessage(HistoryRequest)
 
+WriteAck = _reflection.GeneratedProtocolMessageType('WriteAck', (_message.Message,), {
+  'DESCRIPTOR' : _WRITEACK,
+  '__module__' : 'frames_pb2'
+  # @@protoc_insertion_point(class_scope:pb.WriteAck)
+  })
+_sym_db.RegisterMessage(WriteAck)
+
+BatchWriteTable = _reflection.GeneratedProtocolMessageType('BatchWriteTable', (_message.Message,), {
+  'DESCRIPTOR' : _BATCHWRITETABLE,
+  '__module__' : 'frames_pb2'
+  # @@protoc_insertion_point(class_scope:pb.BatchWriteTable)
+  })
+_sym_db.RegisterMessage(BatchWriteTable)
+
+BatchWriteRequest = _reflection.GeneratedProtocolMessageType('BatchWriteRequest', (_message.Message,), {
+  'DESCRIPTOR' : _BATCHWRITEREQUEST,
+  '__module__' : 'frames_pb2'
+  # @@protoc_insertion_point(class_scope:pb.BatchWriteRequest)
+  })
+_sym_db.RegisterMessage(BatchWriteRequest)
+
+BatchTableResult = _reflection.GeneratedProtocolMessageType('BatchTableResult', (_message.Message,), {
+  'DESCRIPTOR' : _BATCHTABLERESULT,
+  '__module__' : 'frames_pb2'
+  # @@protoc_insertion_point(class_scope:pb.BatchTableResult)
+  })
+_sym_db.RegisterMessage(BatchTableResult)
+
+BatchWriteResponse = _reflection.GeneratedProtocolMessageType('BatchWriteResponse', (_message.Message,), {
+  'DESCRIPTOR' : _BATCHWRITERESPONSE,
+  '__module__' : 'frames_pb2'
+  # @@protoc_insertion_point(class_scope:pb.BatchWriteResponse)
+  })
+_sym_db.RegisterMessage(BatchWriteResponse)
+
 
 _NULLVALUESMAP_NULLCOLUMNSENTRY._options = None
 _FRAME_LABELSENTRY._options = None
@@ -1926,8 +2282,8 @@ _FRAMES = _descriptor.ServiceDescriptor(
   file=DESCRIPTOR,
   index=0,
   serialized_options=None,
-  serialized_start=3316,
-  serialized_end=3660,
+  serialized_start=4006,
+  serialized_end=4466,
   methods=[
   _descriptor.MethodDescriptor(
     name='Read',
@@ -1992,6 +2348,24 @@ _FRAMES = _descriptor.ServiceDescriptor(
     output_type=_VERSIONRESPONSE,
     serialized_options=None,
   ),
+  _descriptor.MethodDescriptor(
+    name='WriteStream',
+    full_name='pb.Frames.WriteStream',
+    index=7,
+    containing_service=None,
+    input_type=_WRITEREQUEST,
+    output_type=_WRITEACK,
+    serialized_options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='BatchWrite',
+    full_name='pb.Frames.BatchWrite',
+    index=8,
+    containing_service=None,
+    input_type=_BATCHWRITEREQUEST,
+    output_type=_BATCHWRITERESPONSE,
+    serialized_options=None,
+  ),
 ])
 _sym_db.RegisterServiceDescriptor(_FRAMES)
 
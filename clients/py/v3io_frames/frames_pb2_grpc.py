@@ -49,6 +49,16 @@ class FramesStub(object):
         request_serializer=frames__pb2.VersionRequest.SerializeToString,
         response_deserializer=frames__pb2.VersionResponse.FromString,
         )
+    self.WriteStream = channel.stream_stream(
+        '/pb.Frames/WriteStream',
+        request_serializer=frames__pb2.WriteRequest.SerializeToString,
+        response_deserializer=frames__pb2.WriteAck.FromString,
+        )
+    self.BatchWrite = channel.unary_unary(
+        '/pb.Frames/BatchWrite',
+        request_serializer=frames__pb2.BatchWriteRequest.SerializeToString,
+        response_deserializer=frames__pb2.BatchWriteResponse.FromString,
+        )
 
 
 class FramesServicer(object):
@@ -104,6 +114,20 @@ class FramesServicer(object):
     context.set_details('Method not implemented!')
     raise NotImplementedError('Method not implemented!')
 
+  def WriteStream(self, request_iterator, context):
+    # missing associated documentation comment in .proto file
+    pass
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def BatchWrite(self, request, context):
+    # missing associated documentation comment in .proto file
+    pass
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
 
 def add_FramesServicer_to_server(servicer, server):
   rpc_method_handlers = {
@@ -142,6 +166,16 @@ def add_FramesServicer_to_server(servicer, server):
           request_deserializer=frames__pb2.VersionRequest.FromString,
           response_serializer=frames__pb2.VersionResponse.SerializeToString,
       ),
+      'WriteStream': grpc.stream_stream_rpc_method_handler(
+          servicer.WriteStream,
+          request_deserializer=frames__pb2.WriteRequest.FromString,
+          response_serializer=frames__pb2.WriteAck.SerializeToString,
+      ),
+      'BatchWrite': grpc.unary_unary_rpc_method_handler(
+          servicer.BatchWrite,
+          request_deserializer=frames__pb2.BatchWriteRequest.FromString,
+          response_serializer=frames__pb2.BatchWriteResponse.SerializeToString,
+      ),
   }
   generic_handler = grpc.method_handlers_generic_handler(
       'pb.Frames', rpc_method_handlers)
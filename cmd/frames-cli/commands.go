@@ -0,0 +1,594 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	framesHttp "github.com/v3io/frames/http"
+	"github.com/v3io/frames/pb"
+)
+
+func runRead(ctx context.Context, client frames.ClientV2, args []string) error {
+	request := &pb.ReadRequest{}
+	var (
+		columns     listFlag
+		query       string
+		format      string
+		interactive bool
+	)
+
+	fs := newFlagSet("read", &request.Backend, &request.Table)
+	fs.Var(&columns, "columns", "comma separated columns to read")
+	fs.StringVar(&request.Filter, "filter", "", "filter expression")
+	fs.StringVar(&request.GroupBy, "group-by", "", "group by expression")
+	fs.StringVar(&query, "query", "", "SQL query (sets table, columns and filter)")
+	fs.Int64Var(&request.Limit, "limit", 0, "maximal number of rows (0 for all)")
+	fs.Int64Var(&request.MessageLimit, "message-limit", 0, "maximal number of rows per frame")
+	fs.StringVar(&request.Marker, "marker", "", "marker of a previous read to continue (kv)")
+	fs.StringVar(&request.Start, "start", "", "start time (tsdb)")
+	fs.StringVar(&request.End, "end", "", "end time (tsdb)")
+	fs.StringVar(&request.Step, "step", "", "aggregation step (tsdb)")
+	fs.StringVar(&request.Aggregators, "aggregators", "", "comma separated aggregators (tsdb)")
+	fs.StringVar(&request.Seek, "seek", "", "seek type (stream)")
+	fs.StringVar(&request.ShardId, "shard", "", "shard ID (stream)")
+	fs.StringVar(&format, "format", tableFormat, "output format (table, csv, json or ndjson)")
+	fs.BoolVar(&interactive, "interactive", false, "read SQL queries from standard input")
+	_ = fs.Parse(args)
+
+	if err := checkRequired(fs, "backend"); err != nil {
+		return err
+	}
+	request.Columns = columns
+
+	if interactive {
+		return readInteractive(ctx, client, request, format, os.Stdin)
+	}
+
+	if query != "" {
+		if err := applySQL(request, query); err != nil {
+			return err
+		}
+	}
+
+	if request.Table == "" {
+		return fmt.Errorf("read: missing -table or -query")
+	}
+
+	return readFrames(ctx, client, request, format)
+}
+
+// applySQL sets the table, columns, filter and group by of request from a
+// SQL query
+func applySQL(request *pb.ReadRequest, sql string) error {
+	query, err := frames.ParseSQL(sql)
+	if err != nil {
+		return errors.Wrap(err, "bad SQL query")
+	}
+
+	request.Table = query.Table
+	request.Columns = query.Columns
+	// ParseSQL keeps the WHERE and GROUP BY keywords
+	request.Filter = trimKeyword(query.Filter, "where")
+	request.GroupBy = trimKeyword(query.GroupBy, "group by")
+	return nil
+}
+
+func trimKeyword(clause string, keyword string) string {
+	if len(clause) >= len(keyword) && strings.EqualFold(clause[:len(keyword)], keyword) {
+		clause = clause[len(keyword):]
+	}
+
+	return strings.TrimSpace(clause)
+}
+
+// readInteractive reads SQL queries, one per line, from in and prints their
+// results
+func readInteractive(ctx context.Context, client frames.ClientV2, base *pb.ReadRequest, format string, in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(os.Stderr, "frames> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(os.Stderr)
+			return scanner.Err()
+		}
+
+		sql := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), ";")
+		switch strings.ToLower(sql) {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		}
+
+		request := *base
+		if err := applySQL(&request, sql); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+			continue
+		}
+
+		if err := readFrames(ctx, client, &request, format); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+func readFrames(ctx context.Context, client frames.ClientV2, request *pb.ReadRequest, format string) error {
+	out, err := newFrameWriter(format, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	it, err := client.Read(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	marker := ""
+	for it.Next() {
+		frame := it.At()
+		marker, _ = frame.Labels()[frames.MarkerLabel].(string)
+		if err := out.Write(frame); err != nil {
+			return err
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	if marker != "" {
+		// Not on stdout so it won't mix with the data
+		fmt.Fprintf(os.Stderr, "marker: %s\n", marker)
+	}
+
+	return nil
+}
+
+// writeFrames writes the frames of it to out and closes out
+func writeFrames(it frames.FrameIterator, out frameWriter) error {
+	for it.Next() {
+		if err := out.Write(it.At()); err != nil {
+			return err
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return it.Err()
+}
+
+func runWrite(ctx context.Context, client frames.ClientV2, args []string) error {
+	request := &frames.WriteRequest{}
+	var (
+		input         string
+		format        string
+		indices       listFlag
+		timeColumns   listFlag
+		partitionKeys listFlag
+		saveMode      string
+	)
+
+	fs := newFlagSet("write", &request.Backend, &request.Table)
+	fs.StringVar(&input, "input", "-", "input file (- for standard input)")
+	fs.StringVar(&format, "format", "", "input format (csv, json, ndjson or columns), default from input extension or csv")
+	fs.Var(&indices, "index", "comma separated index columns")
+	fs.Var(&timeColumns, "time-columns", "comma separated columns to parse as time")
+	fs.StringVar(&request.Expression, "expression", "", "update expression (kv)")
+	fs.StringVar(&request.Condition, "condition", "", "update condition (kv)")
+	fs.Var(&partitionKeys, "partition-keys", "comma separated partition columns (kv)")
+	fs.StringVar(&saveMode, "save-mode", "", "save mode (kv, e.g. createNewItemsOnly, overwriteItem)")
+	fs.BoolVar(&request.ContinueOnError, "continue-on-error", false, "report failed rows instead of failing (kv)")
+	_ = fs.Parse(args)
+
+	if err := checkRequired(fs, "backend", "table"); err != nil {
+		return err
+	}
+
+	var err error
+	if request.SaveMode, err = frames.SaveModeFromString(saveMode); err != nil {
+		return err
+	}
+	request.PartitionKeys = partitionKeys
+
+	if format == "" {
+		format = inputFormat(input)
+	}
+
+	var r io.Reader = os.Stdin
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	dec, err := framesHttp.NewFrameDecoder(format, bufio.NewReader(r), indices, timeColumns)
+	if err != nil {
+		return err
+	}
+
+	appender, err := client.Write(ctx, request)
+	if err != nil {
+		return err
+	}
+	defer appender.Close()
+
+	nFrames, nRows := 0, 0
+	for {
+		frame, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "can't decode input")
+		}
+
+		if err := appender.Add(frame); err != nil {
+			return err
+		}
+		nFrames++
+		nRows += frame.Len()
+	}
+
+	if err := appender.WaitForComplete(writeTimeout(ctx)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "wrote %d rows in %d frames\n", nRows, nFrames)
+
+	fra, ok := appender.(frames.FailedRowsAppender)
+	if !ok || fra.FailedRows() == nil {
+		return nil
+	}
+
+	failedRows := fra.FailedRows()
+	out, _ := newFrameWriter(tableFormat, os.Stdout)
+	if err := out.Write(failedRows); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%d rows failed", failedRows.Len())
+}
+
+// inputFormat returns the input format by the file extension
+func inputFormat(path string) string {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return jsonFormat
+	case ".ndjson", ".jsonl":
+		return ndjsonFormat
+	}
+
+	return csvFormat
+}
+
+// writeTimeout returns the time left for ctx (or a long time if it has no
+// deadline)
+func writeTimeout(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+
+	return 24 * time.Hour
+}
+
+func runCreate(ctx context.Context, client frames.ClientV2, args []string) error {
+	request := &pb.CreateRequest{}
+	var ignoreExisting bool
+
+	fs := newFlagSet("create", &request.Backend, &request.Table)
+	fs.BoolVar(&ignoreExisting, "ignore-existing", false, "don't fail if the table exists")
+	fs.StringVar(&request.Rate, "rate", "", "ingestion rate (tsdb, e.g. 1/s)")
+	fs.StringVar(&request.Aggregates, "aggregates", "", "comma separated pre-aggregates (tsdb)")
+	fs.StringVar(&request.AggregationGranularity, "aggregation-granularity", "", "pre-aggregation granularity (tsdb)")
+	fs.Int64Var(&request.Shards, "shards", 0, "number of shards (stream)")
+	fs.Int64Var(&request.RetentionHours, "retention-hours", 0, "retention in hours (stream)")
+	_ = fs.Parse(args)
+
+	if err := checkRequired(fs, "backend", "table"); err != nil {
+		return err
+	}
+
+	if ignoreExisting {
+		request.IfExists = pb.ErrorOptions_IGNORE
+	}
+
+	return client.Create(ctx, request)
+}
+
+func runDelete(ctx context.Context, client frames.ClientV2, args []string) error {
+	request := &pb.DeleteRequest{}
+	var (
+		ignoreMissing bool
+		metrics       listFlag
+	)
+
+	fs := newFlagSet("delete", &request.Backend, &request.Table)
+	fs.StringVar(&request.Filter, "filter", "", "delete only rows matching filter")
+	fs.BoolVar(&ignoreMissing, "ignore-missing", false, "don't fail if the table doesn't exist")
+	fs.StringVar(&request.Start, "start", "", "start time (tsdb)")
+	fs.StringVar(&request.End, "end", "", "end time (tsdb)")
+	fs.Var(&metrics, "metrics", "comma separated metrics to delete (tsdb)")
+	_ = fs.Parse(args)
+
+	if err := checkRequired(fs, "backend", "table"); err != nil {
+		return err
+	}
+
+	if ignoreMissing {
+		request.IfMissing = pb.ErrorOptions_IGNORE
+	}
+	request.Metrics = metrics
+
+	return client.Delete(ctx, request)
+}
+
+func runExec(ctx context.Context, client frames.ClientV2, args []string) error {
+	request := &pb.ExecRequest{}
+	var format string
+	execArgs := mapFlag{}
+
+	fs := newFlagSet("exec", &request.Backend, &request.Table)
+	fs.StringVar(&request.Command, "command", "", "command name")
+	fs.StringVar(&request.Expression, "expression", "", "command expression")
+	fs.Var(execArgs, "arg", "command argument as key=value (can be repeated)")
+	fs.StringVar(&format, "format", tableFormat, "output format (table, csv, json or ndjson)")
+	_ = fs.Parse(args)
+
+	if err := checkRequired(fs, "backend", "table", "command"); err != nil {
+		return err
+	}
+
+	out, err := newFrameWriter(format, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if request.Args, err = execArgsProto(execArgs); err != nil {
+		return err
+	}
+
+	frame, err := client.Exec(ctx, request)
+	if err != nil || frame == nil {
+		return err
+	}
+
+	if err := out.Write(frame); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// execArgsProto converts key=value arguments to values, numbers and booleans
+// are converted to their types
+func execArgsProto(args map[string]string) (map[string]*pb.Value, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]interface{}, len(args))
+	for key, arg := range args {
+		values[key] = argValue(arg)
+	}
+
+	return pb.FromGoMap(values)
+}
+
+func argValue(arg string) interface{} {
+	if i, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		return i
+	}
+
+	if f, err := strconv.ParseFloat(arg, 64); err == nil {
+		return f
+	}
+
+	if b, err := strconv.ParseBool(arg); err == nil {
+		return b
+	}
+
+	return arg
+}
+
+// historyFlagSet returns a flag set for history request fields
+func historyFlagSet(name string, request *pb.HistoryRequest, format *string) *flag.FlagSet {
+	fs := newFlagSet(name, &request.Backend, &request.Table)
+	fs.StringVar(&request.User, "user", "", "user name")
+	fs.StringVar(&request.Action, "action", "", "action (e.g. read, write, create, delete, execute)")
+	fs.StringVar(&request.Container, "container", "", "container name")
+	fs.StringVar(&request.MinStartTime, "min-start-time", "", "minimal start time")
+	fs.StringVar(&request.MaxStartTime, "max-start-time", "", "maximal start time")
+	fs.Int64Var(&request.MinDuration, "min-duration", 0, "minimal action duration")
+	fs.Int64Var(&request.MaxDuration, "max-duration", 0, "maximal action duration")
+	fs.StringVar(format, "format", tableFormat, "output format (table, csv, json or ndjson)")
+	return fs
+}
+
+func runHistory(ctx context.Context, client frames.ClientV2, args []string) error {
+	request := &pb.HistoryRequest{}
+	var format string
+	fs := historyFlagSet("history", request, &format)
+	_ = fs.Parse(args)
+
+	out, err := newFrameWriter(format, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	it, err := client.History(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return writeFrames(it, out)
+}
+
+func runVersion(ctx context.Context, client frames.ClientV2, args []string) error {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	version, err := client.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(version)
+	return nil
+}
+
+// History log columns used by tables
+const (
+	historyContainerColumn = "Container"
+	historyBackendColumn   = "BackendName"
+	historyTableColumn     = "TableName"
+)
+
+// runTables lists the tables in the history log, there's no API for listing
+// tables
+func runTables(ctx context.Context, client frames.ClientV2, args []string) error {
+	request := &pb.HistoryRequest{}
+	var format string
+	fs := historyFlagSet("tables", request, &format)
+	_ = fs.Parse(args)
+
+	out, err := newFrameWriter(format, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	it, err := client.History(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	tables := make(map[[3]string]bool)
+	for it.Next() {
+		if err := historyTables(it.At(), tables); err != nil {
+			return err
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	frame, err := tablesFrame(tables)
+	if err != nil {
+		return err
+	}
+
+	if err := out.Write(frame); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// historyTables adds the (container, backend, table) of history rows to tables
+func historyTables(frame frames.Frame, tables map[[3]string]bool) error {
+	var columns [3]frames.Column
+	for i, name := range []string{historyContainerColumn, historyBackendColumn, historyTableColumn} {
+		col, err := frame.Column(name)
+		if err != nil {
+			return err
+		}
+		columns[i] = col
+	}
+
+	for row := 0; row < frame.Len(); row++ {
+		var key [3]string
+		for i, col := range columns {
+			value, err := col.StringAt(row)
+			if err != nil {
+				return err
+			}
+			key[i] = value
+		}
+
+		if key[2] != "" {
+			tables[key] = true
+		}
+	}
+
+	return nil
+}
+
+// tablesFrame returns a frame of sorted tables
+func tablesFrame(tables map[[3]string]bool) (frames.Frame, error) {
+	keys := make([][3]string, 0, len(tables))
+	for key := range tables {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		for k := range keys[i] {
+			if keys[i][k] != keys[j][k] {
+				return keys[i][k] < keys[j][k]
+			}
+		}
+		return false
+	})
+
+	var columns []frames.Column
+	for i, name := range []string{"container", "backend", "table"} {
+		values := make([]string, len(keys))
+		for j, key := range keys {
+			values[j] = key[i]
+		}
+
+		col, err := frames.NewSliceColumn(name, values)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+
+	return frames.NewFrame(columns, nil, nil)
+}
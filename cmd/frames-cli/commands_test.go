@@ -0,0 +1,42 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/v3io/frames/pb"
+)
+
+func TestApplySQL(t *testing.T) {
+	request := &pb.ReadRequest{}
+	if err := applySQL(request, "SELECT name FROM users WHERE age > 30"); err != nil {
+		t.Fatal(err)
+	}
+
+	if request.Table != "users" || len(request.Columns) != 1 || request.Columns[0] != "name" {
+		t.Fatalf("bad request - %+v", request)
+	}
+
+	if request.Filter != "age > 30" {
+		t.Fatalf("bad filter - %q", request.Filter)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+// frames-cli is a command line client for framesd.
+//
+// Usage:
+//
+//	frames-cli [-url URL] [-timeout DURATION] COMMAND [ARGS]
+//
+// The server URL defaults to the V3IO_URL environment variable and the
+// session is read from V3IO_SESSION (see frames.SessionFromEnv). Run
+// "frames-cli COMMAND -h" for command arguments.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/v3io/frames"
+	// Register client URL schemes
+	_ "github.com/v3io/frames/embedded"
+	_ "github.com/v3io/frames/grpc"
+	_ "github.com/v3io/frames/http"
+)
+
+var (
+	// Version is frames-cli version (populated by the build process)
+	Version = "unknown"
+)
+
+// command is a frames-cli sub command
+type command struct {
+	help string
+	run  func(ctx context.Context, client frames.ClientV2, args []string) error
+}
+
+var commands = map[string]*command{
+	"read":    {"read data (or run interactive SQL with -interactive)", runRead},
+	"write":   {"write CSV or JSON data", runWrite},
+	"create":  {"create a table", runCreate},
+	"delete":  {"delete a table or rows", runDelete},
+	"exec":    {"execute a backend command", runExec},
+	"history": {"show the request history log", runHistory},
+	"version": {"show the server version", runVersion},
+	"tables":  {"list tables found in the request history log", runTables},
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "usage: %s [options] COMMAND [ARGS]\n\nCommands:\n", os.Args[0])
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-8s %s\n", name, commands[name].help)
+	}
+
+	fmt.Fprintf(out, "\nOptions:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	var config struct {
+		url         string
+		timeout     time.Duration
+		compression string
+		version     bool
+	}
+
+	flag.StringVar(&config.url, "url", "", "server URL (default from V3IO_URL, e.g. grpc://localhost:8081)")
+	flag.DurationVar(&config.timeout, "timeout", 0, "command timeout (0 for none)")
+	flag.StringVar(&config.compression, "compression", "", "transport compression (gzip or zstd)")
+	flag.BoolVar(&config.version, "version", false, "show version and exit")
+	flag.Usage = usage
+	flag.Parse()
+
+	if config.version {
+		fmt.Printf("%s version %s\n", os.Args[0], Version)
+		return
+	}
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "error: unknown command - %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	var options []frames.ClientOption
+	if config.compression != "" {
+		options = append(options, frames.WithCompression(config.compression))
+	}
+
+	// Session is read from the environment by NewClient
+	client, err := frames.NewClient(config.url, nil, nil, options...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: can't create client - %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := runCommand(cmd, client, flag.Args()[1:], config.timeout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func runCommand(cmd *command, client frames.ClientV2, args []string, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return cmd.run(ctx, client, args)
+}
+
+// listFlag is a comma separated list flag
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+
+	return nil
+}
+
+// mapFlag is a key=value flag that can be repeated
+type mapFlag map[string]string
+
+func (m mapFlag) String() string {
+	var pairs []string
+	for key, value := range m {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m mapFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i < 1 {
+		return fmt.Errorf("bad key=value - %q", value)
+	}
+
+	m[value[:i]] = value[i+1:]
+	return nil
+}
+
+// newFlagSet returns a flag set for a command with the common -backend and
+// -table flags
+func newFlagSet(name string, backend *string, table *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(backend, "backend", "", "backend name (e.g. kv, tsdb, stream, csv)")
+	if table != nil {
+		fs.StringVar(table, "table", "", "table name")
+	}
+	return fs
+}
+
+// checkRequired returns an error if one of the flags is empty
+func checkRequired(fs *flag.FlagSet, names ...string) error {
+	for _, name := range names {
+		if fs.Lookup(name).Value.String() == "" {
+			return fmt.Errorf("%s: missing -%s", fs.Name(), name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/v3io/frames"
+)
+
+// Output formats
+const (
+	tableFormat  = "table"  // Aligned columns
+	csvFormat    = "csv"    // CSV with header line
+	jsonFormat   = "json"   // JSON list of row objects
+	ndjsonFormat = "ndjson" // Row object per line
+)
+
+// frameWriter writes frames in an output format, columns are taken from the
+// first frame
+type frameWriter interface {
+	Write(frame frames.Frame) error
+	Close() error
+}
+
+func newFrameWriter(format string, w io.Writer) (frameWriter, error) {
+	switch format {
+	case tableFormat:
+		return &tableWriter{w: tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)}, nil
+	case csvFormat:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case jsonFormat:
+		return &jsonWriter{w: w, first: true}, nil
+	case ndjsonFormat:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format - %q", format)
+}
+
+// rowColumns returns the names of frame columns in row order (index columns
+// first), using the same names as frame.IterRows for unnamed columns
+func rowColumns(frame frames.Frame) []string {
+	var names []string
+	for i, col := range frame.Indices() {
+		name := col.Name()
+		if name == "" {
+			if i == 0 {
+				name = "idx"
+			} else {
+				name = fmt.Sprintf("idx-%d", i)
+			}
+		}
+		names = append(names, name)
+	}
+
+	for i, name := range frame.Names() {
+		if name == "" {
+			name = fmt.Sprintf("col-%d", i)
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// frameRows calls fn with every row of frame, null values are nil
+func frameRows(frame frames.Frame, fn func(row map[string]interface{}) error) error {
+	iter := frame.IterRows(true)
+	for iter.Next() {
+		row := iter.Row()
+		for name := range row {
+			if frame.IsNull(iter.RowNum(), name) {
+				row[name] = nil
+			}
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+// formatValue formats a value for text outputs
+func formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// jsonValue replaces values that can't be encoded to JSON (NaN, ±Inf)
+func jsonValue(value interface{}) interface{} {
+	if f, ok := value.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return nil
+	}
+
+	return value
+}
+
+type tableWriter struct {
+	w       *tabwriter.Writer
+	columns []string
+}
+
+func (tw *tableWriter) Write(frame frames.Frame) error {
+	if tw.columns == nil {
+		tw.columns = rowColumns(frame)
+		if _, err := fmt.Fprintln(tw.w, strings.Join(tw.columns, "\t")); err != nil {
+			return err
+		}
+	}
+
+	values := make([]string, len(tw.columns))
+	return frameRows(frame, func(row map[string]interface{}) error {
+		for i, name := range tw.columns {
+			values[i] = formatValue(row[name])
+		}
+		_, err := fmt.Fprintln(tw.w, strings.Join(values, "\t"))
+		return err
+	})
+}
+
+func (tw *tableWriter) Close() error {
+	return tw.w.Flush()
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (cw *csvWriter) Write(frame frames.Frame) error {
+	if cw.columns == nil {
+		cw.columns = rowColumns(frame)
+		if err := cw.w.Write(cw.columns); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(cw.columns))
+	return frameRows(frame, func(row map[string]interface{}) error {
+		for i, name := range cw.columns {
+			record[i] = formatValue(row[name])
+		}
+		return cw.w.Write(record)
+	})
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type jsonWriter struct {
+	w     io.Writer
+	first bool
+}
+
+func (jw *jsonWriter) Write(frame frames.Frame) error {
+	return frameRows(frame, func(row map[string]interface{}) error {
+		for name, value := range row {
+			row[name] = jsonValue(value)
+		}
+
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+
+		sep := ",\n"
+		if jw.first {
+			sep = "[\n"
+			jw.first = false
+		}
+
+		if _, err := io.WriteString(jw.w, sep); err != nil {
+			return err
+		}
+		_, err = jw.w.Write(data)
+		return err
+	})
+}
+
+func (jw *jsonWriter) Close() error {
+	end := "\n]\n"
+	if jw.first { // No rows
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(jw.w, end)
+	return err
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (nw *ndjsonWriter) Write(frame frames.Frame) error {
+	return frameRows(frame, func(row map[string]interface{}) error {
+		for name, value := range row {
+			row[name] = jsonValue(value)
+		}
+		return nw.enc.Encode(row)
+	})
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nil
+}
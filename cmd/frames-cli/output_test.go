@@ -0,0 +1,87 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package main
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/v3io/frames"
+)
+
+func outputFrame(t *testing.T) frames.Frame {
+	names, err := frames.NewSliceColumn("name", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scores, err := frames.NewSliceColumn("score", []float64{1.5, math.NaN()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := frames.NewSliceColumn("id", []int64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame([]frames.Column{names, scores}, []frames.Column{ids}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
+
+func TestFrameWriter(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{tableFormat, "id  name   score\n1   alice  1.5\n2   bob    NaN\n"},
+		{csvFormat, "id,name,score\n1,alice,1.5\n2,bob,NaN\n"},
+		{jsonFormat, "[\n" + `{"id":1,"name":"alice","score":1.5},` + "\n" + `{"id":2,"name":"bob","score":null}` + "\n]\n"},
+		{ndjsonFormat, `{"id":1,"name":"alice","score":1.5}` + "\n" + `{"id":2,"name":"bob","score":null}` + "\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			out, err := newFrameWriter(tc.format, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := out.Write(outputFrame(t)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := out.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tc.expected {
+				t.Fatalf("bad output:\n%s\nexpected:\n%s", buf.String(), tc.expected)
+			}
+		})
+	}
+}
@@ -50,8 +50,8 @@ func main() {
 	}
 
 	flag.StringVar(&config.file, "config", "", "path to configuration file (YAML)")
-	flag.StringVar(&config.httpAddr, "httpAddr", ":8080", "address to listen on HTTP")
-	flag.StringVar(&config.grpcAddr, "grpcAddr", ":8081", "address to listen on gRPC")
+	flag.StringVar(&config.httpAddr, "httpAddr", ":8080", "address to listen on HTTP (host:port or unix:///path/to/socket)")
+	flag.StringVar(&config.grpcAddr, "grpcAddr", ":8081", "address to listen on gRPC (host:port or unix:///path/to/socket)")
 	flag.Parse()
 
 	log.SetFlags(0) // Show only messages
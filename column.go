@@ -36,8 +36,9 @@ var (
 type colImpl struct {
 	// We can embed column since the field names and the method names are the same
 	// (e.g. Name string and Name() string)
-	msg   *pb.Column
-	times []time.Time
+	msg      *pb.Column
+	times    []time.Time
+	location *time.Location // of times, see pb.Column.TimeZone
 }
 
 func (c *colImpl) Len() int {
@@ -204,7 +205,7 @@ func (c *colImpl) Times() ([]time.Time, error) {
 			if c.msg.Kind == pb.Column_LABEL {
 				idx = 0
 			}
-			times[i] = pb.NSToTime(c.msg.Times[idx])
+			times[i] = pb.NSToTime(c.msg.Times[idx]).In(c.timeLocation())
 		}
 
 		c.times = times
@@ -227,7 +228,22 @@ func (c *colImpl) TimeAt(i int) (time.Time, error) {
 	}
 
 	ns := c.msg.Times[i]
-	return pb.NSToTime(ns), nil
+	return pb.NSToTime(ns).In(c.timeLocation()), nil
+}
+
+// timeLocation returns the location of the column times
+func (c *colImpl) timeLocation() *time.Location {
+	if c.location == nil {
+		c.location = time.Local
+		if c.msg.TimeZone != "" {
+			// Time zones are checked when set (see ColumnInTimeZone)
+			if location, err := time.LoadLocation(c.msg.TimeZone); err == nil {
+				c.location = location
+			}
+		}
+	}
+
+	return c.location
 }
 
 func (c *colImpl) Bools() ([]bool, error) {
@@ -265,9 +281,10 @@ func (c *colImpl) Slice(start int, end int) (Column, error) {
 	}
 
 	msg := &pb.Column{
-		Kind:  c.msg.Kind,
-		Dtype: c.msg.Dtype,
-		Name:  c.msg.Name,
+		Kind:     c.msg.Kind,
+		Dtype:    c.msg.Dtype,
+		Name:     c.msg.Name,
+		TimeZone: c.msg.TimeZone,
 	}
 
 	if c.msg.Kind == pb.Column_LABEL {
@@ -330,6 +347,33 @@ func (c *colImpl) CopyWithName(newName string) Column {
 	return newCol
 }
 
+// ColumnInTimeZone returns a time column with the data of col whose times
+// are in the IANA time zone timeZone (e.g. "America/New_York"), or in local
+// time if timeZone is empty
+func ColumnInTimeZone(col Column, timeZone string) (Column, error) {
+	if col.DType() != TimeType {
+		return nil, fmt.Errorf("%q is not a time column", col.Name())
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("bad time zone %q - %s", timeZone, err)
+	}
+
+	impl, ok := col.(*colImpl)
+	if !ok {
+		return nil, fmt.Errorf("unsupported column type - %T", col)
+	}
+
+	msg := *impl.msg
+	msg.TimeZone = timeZone
+	if timeZone == "" {
+		location = time.Local
+	}
+
+	return &colImpl{msg: &msg, location: location}, nil
+}
+
 // NewSliceColumn returns a new slice column
 func NewSliceColumn(name string, data interface{}) (Column, error) {
 	msg := &pb.Column{
@@ -47,3 +47,57 @@ func TestLabelColTimeAt(t *testing.T) {
 		t.Fatalf("bad time %v != %v", ts1, ts)
 	}
 }
+
+func TestColumnInTimeZoneDST(t *testing.T) {
+	// Clocks in New York moved from 02:00 EST to 03:00 EDT at 2021-03-14 07:00 UTC
+	before := time.Date(2021, 3, 14, 6, 59, 59, 999999999, time.UTC)
+	after := time.Date(2021, 3, 14, 7, 0, 0, 0, time.UTC)
+	col, err := NewSliceColumn("t", []time.Time{before, after})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nyCol, err := ColumnInTimeZone(col, "America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	times, err := nyCol.Times()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"2021-03-14T01:59:59.999999999-05:00",
+		"2021-03-14T03:00:00-04:00",
+	}
+	for i, ts := range times {
+		if s := ts.Format(time.RFC3339Nano); s != expected[i] {
+			t.Fatalf("%d: bad time %s != %s", i, s, expected[i])
+		}
+	}
+
+	ts, err := nyCol.TimeAt(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ts.Equal(before) || ts.Location().String() != "America/New_York" {
+		t.Fatalf("bad time %v", ts)
+	}
+
+	slice, err := nyCol.Slice(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err = slice.TimeAt(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := ts.Format(time.RFC3339Nano); s != expected[1] {
+		t.Fatalf("bad sliced time %s != %s", s, expected[1])
+	}
+
+	if _, err := ColumnInTimeZone(col, "Nowhere/Special"); err == nil {
+		t.Fatal("no error on bad time zone")
+	}
+}
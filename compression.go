@@ -0,0 +1,136 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported compressions (names match HTTP content codings and gRPC
+// compressor names)
+const (
+	NoCompression   = ""
+	GzipCompression = "gzip"
+	ZstdCompression = "zstd"
+)
+
+// CompressWriter is a compressing writer. Flush writes pending data so the
+// reader can decode everything written so far.
+type CompressWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// ValidateCompression returns an error if compression is not supported
+func ValidateCompression(compression string) error {
+	switch compression {
+	case NoCompression, GzipCompression, ZstdCompression:
+		return nil
+	}
+
+	return fmt.Errorf("unknown compression - %q", compression)
+}
+
+// NewCompressWriter returns a writer compressing to w. Closing the returned
+// writer does not close w.
+func NewCompressWriter(compression string, w io.Writer) (CompressWriter, error) {
+	switch compression {
+	case NoCompression:
+		return &nopCompressWriter{w}, nil
+	case GzipCompression:
+		return gzip.NewWriter(w), nil
+	case ZstdCompression:
+		return zstd.NewWriter(w)
+	}
+
+	return nil, fmt.Errorf("unknown compression - %q", compression)
+}
+
+// NewDecompressReader returns a reader decompressing from r. Concatenated
+// compressed streams are decoded as one stream.
+func NewDecompressReader(compression string, r io.Reader) (io.ReadCloser, error) {
+	switch compression {
+	case NoCompression:
+		return io.NopCloser(r), nil
+	case GzipCompression:
+		return gzip.NewReader(r)
+	case ZstdCompression:
+		dec, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	}
+
+	return nil, fmt.Errorf("unknown compression - %q", compression)
+}
+
+// NegotiateCompression returns the preferred supported compression from an
+// HTTP Accept-Encoding header value (zstd is preferred on equal weights)
+func NegotiateCompression(acceptEncoding string) string {
+	best, bestWeight := NoCompression, 0.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name != GzipCompression && name != ZstdCompression {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err == nil {
+					weight = q
+				}
+			}
+		}
+
+		if weight <= 0 { // q=0 means "not acceptable"
+			continue
+		}
+
+		if weight > bestWeight || (weight == bestWeight && name == ZstdCompression) {
+			best, bestWeight = name, weight
+		}
+	}
+
+	return best
+}
+
+type nopCompressWriter struct {
+	io.Writer
+}
+
+func (w *nopCompressWriter) Flush() error {
+	return nil
+}
+
+func (w *nopCompressWriter) Close() error {
+	return nil
+}
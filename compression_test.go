@@ -0,0 +1,91 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestNegotiateCompression(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected string
+	}{
+		{"", NoCompression},
+		{"identity", NoCompression},
+		{"gzip", GzipCompression},
+		{"gzip, deflate, br", GzipCompression},
+		{"gzip, zstd", ZstdCompression},
+		{"zstd;q=0.5, gzip", GzipCompression},
+		{"zstd;q=0, gzip;q=0", NoCompression},
+		{"GZIP;q=0.8", GzipCompression},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			if out := NegotiateCompression(tc.header); out != tc.expected {
+				t.Fatalf("%q: %q != %q", tc.header, out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	for _, compression := range []string{NoCompression, GzipCompression, ZstdCompression} {
+		t.Run(compression, func(t *testing.T) {
+			var buf bytes.Buffer
+			// Two complete streams, should be decoded as one
+			for _, chunk := range []string{"hello ", "world"} {
+				w, err := NewCompressWriter(compression, &buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := io.WriteString(w, chunk); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r, err := NewDecompressReader(compression, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != "hello world" {
+				t.Fatalf("bad data - %q", data)
+			}
+		})
+	}
+
+	if err := ValidateCompression("lz4"); err == nil {
+		t.Fatal("no error on unknown compression")
+	}
+}
@@ -23,7 +23,9 @@ package frames
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
 )
 
 // LogConfig is the logging configuration
@@ -64,6 +66,35 @@ type Config struct {
 	Backends []*BackendConfig `json:"backends,omitempty"`
 
 	DisableProfiling bool `json:"disableProfiling,omitempty"`
+
+	// Permissions of Unix domain sockets the servers listen on (octal,
+	// e.g. "0660"), see Listen
+	UnixSocketMode string `json:"unixSocketMode,omitempty"`
+
+	HTTP HTTPConfig `json:"http,omitempty"`
+}
+
+// HTTPConfig is the HTTP server configuration, zero values mean no limit
+// unless a field documents its default
+type HTTPConfig struct {
+	MaxRequestBodySize  int `json:"maxRequestBodySize,omitempty"` // bytes, 8GB if zero (see InitDefaults)
+	ReadTimeoutSeconds  int `json:"readTimeoutSeconds,omitempty"`
+	WriteTimeoutSeconds int `json:"writeTimeoutSeconds,omitempty"` // also limits live tail duration
+	IdleTimeoutSeconds  int `json:"idleTimeoutSeconds,omitempty"`  // ReadTimeoutSeconds if zero
+	MaxConnsPerIP       int `json:"maxConnsPerIP,omitempty"`
+	Concurrency         int `json:"concurrency,omitempty"` // maximal number of concurrent connections, 256K if zero
+
+	// Don't compress responses even if the client accepts compression
+	DisableCompression bool `json:"disableCompression,omitempty"`
+
+	RateLimit RateLimitConfig `json:"rateLimit,omitempty"`
+}
+
+// RateLimitConfig is a token bucket rate limit per client IP (per connection
+// for Unix domain socket clients), disabled when RequestsPerSecond is 0
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty"`
+	Burst             int     `json:"burst,omitempty"`
 }
 
 // InitDefaults initializes the defaults for configuration
@@ -72,6 +103,18 @@ func (c *Config) InitDefaults() error {
 		c.DefaultTimeout = 300
 	}
 
+	if c.HTTP.MaxRequestBodySize == 0 {
+		c.HTTP.MaxRequestBodySize = 8 * (1 << 30) // 8GB
+	}
+
+	if c.UnixSocketMode == "" {
+		c.UnixSocketMode = DefaultUnixSocketMode
+	}
+
+	if c.HTTP.RateLimit.RequestsPerSecond > 0 && c.HTTP.RateLimit.Burst == 0 {
+		c.HTTP.RateLimit.Burst = int(math.Ceil(c.HTTP.RateLimit.RequestsPerSecond))
+	}
+
 	for _, backendConfig := range c.Backends {
 		initBackendDefaults(backendConfig, c)
 	}
@@ -129,9 +172,30 @@ func (c *Config) Validate() error {
 		names[backend.Name] = true
 	}
 
+	if c.UnixSocketMode != "" {
+		if _, err := c.SocketMode(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// SocketMode returns the permissions of Unix domain sockets
+func (c *Config) SocketMode() (os.FileMode, error) {
+	mode := c.UnixSocketMode
+	if mode == "" {
+		mode = DefaultUnixSocketMode
+	}
+
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || perm&^uint64(os.ModePerm) != 0 {
+		return 0, fmt.Errorf("bad unixSocketMode %q", c.UnixSocketMode)
+	}
+
+	return os.FileMode(perm), nil
+}
+
 // BackendConfig is default backend configuration
 type BackendConfig struct {
 	Type                    string `json:"type"` // v3io, csv, ...
@@ -0,0 +1,374 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+// Package embedded provides an in-process frames client. Frames are passed
+// to and from the backends without serialization.
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/api"
+	"github.com/v3io/frames/pb"
+)
+
+// Client is an in-process client calling the frames API directly. Use
+// frames.NewV1Client to get a frames.Client.
+type Client struct {
+	api     *api.API
+	session *frames.Session
+	logger  logger.Logger
+	version string
+}
+
+var (
+	// Make sure we're implementing frames.ClientV2
+	_ frames.ClientV2 = &Client{}
+)
+
+// NewClient returns a new embedded client over api. Transport options (e.g.
+// compression) are validated but have no effect.
+func NewClient(api *api.API, session *frames.Session, logger logger.Logger, options ...frames.ClientOption) (*Client, error) {
+	if api == nil {
+		return nil, fmt.Errorf("nil API")
+	}
+
+	if _, err := frames.NewClientConfig(options...); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if logger == nil {
+		logger, err = frames.NewLogger("info")
+		if err != nil {
+			return nil, errors.Wrap(err, "can't create logger")
+		}
+	}
+
+	if session == nil {
+		session, err = frames.SessionFromEnv()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	client := &Client{
+		api:     api,
+		session: session,
+		logger:  logger,
+		version: moduleVersion(),
+	}
+
+	return client, nil
+}
+
+// NewClientFromConfig returns a new embedded client with its own API
+// created from config (the same configuration framesd uses)
+func NewClientFromConfig(config *frames.Config, session *frames.Session, logger logger.Logger, options ...frames.ClientOption) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "bad configuration")
+	}
+
+	if err := config.InitDefaults(); err != nil {
+		return nil, errors.Wrap(err, "failed to init defaults")
+	}
+
+	var err error
+	if logger == nil {
+		logger, err = frames.NewLogger(config.Log.Level)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't create logger")
+		}
+	}
+
+	api, err := api.New(logger, config, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't create API")
+	}
+
+	return NewClient(api, session, logger, options...)
+}
+
+// moduleVersion returns the version of the linked frames module
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Path == "github.com/v3io/frames" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/v3io/frames" {
+			return dep.Version
+		}
+	}
+
+	return "unknown"
+}
+
+// requestSession returns the session to use (a copy, since it's modified)
+// with the credentials split out, the same way the servers do
+func (c *Client) requestSession(session *frames.Session) (*frames.Session, frames.SecretString, frames.SecretString) {
+	if session == nil {
+		session = c.session
+	}
+
+	if session == nil {
+		return nil, frames.SecretString{}, frames.SecretString{}
+	}
+
+	sessionCopy := *session
+	password := frames.InitSecretString(sessionCopy.Password)
+	token := frames.InitSecretString(sessionCopy.Token)
+	sessionCopy.Password = ""
+	sessionCopy.Token = ""
+
+	return &sessionCopy, password, token
+}
+
+// Read reads data from the backend. Cancel ctx to stop reading before the
+// iterator is done.
+func (c *Client) Read(ctx context.Context, request *pb.ReadRequest) (frames.FrameIterator, error) {
+	proto := *request // Backends might change the request
+	req := &frames.ReadRequest{Proto: &proto}
+	proto.Session, req.Password, req.Token = c.requestSession(request.Session)
+
+	return newFrameIterator(ctx, func(out chan frames.Frame) error {
+		return c.api.Read(req, out)
+	}), nil
+}
+
+// Write writes data to the backend, ctx is used for the whole write
+func (c *Client) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
+	if request.Backend == "" || request.Table == "" {
+		return nil, fmt.Errorf("missing request parameters")
+	}
+
+	req := *request
+	req.Session, req.Password, req.Token = c.requestSession(request.Session)
+
+	appender := &frameAppender{
+		ctx:  ctx,
+		ch:   make(chan frames.Frame, 1),
+		done: make(chan struct{}),
+	}
+
+	next := func() (frames.Frame, error) {
+		frame, ok := <-appender.ch
+		if !ok {
+			return nil, io.EOF
+		}
+		return frame, nil
+	}
+
+	go func() {
+		defer close(appender.done)
+		result, err := c.api.WriteStream(&req, next, nil)
+		if result != nil && err == nil {
+			appender.failedRows, err = frames.NewFailedRowsFrame(result.FailedRows)
+		}
+		appender.err = err
+	}()
+
+	return appender, nil
+}
+
+// Create creates a table
+func (c *Client) Create(ctx context.Context, request *pb.CreateRequest) error {
+	proto := *request
+	req := &frames.CreateRequest{Proto: &proto}
+	proto.Session, req.Password, req.Token = c.requestSession(request.Session)
+
+	return c.api.Create(req)
+}
+
+// Delete deletes data or table
+func (c *Client) Delete(ctx context.Context, request *pb.DeleteRequest) error {
+	proto := *request
+	req := &frames.DeleteRequest{Proto: &proto}
+	proto.Session, req.Password, req.Token = c.requestSession(request.Session)
+
+	return c.api.Delete(req)
+}
+
+// Exec executes a command on the backend
+func (c *Client) Exec(ctx context.Context, request *pb.ExecRequest) (frames.Frame, error) {
+	proto := *request
+	req := &frames.ExecRequest{Proto: &proto}
+	proto.Session, req.Password, req.Token = c.requestSession(request.Session)
+
+	return c.api.Exec(req)
+}
+
+// History returns the request history log
+func (c *Client) History(ctx context.Context, request *pb.HistoryRequest) (frames.FrameIterator, error) {
+	proto := *request
+	req := &frames.HistoryRequest{Proto: &proto}
+	proto.Session, req.Password, req.Token = c.requestSession(request.Session)
+
+	return newFrameIterator(ctx, func(out chan frames.Frame) error {
+		return c.api.History(req, out)
+	}), nil
+}
+
+// Version returns the version of the frames module
+func (c *Client) Version(ctx context.Context) (string, error) {
+	return c.version, nil
+}
+
+// BatchWrite writes frames to several tables, either all of them are written
+// or none is
+func (c *Client) BatchWrite(ctx context.Context, request *frames.BatchWriteRequest) (*frames.BatchWriteResult, error) {
+	req := *request
+	req.Session, req.Password, req.Token = c.requestSession(request.Session)
+
+	return c.api.BatchWrite(&req)
+}
+
+// frameIterator iterates over frames sent by an API call
+type frameIterator struct {
+	ctx   context.Context
+	ch    chan frames.Frame
+	errCh chan error
+	frame frames.Frame
+	err   error
+	done  bool
+}
+
+func newFrameIterator(ctx context.Context, call func(out chan frames.Frame) error) *frameIterator {
+	it := &frameIterator{
+		ctx:   ctx,
+		ch:    make(chan frames.Frame),
+		errCh: make(chan error, 1),
+	}
+
+	go func() {
+		defer close(it.ch)
+		it.errCh <- call(it.ch)
+	}()
+
+	return it
+}
+
+func (it *frameIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.cancel(err)
+		return false
+	}
+
+	select {
+	case frame, ok := <-it.ch:
+		if !ok {
+			it.done = true
+			it.err = <-it.errCh
+			return false
+		}
+		it.frame = frame
+		return true
+	case <-it.ctx.Done():
+		it.cancel(it.ctx.Err())
+		return false
+	}
+}
+
+func (it *frameIterator) cancel(err error) {
+	it.err = err
+	// Let the API call finish
+	go func() {
+		for range it.ch {
+		}
+	}()
+}
+
+func (it *frameIterator) Err() error {
+	return it.err
+}
+
+func (it *frameIterator) At() frames.Frame {
+	return it.frame
+}
+
+// frameAppender passes frames to an API write call
+type frameAppender struct {
+	ctx    context.Context
+	ch     chan frames.Frame
+	done   chan struct{} // closed when the API call returns
+	err    error         // API call error, valid after done is closed
+	closed bool
+
+	failedRows frames.Frame // valid after done is closed
+}
+
+func (a *frameAppender) Add(frame frames.Frame) error {
+	if a.closed {
+		return fmt.Errorf("appender closed")
+	}
+
+	select {
+	case a.ch <- frame:
+		return nil
+	case <-a.done:
+		if a.err != nil {
+			return a.err
+		}
+		return fmt.Errorf("write ended")
+	case <-a.ctx.Done():
+		return a.ctx.Err()
+	}
+}
+
+func (a *frameAppender) WaitForComplete(timeout time.Duration) error {
+	if !a.closed {
+		close(a.ch)
+		a.closed = true
+	}
+
+	select {
+	case <-a.done:
+		return a.err
+	case <-time.After(timeout):
+		return fmt.Errorf("timeout after %s", timeout)
+	case <-a.ctx.Done():
+		return a.ctx.Err()
+	}
+}
+
+// FailedRows returns the rows the backend failed to write, valid after
+// WaitForComplete when the write request has ContinueOnError set
+func (a *frameAppender) FailedRows() frames.Frame {
+	return a.failedRows
+}
+
+func (a *frameAppender) Close() {
+}
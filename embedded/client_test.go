@@ -0,0 +1,138 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package embedded
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+)
+
+func newTestClient(t *testing.T) *Client {
+	tmpDir, err := os.MkdirTemp("", "frames-embedded")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &frames.Config{
+		Log: frames.LogConfig{
+			Level: "debug",
+		},
+		Backends: []*frames.BackendConfig{
+			{
+				Name:    "csv",
+				Type:    "csv",
+				RootDir: tmpDir,
+			},
+		},
+	}
+
+	session := &frames.Session{User: "iguazio", Password: "secret"}
+	client, err := NewClientFromConfig(cfg, session, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestClient(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	frame, err := frames.NewFrameFromMap(map[string]interface{}{
+		"x": []int64{1, 2, 3},
+		"y": []string{"a", "b", "c"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appender, err := client.Write(ctx, &frames.WriteRequest{Backend: "csv", Table: "t1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.WaitForComplete(10 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	it, err := client.Read(ctx, &pb.ReadRequest{Backend: "csv", Table: "t1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nRows := 0
+	for it.Next() {
+		nRows += it.At().Len()
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if nRows != frame.Len() {
+		t.Fatalf("# of rows mismatch - %d != %d", nRows, frame.Len())
+	}
+
+	if client.session.Password != "secret" {
+		t.Fatal("client session was modified")
+	}
+
+	if _, err := client.Exec(ctx, &pb.ExecRequest{Backend: "csv", Table: "t1", Command: "ping"}); err != nil {
+		t.Fatalf("can't exec - %s", err)
+	}
+
+	if version, err := client.Version(ctx); err != nil || version == "" {
+		t.Fatalf("bad version - %q (%v)", version, err)
+	}
+
+	if err := client.Delete(ctx, &pb.DeleteRequest{Backend: "csv", Table: "t1"}); err != nil {
+		t.Fatalf("can't delete - %s", err)
+	}
+}
+
+func TestClientCanceled(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	it, err := client.Read(ctx, &pb.ReadRequest{Backend: "csv", Table: "t1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if it.Next() {
+		t.Fatal("got frame with canceled context")
+	}
+
+	if it.Err() != context.Canceled {
+		t.Fatalf("bad error - %v", it.Err())
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package embedded
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+)
+
+// newFactoryClient creates a client for frames.NewClient from an
+// embedded://path/to/config.yaml URL (framesd configuration file)
+func newFactoryClient(url string, session *frames.Session, logger logger.Logger, options ...frames.ClientOption) (frames.ClientV2, error) {
+	path := url[strings.Index(url, "://")+len("://"):]
+	if path == "" {
+		return nil, fmt.Errorf("missing configuration path in %q", url)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read configuration")
+	}
+
+	config := &frames.Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, errors.Wrap(err, "can't decode configuration")
+	}
+
+	return NewClientFromConfig(config, session, logger, options...)
+}
+
+func init() {
+	if err := frames.RegisterClientFactory("embedded", newFactoryClient); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nuclio/logger"
+)
+
+// ClientFactory creates a client from a URL. Transport packages register
+// factories for their URL schemes on init, import them (e.g.
+// _ "github.com/v3io/frames/grpc") to use NewClient with their schemes:
+//
+//	grpc://host:port, grpcs://host:port  - gRPC (grpcs uses TLS)
+//	unix:///path/to/socket               - gRPC over a Unix domain socket
+//	http://host:port, https://host:port  - HTTP
+//	http+unix:///path/to/socket          - HTTP over a Unix domain socket
+//	embedded:///path/to/config.yaml      - in-process API (framesd configuration)
+//
+// Network URLs can list several comma separated servers
+// (e.g. grpc://host1:8081,host2:8081).
+type ClientFactory func(url string, session *Session, logger logger.Logger, options ...ClientOption) (ClientV2, error)
+
+var (
+	clientFactories     map[string]ClientFactory
+	clientFactoriesLock sync.RWMutex
+)
+
+// RegisterClientFactory registers a client factory for a URL scheme
+func RegisterClientFactory(scheme string, factory ClientFactory) error {
+	clientFactoriesLock.Lock()
+	defer clientFactoriesLock.Unlock()
+
+	if clientFactories == nil {
+		clientFactories = make(map[string]ClientFactory)
+	}
+
+	scheme = strings.ToLower(scheme)
+	if _, ok := clientFactories[scheme]; ok {
+		return fmt.Errorf("client scheme %q already registered", scheme)
+	}
+
+	clientFactories[scheme] = factory
+	return nil
+}
+
+// NewClient returns a client by the URL scheme (see ClientFactory). If url is
+// empty, V3IO_URL environment variable is used. If session is nil, it's read
+// with SessionFromEnv.
+func NewClient(url string, session *Session, logger logger.Logger, options ...ClientOption) (ClientV2, error) {
+	if url == "" {
+		url = os.Getenv("V3IO_URL")
+	}
+
+	i := strings.Index(url, "://")
+	if i == -1 {
+		return nil, fmt.Errorf("missing scheme in URL - %q", url)
+	}
+	scheme := strings.ToLower(url[:i])
+
+	clientFactoriesLock.RLock()
+	factory, ok := clientFactories[scheme]
+	clientFactoriesLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown client scheme %q (registered: %s)", scheme, strings.Join(ClientSchemes(), ", "))
+	}
+
+	if session == nil {
+		var err error
+		session, err = SessionFromEnv()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return factory(url, session, logger, options...)
+}
+
+// ClientSchemes returns the registered client URL schemes
+func ClientSchemes() []string {
+	clientFactoriesLock.RLock()
+	defer clientFactoriesLock.RUnlock()
+
+	schemes := make([]string, 0, len(clientFactories))
+	for scheme := range clientFactories {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+
+	return schemes
+}
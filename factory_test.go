@@ -0,0 +1,74 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nuclio/logger"
+)
+
+func TestNewClientFactory(t *testing.T) {
+	var (
+		gotURL     string
+		gotSession *Session
+		gotConfig  *ClientConfig
+	)
+
+	factory := func(url string, session *Session, logger logger.Logger, options ...ClientOption) (ClientV2, error) {
+		gotURL, gotSession = url, session
+		var err error
+		gotConfig, err = NewClientConfig(options...)
+		return nil, err
+	}
+
+	if err := RegisterClientFactory("Test", factory); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RegisterClientFactory("test", factory); err == nil {
+		t.Fatal("no error on duplicate registration")
+	}
+
+	url := "TEST://host1:80,host2:80"
+	if _, err := NewClient(url, nil, nil, WithTimeout(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotURL != url {
+		t.Fatalf("bad URL: %q != %q", gotURL, url)
+	}
+
+	if gotSession == nil {
+		t.Fatal("session not set from environment")
+	}
+
+	if gotConfig.Timeout != time.Second {
+		t.Fatalf("bad timeout - %s", gotConfig.Timeout)
+	}
+
+	for _, url := range []string{"localhost:8080", "nope://localhost"} {
+		if _, err := NewClient(url, nil, nil); err == nil {
+			t.Fatalf("%q: no error", url)
+		}
+	}
+}
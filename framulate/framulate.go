@@ -11,8 +11,9 @@ import (
 	"github.com/nuclio/errors"
 	"github.com/nuclio/logger"
 	"github.com/v3io/frames"
-	"github.com/v3io/frames/grpc"
-	"github.com/v3io/frames/http"
+	// Register client schemes
+	_ "github.com/v3io/frames/grpc"
+	_ "github.com/v3io/frames/http"
 	"github.com/v3io/frames/pb"
 	"github.com/v3io/frames/repeatingtask"
 )
@@ -98,19 +99,20 @@ func (f *Framulate) createFramesClient(config *Config) (frames.Client, error) {
 		Token:     config.AccessKey,
 	}
 
-	if strings.HasPrefix(config.Transport.URL, "http") {
-		f.logger.DebugWith("Creating HTTP client", "url", config.Transport.URL)
-
-		return http.NewClient(config.Transport.URL,
-			&session,
-			f.logger)
+	url := config.Transport.URL
+	if !strings.Contains(url, "://") {
+		// Plain address is gRPC
+		url = "grpc://" + url
 	}
 
-	f.logger.DebugWith("Creating gRPC client", "url", config.Transport.URL)
+	f.logger.DebugWith("Creating frames client", "url", url)
+
+	client, err := frames.NewClient(url, &session, f.logger)
+	if err != nil {
+		return nil, err
+	}
 
-	return grpc.NewClient(config.Transport.URL,
-		&session,
-		f.logger)
+	return frames.NewV1Client(client), nil
 }
 
 func (f *Framulate) createScenario(config *Config) (scenario, error) {
@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"time"
 
@@ -32,35 +33,79 @@ import (
 	"github.com/v3io/frames"
 	"github.com/v3io/frames/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
 )
 
 // Client is frames gRPC client
 type Client struct {
 	client  pb.FramesClient
 	session *frames.Session
+	config  *frames.ClientConfig
 }
 
 var (
-	// Make sure we're implementing frames.Client
-	_ frames.Client = &Client{}
+	// Make sure we're implementing frames.ClientV2
+	_ frames.ClientV2 = &Client{}
 )
 
-// NewClient returns a new gRPC client
-func NewClient(address string, session *frames.Session, logger logger.Logger) (*Client, error) {
+// NewClient returns a new gRPC client. address can be a comma separated list
+// of servers, calls are load balanced between them. Servers on a Unix domain
+// socket are given as unix:///path/to/socket.
+func NewClient(address string, session *frames.Session, logger logger.Logger, options ...frames.ClientOption) (*Client, error) {
+	config, err := frames.NewClientConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+
 	if address == "" {
 		address = os.Getenv("V3IO_URL")
 	}
 
-	if address == "" {
+	addresses := frames.SplitAddresses(address)
+	if len(addresses) == 0 {
 		return nil, fmt.Errorf("empty address")
 	}
 
-	conn, err := grpc.Dial(
-		address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(grpcMsgSize)),
+	msgSize := grpcMsgSize
+	if config.MaxMessageSize > 0 {
+		msgSize = config.MaxMessageSize
+	}
+
+	callOptions := []grpc.CallOption{
+		grpc.MaxCallRecvMsgSize(msgSize),
+		grpc.MaxCallSendMsgSize(msgSize),
+	}
+	if config.Compression != frames.NoCompression {
+		callOptions = append(callOptions, grpc.UseCompressor(config.Compression))
+	}
+
+	creds := insecure.NewCredentials()
+	if config.TLSConfig != nil {
+		creds = credentials.NewTLS(config.TLSConfig)
+	}
+
+	target, dialOptions := dialTarget(addresses, config.MaxConnsPerHost)
+	dialOptions = append(
+		dialOptions,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(callOptions...),
 	)
+
+	if config.DialTimeout > 0 {
+		dialOptions = append(dialOptions, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: config.DialTimeout,
+		}))
+	}
+
+	conn, err := grpc.Dial(target, dialOptions...)
 	if err != nil {
 		return nil, errors.Wrap(err, "can't create gRPC connection")
 	}
@@ -76,28 +121,137 @@ func NewClient(address string, session *frames.Session, logger logger.Logger) (*
 	client := &Client{
 		client:  pb.NewFramesClient(conn),
 		session: session,
+		config:  config,
 	}
 
 	return client, nil
 }
 
-func (c *Client) Read(request *pb.ReadRequest) (frames.FrameIterator, error) {
+// dialTarget returns the dial target for addresses. Several addresses (or
+// several connections per address) are round robin balanced.
+func dialTarget(addresses []string, connsPerHost int) (string, []grpc.DialOption) {
+	if len(addresses) == 1 && connsPerHost <= 1 {
+		return addresses[0], nil
+	}
+
+	if connsPerHost < 1 {
+		connsPerHost = 1
+	}
+
+	var state resolver.State
+	hasSocket := false
+	for _, address := range addresses {
+		// TLS certificates are verified against the address host, not the
+		// "frames" target
+		serverName := ""
+		if _, ok := frames.UnixSocketPath(address); ok {
+			hasSocket = true
+		} else if host, _, err := net.SplitHostPort(address); err == nil {
+			serverName = host
+		}
+		for i := 0; i < connsPerHost; i++ {
+			// Addresses with different attributes get separate connections
+			state.Addresses = append(state.Addresses, resolver.Address{
+				Addr:       address,
+				ServerName: serverName,
+				Attributes: attributes.New("conn", i),
+			})
+		}
+	}
+
+	builder := manual.NewBuilderWithScheme("frames")
+	builder.InitialState(state)
+	dialOptions := []grpc.DialOption{
+		grpc.WithResolvers(builder),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"round_robin": {}}]}`),
+	}
+
+	if hasSocket {
+		// The default dialer uses TCP for resolved addresses
+		dialOptions = append(dialOptions, grpc.WithContextDialer(dialAddress))
+	}
+
+	return builder.Scheme() + ":///frames", dialOptions
+}
+
+// dialAddress dials host:port or unix:///path/to/socket
+func dialAddress(ctx context.Context, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	if path, ok := frames.UnixSocketPath(address); ok {
+		return dialer.DialContext(ctx, "unix", path)
+	}
+
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// retryable returns true if a call failing with err can be retried
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+
+	return false
+}
+
+// Read reads data from server
+func (c *Client) Read(ctx context.Context, request *pb.ReadRequest) (frames.FrameIterator, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	return c.readStream(ctx, func() (frameStream, error) {
+		return c.client.Read(ctx, request)
+	})
+}
+
+// History returns the request history log
+func (c *Client) History(ctx context.Context, request *pb.HistoryRequest) (frames.FrameIterator, error) {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	stream, err := c.client.Read(context.Background(), request)
+	return c.readStream(ctx, func() (frameStream, error) {
+		return c.client.History(ctx, request)
+	})
+}
+
+// readStream opens a stream of frames (with retries)
+func (c *Client) readStream(ctx context.Context, open func() (frameStream, error)) (frames.FrameIterator, error) {
+	var it *frameIterator
+	err := c.config.Retry.Retry(ctx, retryable, func() error {
+		stream, err := open()
+		if err != nil {
+			return err
+		}
+
+		// Call errors (e.g. unavailable server) are returned on first receive,
+		// other errors are reported by the iterator
+		msg, err := stream.Recv()
+		if err != nil && err != io.EOF && retryable(err) {
+			return err
+		}
+
+		it = &frameIterator{
+			stream: stream,
+			first:  msg,
+			done:   err == io.EOF,
+		}
+		if err != nil && err != io.EOF {
+			it.err = err
+		}
+		return nil
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	it := &frameIterator{
-		stream: stream,
-	}
 	return it, nil
 }
 
-func (c *Client) Write(request *frames.WriteRequest) (frames.FrameAppender, error) {
+// Write writes data to server
+func (c *Client) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
 	if request.Session == nil {
 		request.Session = c.session
 	}
@@ -112,21 +266,26 @@ func (c *Client) Write(request *frames.WriteRequest) (frames.FrameAppender, erro
 		frame = proto.Proto()
 	}
 
-	stream, err := c.client.Write(context.Background())
+	stream, err := c.client.Write(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	ireq := &pb.InitialWriteRequest{
-		Session:       request.Session,
-		Backend:       request.Backend,
-		Table:         request.Table,
-		InitialData:   frame,
-		Expression:    request.Expression,
-		More:          request.HaveMore,
-		SaveMode:      request.SaveMode.String(),
-		PartitionKeys: request.PartitionKeys,
-		Condition:     request.Condition,
+		Session:         request.Session,
+		Backend:         request.Backend,
+		Table:           request.Table,
+		InitialData:     frame,
+		Expression:      request.Expression,
+		More:            request.HaveMore,
+		SaveMode:        request.SaveMode.String(),
+		PartitionKeys:   request.PartitionKeys,
+		Condition:       request.Condition,
+		ContinueOnError: request.ContinueOnError,
+		TimeZone:        request.TimeZone,
+		TimePrecision:   request.TimePrecision,
+		Ttl:             request.TTL,
+		SchemaMode:      request.SchemaMode,
 	}
 
 	req := &pb.WriteRequest{
@@ -149,32 +308,57 @@ func (c *Client) Write(request *frames.WriteRequest) (frames.FrameAppender, erro
 }
 
 // Create creates a table
-func (c *Client) Create(request *pb.CreateRequest) error {
+func (c *Client) Create(ctx context.Context, request *pb.CreateRequest) error {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	_, err := c.client.Create(context.Background(), request)
-	return err
+	ctx, cancel := c.config.CallContext(ctx)
+	defer cancel()
+
+	call := func() error {
+		_, err := c.client.Create(ctx, request)
+		return err
+	}
+
+	if !frames.IdempotentCreate(request) {
+		return call()
+	}
+
+	return c.config.Retry.Retry(ctx, retryable, call)
 }
 
 // Delete deletes data or table
-func (c *Client) Delete(request *pb.DeleteRequest) error {
+func (c *Client) Delete(ctx context.Context, request *pb.DeleteRequest) error {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	_, err := c.client.Delete(context.Background(), request)
-	return err
+	ctx, cancel := c.config.CallContext(ctx)
+	defer cancel()
+
+	call := func() error {
+		_, err := c.client.Delete(ctx, request)
+		return err
+	}
+
+	if !frames.IdempotentDelete(request) {
+		return call()
+	}
+
+	return c.config.Retry.Retry(ctx, retryable, call)
 }
 
 // Exec executes a command on the backend
-func (c *Client) Exec(request *pb.ExecRequest) (frames.Frame, error) {
+func (c *Client) Exec(ctx context.Context, request *pb.ExecRequest) (frames.Frame, error) {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	msg, err := c.client.Exec(context.Background(), request)
+	ctx, cancel := c.config.CallContext(ctx)
+	defer cancel()
+
+	msg, err := c.client.Exec(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -187,14 +371,68 @@ func (c *Client) Exec(request *pb.ExecRequest) (frames.Frame, error) {
 	return frame, nil
 }
 
+// Version returns the server version
+func (c *Client) Version(ctx context.Context) (string, error) {
+	ctx, cancel := c.config.CallContext(ctx)
+	defer cancel()
+
+	var version string
+	err := c.config.Retry.Retry(ctx, retryable, func() error {
+		resp, err := c.client.Version(ctx, &pb.VersionRequest{})
+		if err != nil {
+			return err
+		}
+
+		version = resp.Version
+		return nil
+	})
+
+	return version, err
+}
+
+// BatchWrite writes frames to several tables, either all of them are written
+// or none is. Batch writes are not retried.
+func (c *Client) BatchWrite(ctx context.Context, request *frames.BatchWriteRequest) (*frames.BatchWriteResult, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	msg, err := request.Proto()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.config.CallContext(ctx)
+	defer cancel()
+
+	resp, err := c.client.BatchWrite(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return frames.BatchWriteResultFromProto(resp)
+}
+
+// frameStream is a stream of frames (pb.Frames_ReadClient or pb.Frames_HistoryClient)
+type frameStream interface {
+	Recv() (*pb.Frame, error)
+}
+
 type frameIterator struct {
-	stream pb.Frames_ReadClient
+	stream frameStream
+	first  *pb.Frame // received when the read started
 	frame  frames.Frame
 	err    error
 	done   bool
 }
 
 func (it *frameIterator) Next() bool {
+	if it.first != nil {
+		it.frame = frames.NewFrameFromProto(it.first)
+		it.first = nil
+		return true
+	}
+
 	if it.done || it.err != nil {
 		return false
 	}
@@ -221,8 +459,9 @@ func (it *frameIterator) At() frames.Frame {
 }
 
 type frameAppender struct {
-	stream pb.Frames_WriteClient
-	closed bool
+	stream     pb.Frames_WriteClient
+	closed     bool
+	failedRows frames.Frame
 }
 
 func (fa *frameAppender) Add(frame frames.Frame) error {
@@ -257,8 +496,21 @@ func (fa *frameAppender) WaitForComplete(timeout time.Duration) error {
 	}
 
 	// TODO: timeout
-	_, err := fa.stream.CloseAndRecv()
-	return err
+	resp, err := fa.stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	if resp.FailedRows != nil {
+		fa.failedRows = frames.NewFrameFromProto(resp.FailedRows)
+	}
+	return nil
+}
+
+// FailedRows returns the rows the server failed to write, valid after
+// WaitForComplete when the write request has ContinueOnError set
+func (fa *frameAppender) FailedRows() frames.Frame {
+	return fa.failedRows
 }
 
 func (fa *frameAppender) Close() {
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package grpc
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/v3io/frames"
+	"google.golang.org/grpc/encoding"
+	// Register gzip compressor
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+// gRPC uses registered compressors both for client calls (see
+// grpc.UseCompressor) and for decompressing incoming messages on the server,
+// which replies with the compressor used by the client.
+
+func init() {
+	encoding.RegisterCompressor(newZstdCompressor())
+}
+
+// zstdCompressor implements encoding.Compressor. gRPC messages are complete
+// in memory, so we use the stateless EncodeAll/DecodeAll which are safe for
+// concurrent use. gRPC checks the message size only after Decompress, so the
+// decoder itself is limited to the maximal message size.
+type zstdCompressor struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func newZstdCompressor() *zstdCompressor {
+	// Errors are returned only on bad options
+	encoder, _ := zstd.NewWriter(nil)
+	decoder, _ := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(grpcMsgSize))
+
+	return &zstdCompressor{
+		encoder: encoder,
+		decoder: decoder,
+	}
+}
+
+func (c *zstdCompressor) Name() string {
+	return frames.ZstdCompression
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &zstdMessageWriter{encoder: c.encoder, w: w}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.decoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(out), nil
+}
+
+// zstdMessageWriter buffers a message and compresses it on Close
+type zstdMessageWriter struct {
+	encoder *zstd.Encoder
+	w       io.Writer
+	buf     bytes.Buffer
+}
+
+func (mw *zstdMessageWriter) Write(p []byte) (int, error) {
+	return mw.buf.Write(p)
+}
+
+func (mw *zstdMessageWriter) Close() error {
+	_, err := mw.w.Write(mw.encoder.EncodeAll(mw.buf.Bytes(), nil))
+	return err
+}
@@ -21,9 +21,19 @@ such restriction.
 package grpc_test
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"io"
+	"math/big"
 	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -73,6 +83,7 @@ func TestEnd2End(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	ctx := context.Background()
 
 	frame, err := makeFrame()
 	if err != nil {
@@ -85,7 +96,7 @@ func TestEnd2End(t *testing.T) {
 		Table:   tableName,
 	}
 
-	appender, err := client.Write(writeReq)
+	appender, err := client.Write(ctx, writeReq)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -104,7 +115,7 @@ func TestEnd2End(t *testing.T) {
 		MessageLimit: 100,
 	}
 
-	it, err := client.Read(readReq)
+	it, err := client.Read(ctx, readReq)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -134,9 +145,22 @@ func TestEnd2End(t *testing.T) {
 		Command: "ping",
 	}
 
-	if _, err := client.Exec(execReq); err != nil {
+	if _, err := client.Exec(ctx, execReq); err != nil {
 		t.Fatalf("can't exec - %s", err)
 	}
+
+	if _, err := client.Version(ctx); err != nil {
+		t.Fatalf("can't get version - %s", err)
+	}
+
+	testFailover(t, url)
+	testTLS(t, url)
+	testTLSFactory(t, url)
+	testResumableWrite(t, url, backendName, frame)
+
+	for _, compression := range []string{frames.GzipCompression, frames.ZstdCompression} {
+		testCompression(t, url, backendName, compression, frame)
+	}
 }
 
 func makeFrame() (frames.Frame, error) {
@@ -175,3 +199,300 @@ func freePort() (int, error) {
 	l.Close()
 	return l.Addr().(*net.TCPAddr).Port, nil
 }
+
+func testCompression(t *testing.T, url string, backend string, compression string, frame frames.Frame) {
+	client, err := grpc.NewClient(url, nil, nil, frames.WithCompression(compression))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tableName := "e2e-" + compression
+	appender, err := client.Write(ctx, &frames.WriteRequest{Backend: backend, Table: tableName})
+	if err != nil {
+		t.Fatalf("%s: can't write - %s", compression, err)
+	}
+
+	if err := appender.Add(frame); err != nil {
+		t.Fatalf("%s: can't add frame - %s", compression, err)
+	}
+
+	if err := appender.WaitForComplete(10 * time.Second); err != nil {
+		t.Fatalf("%s: can't complete write - %s", compression, err)
+	}
+
+	it, err := client.Read(ctx, &pb.ReadRequest{Backend: backend, Table: tableName, MessageLimit: 100})
+	if err != nil {
+		t.Fatalf("%s: can't read - %s", compression, err)
+	}
+
+	nRows := 0
+	for it.Next() {
+		nRows += it.At().Len()
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("%s: read error - %s", compression, err)
+	}
+
+	if nRows != frame.Len() {
+		t.Fatalf("%s: # of rows mismatch - %d != %d", compression, nRows, frame.Len())
+	}
+}
+
+// testResumableWrite checks that frames are acknowledged over WriteStream
+func testResumableWrite(t *testing.T, url string, backend string, frame frames.Frame) {
+	client, err := grpc.NewClient(url, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tableName := "e2e-resumable"
+	request := &frames.WriteRequest{
+		Backend:       backend,
+		Table:         tableName,
+		ImmidiateData: frame,
+	}
+
+	appender, err := client.ResumableWrite(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.WaitForComplete(10 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	nFrames, nRows := appender.Acknowledged()
+	if nFrames != 2 || nRows != int64(2*frame.Len()) {
+		t.Fatalf("bad acknowledgements: %d frames, %d rows", nFrames, nRows)
+	}
+
+	it, err := client.Read(ctx, &pb.ReadRequest{Backend: backend, Table: tableName, MessageLimit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readRows := 0
+	for it.Next() {
+		readRows += it.At().Len()
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if readRows != 2*frame.Len() {
+		t.Fatalf("# of rows mismatch - %d != %d", readRows, 2*frame.Len())
+	}
+}
+
+// localhostCertificate returns a self signed certificate of localhost and a
+// pool with it
+func localhostCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+// tlsProxy accepts TLS connections with cert and forwards them to address,
+// it returns the proxy address
+func tlsProxy(t *testing.T, address string, cert tls.Certificate) string {
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2"},
+	}
+	lis, err := tls.Listen("tcp", "localhost:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				server, err := net.Dial("tcp", address)
+				if err != nil {
+					return
+				}
+				defer server.Close()
+
+				go io.Copy(server, conn) // nolint: errcheck
+				io.Copy(conn, server)    // nolint: errcheck
+			}()
+		}
+	}()
+
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "localhost:" + port
+}
+
+// testTLS checks TLS connections to several servers, certificates are
+// verified against the host of every server
+func testTLS(t *testing.T, url string) {
+	cert, pool := localhostCertificate(t)
+	urls := fmt.Sprintf("%s,%s", tlsProxy(t, url, cert), tlsProxy(t, url, cert))
+
+	client, err := grpc.NewClient(urls, nil, nil, frames.WithTLSConfig(&tls.Config{RootCAs: pool}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// Calls are round robin, check both servers
+	for i := 0; i < 2; i++ {
+		if _, err := client.Version(ctx); err != nil {
+			t.Fatalf("can't get version over TLS - %s", err)
+		}
+	}
+}
+
+// testTLSFactory checks a client created from a grpcs:// URL of several
+// servers
+func testTLSFactory(t *testing.T, url string) {
+	cert, pool := localhostCertificate(t)
+	urls := fmt.Sprintf("grpcs://%s,%s", tlsProxy(t, url, cert), tlsProxy(t, url, cert))
+
+	client, err := frames.NewClient(urls, &frames.Session{}, nil, frames.WithTLSConfig(&tls.Config{RootCAs: pool}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Version(ctx); err != nil {
+			t.Fatalf("can't get version from %s - %s", urls, err)
+		}
+	}
+}
+
+// testFailover checks that idempotent calls are retried on another server
+func testFailover(t *testing.T, url string) {
+	deadPort, err := freePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Calls are round robin, the first call goes to the second (dead) server
+	urls := fmt.Sprintf("%s,localhost:%d", url, deadPort)
+	policy := frames.RetryPolicy{MaxAttempts: 2, Multiplier: 1}
+	client, err := grpc.NewClient(urls, nil, nil, frames.WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.Version(ctx); err != nil {
+		t.Fatalf("no failover - %s", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Version(ctx); err == nil {
+		t.Fatal("no error on canceled context")
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	tmpDir := t.TempDir()
+	backendName := "e2e-backend"
+	cfg := &frames.Config{
+		Log: frames.LogConfig{
+			Level: "debug",
+		},
+		Backends: []*frames.BackendConfig{
+			{
+				Name:    backendName,
+				Type:    "csv",
+				RootDir: tmpDir,
+			},
+		},
+		UnixSocketMode: "0600",
+	}
+
+	socketPath := filepath.Join(tmpDir, "framesd.sock")
+	srv, err := grpc.NewServer(cfg, "unix://"+socketPath, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Fatalf("bad socket mode: %o != %o", mode, 0600)
+	}
+
+	frame, err := makeFrame()
+	if err != nil {
+		t.Fatalf("can't create frame - %s", err)
+	}
+
+	url := "unix://" + socketPath
+	client, err := frames.NewClient(url, &frames.Session{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCompression(t, url, backendName, frames.ZstdCompression, frame)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.Version(ctx); err != nil {
+		t.Fatalf("can't get version - %s", err)
+	}
+
+	// Load balanced connections
+	client, err = frames.NewClient(url, &frames.Session{}, nil, frames.WithMaxConnsPerHost(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Version(ctx); err != nil {
+		t.Fatalf("can't get version with several connections - %s", err)
+	}
+}
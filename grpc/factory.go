@@ -0,0 +1,60 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package grpc
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/nuclio/logger"
+	"github.com/v3io/frames"
+)
+
+// newFactoryClient creates a client for frames.NewClient
+func newFactoryClient(url string, session *frames.Session, logger logger.Logger, options ...frames.ClientOption) (frames.ClientV2, error) {
+	i := strings.Index(url, "://")
+	scheme, address := strings.ToLower(url[:i]), url[i+len("://"):]
+
+	switch scheme {
+	case "grpcs":
+		config, err := frames.NewClientConfig(options...)
+		if err != nil {
+			return nil, err
+		}
+
+		if config.TLSConfig == nil {
+			options = append(options, frames.WithTLSConfig(&tls.Config{}))
+		}
+	case "unix":
+		// gRPC dials unix:///path targets
+		address = url
+	}
+
+	return NewClient(address, session, logger, options...)
+}
+
+func init() {
+	for _, scheme := range []string{"grpc", "grpcs", "unix"} {
+		if err := frames.RegisterClientFactory(scheme, newFactoryClient); err != nil {
+			panic(err)
+		}
+	}
+}
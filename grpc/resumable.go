@@ -0,0 +1,396 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+)
+
+// ResumableAppender writes frames over the WriteStream RPC. Frames are kept
+// until the server acknowledges that the backend applied them. If the stream
+// fails with a retryable error, a new stream is opened (according to the
+// client retry policy) and the frames that weren't acknowledged are sent
+// again, so they might be written more than once.
+//
+// Backends that can't flush in the middle of a write acknowledge all frames
+// when the write completes, in this case all frames are kept until then.
+//
+// Once the server acknowledged a frame, resumed streams write items with the
+// updateItem save mode when the write has a table save mode
+// (errorIfTableExists or overwriteTable), since the table was already created.
+// Streams resumed before the first acknowledgement keep the write save mode.
+// The createNewItemsOnly save mode can't be resumed.
+type ResumableAppender struct {
+	client  *Client
+	ctx     context.Context
+	request *pb.InitialWriteRequest
+
+	stream *writeStream
+	closed bool
+
+	lock    sync.Mutex
+	pending []*pb.Frame // frames waiting for acknowledgement
+	nFrames int64       // acknowledged frames
+	nRows   int64       // acknowledged rows
+
+	rowErrors []frames.RowError // failed rows of acknowledged frames
+}
+
+// writeStream is a single WriteStream call
+type writeStream struct {
+	stream pb.Frames_WriteStreamClient
+	cancel context.CancelFunc
+	acked  int64         // acknowledged frames on this stream
+	done   chan struct{} // closed when the server ends the stream
+	err    error         // valid after done is closed, nil if the write completed
+}
+
+// ResumableWrite starts a resumable write (see ResumableAppender). The server
+// must support the WriteStream RPC.
+func (c *Client) ResumableWrite(ctx context.Context, request *frames.WriteRequest) (*ResumableAppender, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	if request.SaveMode == frames.CreateNewItemsOnly {
+		return nil, fmt.Errorf("save mode %s can't be used in a resumable write, resent items would fail", request.SaveMode)
+	}
+
+	appender := &ResumableAppender{
+		client: c,
+		ctx:    ctx,
+		request: &pb.InitialWriteRequest{
+			Session:         request.Session,
+			Backend:         request.Backend,
+			Table:           request.Table,
+			Expression:      request.Expression,
+			More:            request.HaveMore,
+			SaveMode:        request.SaveMode.String(),
+			PartitionKeys:   request.PartitionKeys,
+			Condition:       request.Condition,
+			ContinueOnError: request.ContinueOnError,
+			TimeZone:        request.TimeZone,
+			TimePrecision:   request.TimePrecision,
+			Ttl:             request.TTL,
+			SchemaMode:      request.SchemaMode,
+		},
+	}
+
+	// Immediate data is sent as the first frame so it's resent like the others
+	if request.ImmidiateData != nil {
+		frame, err := frameProto(request.ImmidiateData)
+		if err != nil {
+			return nil, err
+		}
+		appender.pending = append(appender.pending, frame)
+	}
+
+	if err := appender.open(); err != nil {
+		if err = appender.resume(err); err != nil {
+			return nil, err
+		}
+	}
+
+	return appender, nil
+}
+
+func frameProto(frame frames.Frame) (*pb.Frame, error) {
+	pbf, ok := frame.(pb.Framed)
+	if !ok {
+		return nil, errors.New("unknown frame type")
+	}
+
+	return pbf.Proto(), nil
+}
+
+// open opens a new stream and sends the pending frames
+func (a *ResumableAppender) open() error {
+	ctx, cancel := context.WithCancel(a.ctx)
+	stream, err := a.client.client.WriteStream(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	ws := &writeStream{
+		stream: stream,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	a.lock.Lock()
+	a.stream = ws
+	request := a.request
+	pending := append([]*pb.Frame(nil), a.pending...)
+	a.lock.Unlock()
+
+	go a.receive(ws)
+
+	req := &pb.WriteRequest{
+		Type: &pb.WriteRequest_Request{
+			Request: request,
+		},
+	}
+
+	if err := stream.Send(req); err != nil {
+		return ws.sendError(err)
+	}
+
+	for _, frame := range pending {
+		if err := ws.send(frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resumedRequest returns the request of streams that resume a write, table
+// save modes become updateItem so a resumed stream doesn't fail on the table
+// or delete the frames already written
+func resumedRequest(request *pb.InitialWriteRequest) *pb.InitialWriteRequest {
+	switch request.SaveMode {
+	case "", frames.ErrorIfTableExists.String(), frames.OverwriteTable.String():
+		resumed := *request
+		resumed.SaveMode = frames.UpdateItem.String()
+		return &resumed
+	}
+
+	return request
+}
+
+// receive handles the acknowledgements sent by the server
+func (a *ResumableAppender) receive(ws *writeStream) {
+	defer close(ws.done)
+
+	for {
+		ack, err := ws.stream.Recv()
+		if err == io.EOF {
+			return
+		}
+
+		if err != nil {
+			ws.err = err
+			return
+		}
+
+		a.lock.Lock()
+		if ack.Frame != ws.acked || len(a.pending) == 0 {
+			a.lock.Unlock()
+			ws.err = fmt.Errorf("unexpected acknowledgement of frame %d (expected %d)", ack.Frame, ws.acked)
+			ws.cancel()
+			return
+		}
+
+		if ack.FailedRows != nil {
+			rowErrors, err := frames.RowErrorsFromFrame(frames.NewFrameFromProto(ack.FailedRows))
+			if err != nil {
+				a.lock.Unlock()
+				ws.err = errors.Wrap(err, "bad failed rows in acknowledgement")
+				ws.cancel()
+				return
+			}
+			a.rowErrors = append(a.rowErrors, rowErrors...)
+		}
+
+		a.pending[0] = nil
+		a.pending = a.pending[1:]
+		ws.acked++
+		a.nFrames++
+		a.nRows += ack.Rows
+		if a.nFrames == 1 {
+			a.request = resumedRequest(a.request)
+		}
+		a.lock.Unlock()
+	}
+}
+
+func (ws *writeStream) send(frame *pb.Frame) error {
+	msg := &pb.WriteRequest{
+		Type: &pb.WriteRequest_Frame{
+			Frame: frame,
+		},
+	}
+
+	if err := ws.stream.Send(msg); err != nil {
+		return ws.sendError(err)
+	}
+
+	return nil
+}
+
+// sendError returns the reason a send failed, gRPC returns io.EOF from Send
+// and the status from Recv
+func (ws *writeStream) sendError(err error) error {
+	if err != io.EOF {
+		return err
+	}
+
+	<-ws.done
+	if ws.err != nil {
+		return ws.err
+	}
+
+	return fmt.Errorf("server ended the write stream")
+}
+
+// resume opens a new stream after the current one failed with err
+func (a *ResumableAppender) resume(err error) error {
+	policy := a.client.config.Retry
+	for attempt := 1; ; attempt++ {
+		if attempt >= policy.MaxAttempts || !retryable(err) {
+			a.closed = true
+			return err
+		}
+
+		if a.stream != nil {
+			a.stream.cancel()
+			<-a.stream.done
+		}
+
+		timer := time.NewTimer(policy.Backoff(attempt))
+		select {
+		case <-a.ctx.Done():
+			timer.Stop()
+			a.closed = true
+			return err
+		case <-timer.C:
+		}
+
+		if err = a.open(); err == nil {
+			return nil
+		}
+	}
+}
+
+// Add adds a frame to the write
+func (a *ResumableAppender) Add(frame frames.Frame) error {
+	if a.closed {
+		return fmt.Errorf("stream closed")
+	}
+
+	msg, err := frameProto(frame)
+	if err != nil {
+		return err
+	}
+
+	a.lock.Lock()
+	a.pending = append(a.pending, msg)
+	a.lock.Unlock()
+
+	ws := a.stream
+	select {
+	case <-ws.done:
+		// The stream failed, resuming will send the frame
+		if ws.err != nil {
+			return a.resume(ws.err)
+		}
+		err = fmt.Errorf("server ended the write stream")
+	default:
+		err = ws.send(msg)
+	}
+
+	if err != nil {
+		return a.resume(err)
+	}
+
+	return nil
+}
+
+// WaitForComplete ends the write and waits for the server to acknowledge all
+// frames
+func (a *ResumableAppender) WaitForComplete(timeout time.Duration) error {
+	if a.closed {
+		return fmt.Errorf("stream closed")
+	}
+
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	for {
+		ws := a.stream
+		if err := ws.stream.CloseSend(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ws.done:
+		case <-timeoutCh:
+			return fmt.Errorf("timeout after %s", timeout)
+		}
+
+		if ws.err != nil {
+			if err := a.resume(ws.err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		a.closed = true
+		ws.cancel()
+		a.lock.Lock()
+		defer a.lock.Unlock()
+		if n := len(a.pending); n > 0 {
+			return fmt.Errorf("write completed with %d frames not acknowledged", n)
+		}
+
+		return nil
+	}
+}
+
+// Acknowledged returns the number of frames and rows the server acknowledged
+func (a *ResumableAppender) Acknowledged() (int64, int64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.nFrames, a.nRows
+}
+
+// FailedRows returns the rows the server failed to write in acknowledged
+// frames when the write request has ContinueOnError set
+func (a *ResumableAppender) FailedRows() frames.Frame {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// Errors only on bad columns, which we create
+	frame, _ := frames.NewFailedRowsFrame(a.rowErrors)
+	return frame
+}
+
+// Close cancels the write if it's not complete
+func (a *ResumableAppender) Close() {
+	a.closed = true
+	if a.stream != nil {
+		a.stream.cancel()
+	}
+}
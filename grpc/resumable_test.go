@@ -0,0 +1,288 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package grpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeWriteStream acknowledges every frame, and fails after failAfter frames
+// (if failAfter >= 0) with failErr
+type fakeWriteStream struct {
+	grpc.ClientStream
+
+	ctx       context.Context
+	failAfter int
+	failErr   error
+	acks      chan *pb.WriteAck
+	errCh     chan error
+
+	lock    sync.Mutex
+	request *pb.InitialWriteRequest
+	frames  []int64 // first value of every received frame
+	failed  bool
+}
+
+func newFakeWriteStream(ctx context.Context, failAfter int, failErr error) *fakeWriteStream {
+	return &fakeWriteStream{
+		ctx:       ctx,
+		failAfter: failAfter,
+		failErr:   failErr,
+		acks:      make(chan *pb.WriteAck, 100),
+		errCh:     make(chan error, 1),
+	}
+}
+
+func (s *fakeWriteStream) Send(msg *pb.WriteRequest) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.failed {
+		return io.EOF
+	}
+
+	frame := msg.GetFrame()
+	if frame == nil {
+		s.request = msg.GetRequest()
+		return nil
+	}
+
+	n := len(s.frames)
+	if s.failAfter >= 0 && n >= s.failAfter {
+		s.failed = true
+		s.errCh <- s.failErr
+		return io.EOF
+	}
+
+	s.frames = append(s.frames, frame.Columns[0].Ints[0])
+	s.acks <- &pb.WriteAck{Frame: int64(n), Rows: int64(len(frame.Columns[0].Ints))}
+	return nil
+}
+
+func (s *fakeWriteStream) CloseSend() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.failed {
+		s.errCh <- io.EOF
+	}
+	return nil
+}
+
+func (s *fakeWriteStream) Recv() (*pb.WriteAck, error) {
+	select {
+	case ack := <-s.acks:
+		return ack, nil
+	default:
+	}
+
+	select {
+	case ack := <-s.acks:
+		return ack, nil
+	case err := <-s.errCh:
+		return nil, err
+	case <-s.ctx.Done():
+		return nil, status.Error(codes.Canceled, s.ctx.Err().Error())
+	}
+}
+
+func (s *fakeWriteStream) received() []int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.frames
+}
+
+type fakeWriteClient struct {
+	pb.FramesClient
+
+	newStream func(ctx context.Context) *fakeWriteStream
+	streams   []*fakeWriteStream
+}
+
+func (c *fakeWriteClient) WriteStream(ctx context.Context, opts ...grpc.CallOption) (pb.Frames_WriteStreamClient, error) {
+	stream := c.newStream(ctx)
+	c.streams = append(c.streams, stream)
+	return stream, nil
+}
+
+func newFakeClient(newStream func(int, context.Context) *fakeWriteStream) *fakeWriteClient {
+	client := &fakeWriteClient{}
+	client.newStream = func(ctx context.Context) *fakeWriteStream {
+		return newStream(len(client.streams), ctx)
+	}
+	return client
+}
+
+func writeFrames(t *testing.T, client *fakeWriteClient, n int) (*ResumableAppender, error) {
+	return writeFramesWithMode(t, client, n, frames.ErrorIfTableExists)
+}
+
+func writeFramesWithMode(t *testing.T, client *fakeWriteClient, n int, saveMode frames.SaveMode) (*ResumableAppender, error) {
+	policy := frames.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}
+	config, err := frames.NewClientConfig(frames.WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Client{
+		client:  client,
+		session: &frames.Session{},
+		config:  config,
+	}
+
+	appender, err := c.ResumableWrite(context.Background(), &frames.WriteRequest{Backend: "kv", Table: "t", SaveMode: saveMode})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < n; i++ {
+		col, err := frames.NewSliceColumn("x", []int64{int64(i), int64(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		frame, err := frames.NewFrame([]frames.Column{col}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.Add(frame); err != nil {
+			return appender, err
+		}
+	}
+
+	return appender, appender.WaitForComplete(time.Second)
+}
+
+func TestResumableWrite(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection reset")
+	client := newFakeClient(func(i int, ctx context.Context) *fakeWriteStream {
+		if i == 0 {
+			return newFakeWriteStream(ctx, 2, unavailable)
+		}
+		return newFakeWriteStream(ctx, -1, nil)
+	})
+
+	appender, err := writeFrames(t, client, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if nFrames, nRows := appender.Acknowledged(); nFrames != 5 || nRows != 10 {
+		t.Fatalf("bad acknowledgements: %d frames, %d rows", nFrames, nRows)
+	}
+
+	if len(client.streams) != 2 {
+		t.Fatalf("bad number of streams: %d", len(client.streams))
+	}
+
+	var received []int64
+	for _, stream := range client.streams {
+		received = append(received, stream.received()...)
+	}
+
+	for i, value := range received {
+		if value != int64(i) {
+			t.Fatalf("frames not written in order: %v", received)
+		}
+	}
+
+	if len(received) != 5 {
+		t.Fatalf("bad number of frames: %v", received)
+	}
+}
+
+func TestResumableWriteFailure(t *testing.T) {
+	failed := status.Error(codes.Unknown, "bad frame")
+	client := newFakeClient(func(i int, ctx context.Context) *fakeWriteStream {
+		return newFakeWriteStream(ctx, 1, failed)
+	})
+
+	appender, err := writeFrames(t, client, 3)
+	if err == nil {
+		t.Fatal("no error")
+	}
+
+	if len(client.streams) != 1 {
+		t.Fatalf("write with non retryable error resumed (%d streams)", len(client.streams))
+	}
+
+	if nFrames, _ := appender.Acknowledged(); nFrames != 1 {
+		t.Fatalf("bad number of acknowledged frames: %d", nFrames)
+	}
+}
+
+func TestResumableWriteSaveMode(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection reset")
+	cases := []struct {
+		saveMode  frames.SaveMode
+		failAfter int
+		resumed   frames.SaveMode
+	}{
+		{frames.ErrorIfTableExists, 1, frames.UpdateItem},
+		{frames.OverwriteTable, 1, frames.UpdateItem},
+		{frames.UpdateItem, 1, frames.UpdateItem},
+		{frames.OverwriteItem, 1, frames.OverwriteItem},
+		// No frame was acknowledged, the table might not have been created
+		{frames.ErrorIfTableExists, 0, frames.ErrorIfTableExists},
+		{frames.OverwriteTable, 0, frames.OverwriteTable},
+	}
+
+	for _, c := range cases {
+		client := newFakeClient(func(i int, ctx context.Context) *fakeWriteStream {
+			if i == 0 {
+				return newFakeWriteStream(ctx, c.failAfter, unavailable)
+			}
+			return newFakeWriteStream(ctx, -1, nil)
+		})
+
+		if _, err := writeFramesWithMode(t, client, 3, c.saveMode); err != nil {
+			t.Fatalf("%s: %v", c.saveMode, err)
+		}
+
+		if len(client.streams) != 2 {
+			t.Fatalf("%s: bad number of streams: %d", c.saveMode, len(client.streams))
+		}
+		if mode := client.streams[0].request.SaveMode; mode != c.saveMode.String() {
+			t.Fatalf("%s: bad save mode of first stream: %s", c.saveMode, mode)
+		}
+		if mode := client.streams[1].request.SaveMode; mode != c.resumed.String() {
+			t.Fatalf("%s: bad save mode of resumed stream: %s", c.saveMode, mode)
+		}
+	}
+
+	client := newFakeClient(func(i int, ctx context.Context) *fakeWriteStream {
+		return newFakeWriteStream(ctx, -1, nil)
+	})
+	if _, err := writeFramesWithMode(t, client, 1, frames.CreateNewItemsOnly); err == nil {
+		t.Fatal("createNewItemsOnly resumable write didn't fail")
+	}
+}
@@ -23,8 +23,6 @@ package grpc
 import (
 	"context"
 	"fmt"
-	"io"
-	"net"
 
 	"github.com/nuclio/logger"
 	"github.com/pkg/errors"
@@ -52,8 +50,9 @@ type Server struct {
 	version string
 }
 
-// NewServer returns a new gRPC server
-func NewServer(config *frames.Config, addr string, logger logger.Logger, historyServer *utils.HistoryServer, version string) (*Server, error) {
+// NewServer returns a new gRPC server, options are passed to the API (e.g.
+// api.WithV3ioContext)
+func NewServer(config *frames.Config, addr string, logger logger.Logger, historyServer *utils.HistoryServer, version string, options ...api.Option) (*Server, error) {
 	if err := config.Validate(); err != nil {
 		return nil, errors.Wrap(err, "bad configuration")
 	}
@@ -70,7 +69,7 @@ func NewServer(config *frames.Config, addr string, logger logger.Logger, history
 		}
 	}
 
-	api, err := api.New(logger, config, historyServer)
+	api, err := api.New(logger, config, historyServer, options...)
 	if err != nil {
 		return nil, errors.Wrap(err, "can't create API")
 	}
@@ -97,9 +96,15 @@ func NewServer(config *frames.Config, addr string, logger logger.Logger, history
 	return server, nil
 }
 
-// Start starts the server
+// Start starts the server, the address is host:port or unix:///path/to/socket
 func (s *Server) Start() error {
-	lis, err := net.Listen("tcp", s.address)
+	mode, err := s.config.SocketMode()
+	if err != nil {
+		s.SetError(err)
+		return err
+	}
+
+	lis, err := frames.Listen(s.address, mode)
 	if err != nil {
 		s.SetError(err)
 		return err
@@ -155,14 +160,66 @@ func (s *Server) Read(request *pb.ReadRequest, stream pb.Frames_ReadServer) erro
 
 // Write write data to table
 func (s *Server) Write(stream pb.Frames_WriteServer) error {
-	msg, err := stream.Recv()
+	req, err := writeRequest(stream.Recv)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.api.WriteStream(req, frameSource(stream.Recv), nil)
+	if err != nil {
+		s.logger.ErrorWith("write error", "error", err)
+		return err
+	}
+
+	failedRows, err := failedRowsProto(result.FailedRows)
+	if err != nil {
+		return err
+	}
+
+	resp := &pb.WriteRespose{
+		Frames:     int64(result.Frames),
+		Rows:       int64(result.Rows),
+		FailedRows: failedRows,
+	}
+
+	return stream.SendAndClose(resp)
+}
+
+// WriteStream writes data to table, every frame is acknowledged once the
+// backend applied it
+func (s *Server) WriteStream(stream pb.Frames_WriteStreamServer) error {
+	req, err := writeRequest(stream.Recv)
 	if err != nil {
 		return err
 	}
 
+	ack := func(frame int, rows int, rowErrors []frames.RowError) error {
+		failedRows, err := failedRowsProto(rowErrors)
+		if err != nil {
+			return err
+		}
+
+		return stream.Send(&pb.WriteAck{Frame: int64(frame), Rows: int64(rows), FailedRows: failedRows})
+	}
+
+	if _, err := s.api.WriteStream(req, frameSource(stream.Recv), ack); err != nil {
+		s.logger.ErrorWith("write error", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// writeRequest reads the write request starting a write stream
+func writeRequest(recv func() (*pb.WriteRequest, error)) (*frames.WriteRequest, error) {
+	msg, err := recv()
+	if err != nil {
+		return nil, err
+	}
+
 	pbReq := msg.GetRequest()
 	if pbReq == nil {
-		return fmt.Errorf("stream didn't start with write request")
+		return nil, fmt.Errorf("stream didn't start with write request")
 	}
 	password := frames.InitSecretString(pbReq.Session.Password)
 	token := frames.InitSecretString(pbReq.Session.Token)
@@ -176,70 +233,56 @@ func (s *Server) Write(stream pb.Frames_WriteServer) error {
 
 	saveMode, err := frames.SaveModeFromString(pbReq.SaveMode)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req := &frames.WriteRequest{
-		Session:       pbReq.Session,
-		Password:      password,
-		Token:         token,
-		Backend:       pbReq.Backend,
-		Expression:    pbReq.Expression,
-		Condition:     pbReq.Condition,
-		HaveMore:      pbReq.More,
-		ImmidiateData: frame,
-		Table:         pbReq.Table,
-		SaveMode:      saveMode,
-		PartitionKeys: pbReq.PartitionKeys,
-	}
-
-	// TODO: Unite with the code in HTTP server
-	var (
-		writeError     error
-		nFrames, nRows int
-		ch             = make(chan frames.Frame, 1)
-		done           = make(chan bool)
-	)
+		Session:         pbReq.Session,
+		Password:        password,
+		Token:           token,
+		Backend:         pbReq.Backend,
+		Expression:      pbReq.Expression,
+		Condition:       pbReq.Condition,
+		HaveMore:        pbReq.More,
+		ImmidiateData:   frame,
+		Table:           pbReq.Table,
+		SaveMode:        saveMode,
+		PartitionKeys:   pbReq.PartitionKeys,
+		ContinueOnError: pbReq.ContinueOnError,
+		TimeZone:        pbReq.TimeZone,
+		TimePrecision:   pbReq.TimePrecision,
+		TTL:             pbReq.Ttl,
+		SchemaMode:      pbReq.SchemaMode,
+	}
 
-	go func() {
-		defer close(done)
-		nFrames, nRows, writeError = s.api.Write(req, ch)
-	}()
+	return req, nil
+}
+
+// failedRowsProto returns the failed rows frame message, nil if there are none
+func failedRowsProto(rowErrors []frames.RowError) (*pb.Frame, error) {
+	frame, err := frames.NewFailedRowsFrame(rowErrors)
+	if err != nil || frame == nil {
+		return nil, err
+	}
 
-	for writeError == nil {
-		msg, err := stream.Recv()
+	return frame.(pb.Framed).Proto(), nil
+}
+
+// frameSource returns the frames sent on a write stream after the request,
+// io.EOF when the client is done sending
+func frameSource(recv func() (*pb.WriteRequest, error)) func() (frames.Frame, error) {
+	return func() (frames.Frame, error) {
+		msg, err := recv()
 		if err != nil {
-			if err != io.EOF {
-				s.logger.ErrorWith("stream error", "error", err)
-				return err
-			}
-			break
+			return nil, err
 		}
 
 		frameMessage := msg.GetFrame()
 		if frameMessage == nil {
-			s.logger.ErrorWith("nil frame", "message", msg)
-			return fmt.Errorf("nil frame")
+			return nil, fmt.Errorf("nil frame")
 		}
 
-		frame := frames.NewFrameFromProto(frameMessage)
-		ch <- frame
-	}
-
-	close(ch)
-	<-done
-
-	// We can't handle writeError right after .Write since it's done in a goroutine
-	if writeError != nil {
-		s.logger.ErrorWith("write error", "error", writeError)
-		return writeError
-	}
-
-	resp := &pb.WriteRespose{
-		Frames: int64(nFrames),
-		Rows:   int64(nRows),
+		return frames.NewFrameFromProto(frameMessage), nil
 	}
-
-	return stream.SendAndClose(resp)
 }
 
 // Create creates a table
@@ -312,6 +355,17 @@ func (s *Server) Exec(ctx context.Context, req *pb.ExecRequest) (*pb.ExecRespons
 	return resp, nil
 }
 
+// BatchWrite writes frames to several tables, an aborted batch is replied
+// with the table results and the error
+func (s *Server) BatchWrite(ctx context.Context, req *pb.BatchWriteRequest) (*pb.BatchWriteResponse, error) {
+	result, err := s.api.BatchWrite(frames.BatchWriteRequestFromProto(req))
+	if result == nil {
+		return nil, err
+	}
+
+	return result.Proto(err), nil
+}
+
 // History returns framesd history logs
 func (s *Server) History(request *pb.HistoryRequest, stream pb.Frames_HistoryServer) error {
 	ch := make(chan frames.Frame)
@@ -0,0 +1,597 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+)
+
+// Write body formats
+const (
+	jsonRowsFormat    = "json"    // JSON list of row objects
+	ndjsonFormat      = "ndjson"  // Row object per line
+	jsonColumnsFormat = "columns" // Stream of {"col": [values...]} objects
+	csvFormat         = "csv"     // CSV with header line
+
+	defaultWriteBatchSize = 1024
+)
+
+// bodyDecoder decodes a request body to frames, returns io.EOF at end of body
+type bodyDecoder interface {
+	Next() (frames.Frame, error)
+}
+
+// bodyDecoderOptions are common options for body decoders
+type bodyDecoderOptions struct {
+	indices     []string        // index column names
+	timeColumns map[string]bool // columns to parse as time
+	batchSize   int             // rows per frame
+}
+
+// bodyFormat returns the body format from the "format" query argument or
+// from the request content type
+func bodyFormat(format string, contentType string) (string, error) {
+	switch format {
+	case jsonRowsFormat, ndjsonFormat, jsonColumnsFormat, csvFormat:
+		return format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unknown format - %q", format)
+	}
+
+	if contentType == "" {
+		return jsonRowsFormat, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", errors.Wrapf(err, "bad content type - %q", contentType)
+	}
+
+	switch mediaType {
+	case "application/json":
+		return jsonRowsFormat, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonlines", "application/x-jsonlines":
+		return ndjsonFormat, nil
+	case "text/csv", "application/csv":
+		return csvFormat, nil
+	}
+
+	return "", fmt.Errorf("unsupported content type - %q", mediaType)
+}
+
+// FrameDecoder decodes frames from CSV or JSON, Next returns io.EOF at the end
+// of the input
+type FrameDecoder interface {
+	Next() (frames.Frame, error)
+}
+
+// NewFrameDecoder returns a decoder of r in one of the REST write body
+// formats ("json", "ndjson", "columns" or "csv"). indices are the index
+// columns and timeColumns are columns to parse as time.
+func NewFrameDecoder(format string, r io.Reader, indices []string, timeColumns []string) (FrameDecoder, error) {
+	options := &bodyDecoderOptions{
+		indices:     indices,
+		timeColumns: make(map[string]bool),
+	}
+	for _, name := range timeColumns {
+		options.timeColumns[name] = true
+	}
+
+	return newBodyDecoder(format, r, options)
+}
+
+func newBodyDecoder(format string, r io.Reader, options *bodyDecoderOptions) (bodyDecoder, error) {
+	if options.batchSize <= 0 {
+		options.batchSize = defaultWriteBatchSize
+	}
+
+	switch format {
+	case jsonRowsFormat:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, errors.Wrap(err, "can't read JSON body")
+		}
+
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return nil, fmt.Errorf("body should be a JSON list of rows")
+		}
+		return &jsonRowsDecoder{dec: dec, options: options, types: make(map[string]frames.DType)}, nil
+	case ndjsonFormat:
+		return &ndjsonDecoder{reader: bufio.NewReader(r), options: options, types: make(map[string]frames.DType)}, nil
+	case jsonColumnsFormat:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		return &jsonColumnsDecoder{dec: dec, options: options}, nil
+	case csvFormat:
+		return &csvDecoder{reader: csv.NewReader(r), options: options}, nil
+	}
+
+	return nil, fmt.Errorf("unknown format - %q", format)
+}
+
+// jsonRowsDecoder decodes a JSON list of rows, the opening '[' was already read
+type jsonRowsDecoder struct {
+	dec     *json.Decoder
+	options *bodyDecoderOptions
+	types   map[string]frames.DType // column types set by earlier batches
+	done    bool
+}
+
+func (d *jsonRowsDecoder) Next() (frames.Frame, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+
+	var rows []map[string]interface{}
+	for len(rows) < d.options.batchSize {
+		if !d.dec.More() {
+			// Consume closing ']'
+			if _, err := d.dec.Token(); err != nil {
+				return nil, errors.Wrap(err, "can't read JSON body")
+			}
+			d.done = true
+			break
+		}
+
+		var row map[string]interface{}
+		if err := d.dec.Decode(&row); err != nil {
+			return nil, errors.Wrapf(err, "can't decode row %d", len(rows))
+		}
+		rows = append(rows, row)
+	}
+
+	return rowsToFrame(rows, d.options, d.types)
+}
+
+// ndjsonDecoder decodes a JSON object per line, empty lines are ignored
+type ndjsonDecoder struct {
+	reader  *bufio.Reader
+	options *bodyDecoderOptions
+	types   map[string]frames.DType // column types set by earlier batches
+	lineNum int
+	done    bool
+}
+
+func (d *ndjsonDecoder) Next() (frames.Frame, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+
+	var rows []map[string]interface{}
+	for len(rows) < d.options.batchSize {
+		line, err := d.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, "can't read body")
+		}
+		if err == io.EOF {
+			d.done = true
+		}
+		d.lineNum++
+
+		if len(strings.TrimSpace(string(line))) > 0 {
+			dec := json.NewDecoder(strings.NewReader(string(line)))
+			dec.UseNumber()
+			var row map[string]interface{}
+			if err := dec.Decode(&row); err != nil {
+				return nil, errors.Wrapf(err, "line %d: bad JSON", d.lineNum)
+			}
+			rows = append(rows, row)
+		}
+
+		if d.done {
+			break
+		}
+	}
+
+	return rowsToFrame(rows, d.options, d.types)
+}
+
+// jsonColumnsDecoder decodes a stream of {"col": [values...]} objects, each
+// object is a frame
+type jsonColumnsDecoder struct {
+	dec     *json.Decoder
+	options *bodyDecoderOptions
+}
+
+func (d *jsonColumnsDecoder) Next() (frames.Frame, error) {
+	var columns map[string][]interface{}
+	if err := d.dec.Decode(&columns); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, "can't decode columns")
+	}
+
+	data := make(map[string]interface{})
+	indices := make(map[string]interface{})
+	for name, values := range columns {
+		typed, err := columnValues(name, values, d.options.timeColumns[name])
+		if err != nil {
+			return nil, err
+		}
+
+		if inList(name, d.options.indices) {
+			indices[name] = typed
+		} else {
+			data[name] = typed
+		}
+	}
+
+	return frames.NewFrameFromMap(data, indices)
+}
+
+// csvDecoder decodes CSV with a header line. Column types are inferred from
+// the first batch, later batches with values of another type (except integers
+// in float columns) are rejected. Empty cells are missing values.
+type csvDecoder struct {
+	reader  *csv.Reader
+	options *bodyDecoderOptions
+	header  []string
+	dtypes  []frames.DType
+	lineNum int
+	done    bool
+}
+
+func (d *csvDecoder) Next() (frames.Frame, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+
+	if d.header == nil {
+		header, err := d.reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("missing CSV header")
+			}
+			return nil, errors.Wrap(err, "can't read CSV header")
+		}
+		d.header = header
+		d.lineNum++
+	}
+
+	var records [][]string
+	for len(records) < d.options.batchSize {
+		record, err := d.reader.Read()
+		if err == io.EOF {
+			d.done = true
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "can't read CSV")
+		}
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		return nil, io.EOF
+	}
+
+	if d.dtypes == nil {
+		d.dtypes = d.inferTypes(records)
+	}
+
+	rows := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		d.lineNum++
+		row := make(map[string]interface{})
+		for col, cell := range record {
+			if cell == "" {
+				continue
+			}
+
+			value, err := parseCell(cell, d.dtypes[col])
+			if err != nil {
+				if d.options.timeColumns[d.header[col]] {
+					return nil, errors.Wrapf(err, "line %d, column %q", d.lineNum, d.header[col])
+				}
+				err = typeChangeError(d.header[col], d.dtypes[col], cellType(cell))
+				return nil, errors.Wrapf(err, "line %d", d.lineNum)
+			}
+			row[d.header[col]] = value
+		}
+		rows[i] = row
+	}
+
+	return frames.NewFrameFromRows(rows, d.options.indices, nil)
+}
+
+// inferTypes infers column types, widening bool/int -> float -> string
+func (d *csvDecoder) inferTypes(records [][]string) []frames.DType {
+	dtypes := make([]frames.DType, len(d.header))
+	for col, name := range d.header {
+		if d.options.timeColumns[name] {
+			dtypes[col] = frames.TimeType
+			continue
+		}
+
+		dtype := frames.NullType
+		for _, record := range records {
+			if col >= len(record) || record[col] == "" {
+				continue
+			}
+			dtype = widenType(dtype, cellType(record[col]))
+		}
+
+		if dtype == frames.NullType {
+			dtype = frames.StringType
+		}
+		dtypes[col] = dtype
+	}
+
+	return dtypes
+}
+
+func cellType(cell string) frames.DType {
+	if _, err := strconv.ParseInt(cell, 10, 64); err == nil {
+		return frames.IntType
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return frames.FloatType
+	}
+	if cell == "true" || cell == "false" {
+		return frames.BoolType
+	}
+	if _, err := time.Parse(time.RFC3339Nano, cell); err == nil {
+		return frames.TimeType
+	}
+
+	return frames.StringType
+}
+
+func widenType(current, dtype frames.DType) frames.DType {
+	switch {
+	case current == frames.NullType || current == dtype:
+		return dtype
+	case (current == frames.IntType && dtype == frames.FloatType) || (current == frames.FloatType && dtype == frames.IntType):
+		return frames.FloatType
+	}
+
+	return frames.StringType
+}
+
+func parseCell(cell string, dtype frames.DType) (interface{}, error) {
+	switch dtype {
+	case frames.IntType:
+		return strconv.ParseInt(cell, 10, 64)
+	case frames.FloatType:
+		return strconv.ParseFloat(cell, 64)
+	case frames.BoolType:
+		return strconv.ParseBool(cell)
+	case frames.TimeType:
+		return parseTime(cell)
+	}
+
+	return cell, nil
+}
+
+// parseTime parses RFC 3339 time or epoch nanoseconds
+func parseTime(value interface{}) (time.Time, error) {
+	switch value := value.(type) {
+	case time.Time:
+		return value, nil
+	case int64:
+		return time.Unix(0, value), nil
+	case float64:
+		return time.Unix(0, int64(value)), nil
+	case string:
+		if ns, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Unix(0, ns), nil
+		}
+		return time.Parse(time.RFC3339Nano, value)
+	}
+
+	return time.Time{}, fmt.Errorf("can't convert %T to time", value)
+}
+
+// rowsToFrame converts JSON decoded rows to a frame. Integer columns that
+// contain floats in the same batch are converted to float. types holds the
+// column types of earlier batches, integers in float columns are converted to
+// float and other type changes are rejected.
+func rowsToFrame(rows []map[string]interface{}, options *bodyDecoderOptions, types map[string]frames.DType) (frames.Frame, error) {
+	if len(rows) == 0 {
+		return nil, io.EOF
+	}
+
+	floatColumns := make(map[string]bool)
+	for name, dtype := range types {
+		if dtype == frames.FloatType {
+			floatColumns[name] = true
+		}
+	}
+	for _, row := range rows {
+		for name, value := range row {
+			if value == nil { // missing values are filled by NewFrameFromRows
+				delete(row, name)
+				continue
+			}
+
+			value = jsonToGoValue(value)
+			if options.timeColumns[name] {
+				t, err := parseTime(value)
+				if err != nil {
+					return nil, errors.Wrapf(err, "column %q", name)
+				}
+				value = t
+			}
+
+			if _, ok := value.(float64); ok {
+				floatColumns[name] = true
+			}
+			row[name] = value
+		}
+	}
+
+	batchTypes := make(map[string]frames.DType)
+	for _, row := range rows {
+		for name := range floatColumns {
+			if i, ok := row[name].(int64); ok {
+				row[name] = float64(i)
+			}
+		}
+
+		for name, value := range row {
+			valueType := goValueType(value)
+			if dtype, ok := types[name]; ok && dtype != valueType {
+				return nil, typeChangeError(name, dtype, valueType)
+			}
+			if _, ok := batchTypes[name]; !ok {
+				batchTypes[name] = valueType
+			}
+		}
+	}
+
+	for name, dtype := range batchTypes {
+		types[name] = dtype
+	}
+
+	return frames.NewFrameFromRows(rows, options.indices, nil)
+}
+
+// typeChangeError is returned when a column has values of another type than
+// the type set by an earlier batch, the frames written from a body must have
+// the same column types
+func typeChangeError(name string, dtype frames.DType, valueType frames.DType) error {
+	return fmt.Errorf(
+		"column %q: %s value in %s column (column types are set by the first batch, use a larger batch_size)",
+		name, dtypeName(valueType), dtypeName(dtype))
+}
+
+func dtypeName(dtype frames.DType) string {
+	return strings.ToLower(pb.DType(dtype).String())
+}
+
+func goValueType(value interface{}) frames.DType {
+	switch value.(type) {
+	case int64:
+		return frames.IntType
+	case float64:
+		return frames.FloatType
+	case bool:
+		return frames.BoolType
+	case time.Time:
+		return frames.TimeType
+	}
+
+	return frames.StringType
+}
+
+// columnValues converts JSON decoded values to a typed slice
+func columnValues(name string, values []interface{}, isTime bool) (interface{}, error) {
+	dtype := frames.NullType
+	converted := make([]interface{}, len(values))
+	for i, value := range values {
+		value = jsonToGoValue(value)
+		var valueType frames.DType
+		switch value.(type) {
+		case nil:
+			return nil, fmt.Errorf("column %q: null values are not supported in column format", name)
+		case int64:
+			valueType = frames.IntType
+		case float64:
+			valueType = frames.FloatType
+		case bool:
+			valueType = frames.BoolType
+		case string:
+			valueType = frames.StringType
+		default:
+			return nil, fmt.Errorf("column %q: unsupported value type %T", name, value)
+		}
+
+		if isTime {
+			t, err := parseTime(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "column %q", name)
+			}
+			value, valueType = t, frames.TimeType
+		}
+
+		converted[i] = value
+		if dtype != frames.NullType && dtype != valueType &&
+			!(dtype == frames.IntType && valueType == frames.FloatType) &&
+			!(dtype == frames.FloatType && valueType == frames.IntType) {
+			return nil, fmt.Errorf("column %q: mixed value types", name)
+		}
+		dtype = widenType(dtype, valueType)
+	}
+
+	switch dtype {
+	case frames.IntType:
+		out := make([]int64, len(converted))
+		for i, value := range converted {
+			out[i] = value.(int64)
+		}
+		return out, nil
+	case frames.FloatType:
+		out := make([]float64, len(converted))
+		for i, value := range converted {
+			switch value := value.(type) {
+			case int64:
+				out[i] = float64(value)
+			case float64:
+				out[i] = value
+			}
+		}
+		return out, nil
+	case frames.BoolType:
+		out := make([]bool, len(converted))
+		for i, value := range converted {
+			out[i] = value.(bool)
+		}
+		return out, nil
+	case frames.TimeType:
+		out := make([]time.Time, len(converted))
+		for i, value := range converted {
+			out[i] = value.(time.Time)
+		}
+		return out, nil
+	}
+
+	out := make([]string, len(converted))
+	for i, value := range converted {
+		out[i] = value.(string)
+	}
+	return out, nil
+}
+
+func inList(name string, names []string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
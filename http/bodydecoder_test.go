@@ -0,0 +1,255 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+)
+
+func decodeAll(t *testing.T, format string, body string, options *bodyDecoderOptions) []frames.Frame {
+	dec, err := newBodyDecoder(format, strings.NewReader(body), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []frames.Frame
+	for {
+		frame, err := dec.Next()
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, frame)
+	}
+}
+
+func TestBodyFormat(t *testing.T) {
+	testCases := []struct {
+		format      string
+		contentType string
+		expected    string
+	}{
+		{"", "", jsonRowsFormat},
+		{"", "application/json; charset=utf-8", jsonRowsFormat},
+		{"", "application/x-ndjson", ndjsonFormat},
+		{"", "text/csv", csvFormat},
+		{"columns", "application/json", jsonColumnsFormat},
+	}
+
+	for _, tc := range testCases {
+		format, err := bodyFormat(tc.format, tc.contentType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != tc.expected {
+			t.Fatalf("%q/%q: %q != %q", tc.format, tc.contentType, format, tc.expected)
+		}
+	}
+
+	if _, err := bodyFormat("", "image/png"); err == nil {
+		t.Fatal("no error on unsupported content type")
+	}
+}
+
+func TestJSONRowsDecoder(t *testing.T) {
+	body := `[{"x": 1, "y": "a"}, {"x": 2.5, "y": "b"}, {"x": 3, "y": null}]`
+	out := decodeAll(t, jsonRowsFormat, body, &bodyDecoderOptions{batchSize: 2})
+	if len(out) != 2 {
+		t.Fatalf("bad number of frames - %d != 2", len(out))
+	}
+
+	col, err := out[0].Column("x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.DType() != frames.FloatType {
+		t.Fatalf("ints not widened to float in batch with floats")
+	}
+
+	if out[1].Len() != 1 {
+		t.Fatalf("bad last batch length - %d", out[1].Len())
+	}
+
+	col, err = out[1].Column("x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.DType() != frames.FloatType {
+		t.Fatalf("ints not widened to float after batch with floats")
+	}
+}
+
+func TestJSONRowsDecoderTypeChange(t *testing.T) {
+	body := `[{"x": 1}, {"x": 2}, {"x": 3.5}]`
+	dec, err := newBodyDecoder(jsonRowsFormat, strings.NewReader(body), &bodyDecoderOptions{batchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dec.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = dec.Next()
+	if err == nil {
+		t.Fatal("no error on int to float change after first batch")
+	}
+
+	if !strings.Contains(err.Error(), `column "x": float value in integer column`) {
+		t.Fatalf("bad error - %s", err)
+	}
+}
+
+func TestNDJSONDecoder(t *testing.T) {
+	body := "{\"id\": \"a\", \"ts\": \"2020-03-01T10:00:00Z\"}\n\n{\"id\": \"b\", \"ts\": 1583056800000000000}"
+	options := &bodyDecoderOptions{
+		indices:     []string{"ts"},
+		timeColumns: map[string]bool{"ts": true},
+	}
+	out := decodeAll(t, ndjsonFormat, body, options)
+	if len(out) != 1 || out[0].Len() != 2 {
+		t.Fatalf("bad result - %d frames", len(out))
+	}
+
+	indices := out[0].Indices()
+	if len(indices) != 1 || indices[0].DType() != frames.TimeType {
+		t.Fatalf("bad indices - %v", indices)
+	}
+
+	ts, err := indices[0].TimeAt(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC); !ts.Equal(expected) {
+		t.Fatalf("bad time - %s != %s", ts, expected)
+	}
+}
+
+func TestJSONColumnsDecoder(t *testing.T) {
+	body := `{"x": [1, 2], "y": ["a", "b"]} {"x": [3], "y": ["c"]}`
+	out := decodeAll(t, jsonColumnsFormat, body, &bodyDecoderOptions{})
+	if len(out) != 2 {
+		t.Fatalf("bad number of frames - %d != 2", len(out))
+	}
+
+	col, err := out[0].Column("x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.DType() != frames.IntType || col.Len() != 2 {
+		t.Fatalf("bad column - %v (%d)", col.DType(), col.Len())
+	}
+
+	dec, err := newBodyDecoder(jsonColumnsFormat, strings.NewReader(`{"x": [1, "a"]}`), &bodyDecoderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dec.Next(); err == nil {
+		t.Fatal("no error on mixed column")
+	}
+}
+
+func TestCSVDecoder(t *testing.T) {
+	body := "name,count,price,ok\na,1,1,true\nb,2,2.5,false\nc,,3,true\n"
+	out := decodeAll(t, csvFormat, body, &bodyDecoderOptions{indices: []string{"name"}})
+	if len(out) != 1 || out[0].Len() != 3 {
+		t.Fatalf("bad result - %d frames", len(out))
+	}
+
+	expected := map[string]frames.DType{
+		"count": frames.IntType,
+		"price": frames.FloatType,
+		"ok":    frames.BoolType,
+	}
+
+	for name, dtype := range expected {
+		col, err := out[0].Column(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if col.DType() != dtype {
+			t.Fatalf("%s: bad dtype - %v != %v", name, col.DType(), dtype)
+		}
+	}
+
+	if len(out[0].Indices()) != 1 {
+		t.Fatalf("index column missing")
+	}
+
+	dec, err := newBodyDecoder(csvFormat, strings.NewReader("x\n1\n2\nz\n"), &bodyDecoderOptions{batchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dec.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dec.Next(); err == nil {
+		t.Fatal("no error on type change after first batch")
+	}
+
+	dec, err = newBodyDecoder(csvFormat, strings.NewReader("x,y\n1,1.5\n2,2.5\n3.5,3\n"), &bodyDecoderOptions{batchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dec.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = dec.Next()
+	if err == nil {
+		t.Fatal("no error on int to float change after first batch")
+	}
+
+	if !strings.Contains(err.Error(), `line 4: column "x": float value in integer column`) {
+		t.Fatalf("bad error - %s", err)
+	}
+
+	// Integers in a float column are converted to float
+	out = decodeAll(t, csvFormat, "y\n1.5\n2.5\n3\n", &bodyDecoderOptions{batchSize: 2})
+	if len(out) != 2 {
+		t.Fatalf("bad number of frames - %d != 2", len(out))
+	}
+
+	col, err := out[1].Column("y")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.DType() != frames.FloatType {
+		t.Fatalf("bad dtype in second batch - %v", col.DType())
+	}
+}
@@ -22,14 +22,20 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	neturl "net/url"
 	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/nuclio/logger"
@@ -39,16 +45,29 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
+// unixScheme is the URL scheme of servers on a Unix domain socket
+const unixScheme = "http+unix"
+
 type httpResponseReaderCloser struct {
 	httpResponse *fasthttp.Response
-	bodyReader   bytes.Reader
+	bodyReader   io.ReadCloser
 }
 
-func newHTTPResponseReaderCloser(httpResponse *fasthttp.Response) httpResponseReaderCloser {
-	newHTTPResponseReaderCloser := httpResponseReaderCloser{}
-	newHTTPResponseReaderCloser.bodyReader.Reset(httpResponse.Body())
+// newHTTPResponseReaderCloser returns a reader over the response body,
+// decompressed according to the response Content-Encoding
+func newHTTPResponseReaderCloser(httpResponse *fasthttp.Response) (*httpResponseReaderCloser, error) {
+	encoding := string(httpResponse.Header.Peek("Content-Encoding"))
+	bodyReader, err := frames.NewDecompressReader(encoding, bytes.NewReader(httpResponse.Body()))
+	if err != nil {
+		return nil, err
+	}
+
+	newHTTPResponseReaderCloser := &httpResponseReaderCloser{
+		httpResponse: httpResponse,
+		bodyReader:   bodyReader,
+	}
 
-	return newHTTPResponseReaderCloser
+	return newHTTPResponseReaderCloser, nil
 }
 
 func (rc *httpResponseReaderCloser) Read(p []byte) (n int, err error) {
@@ -56,29 +75,80 @@ func (rc *httpResponseReaderCloser) Read(p []byte) (n int, err error) {
 }
 
 func (rc *httpResponseReaderCloser) Close() error {
+	err := rc.bodyReader.Close()
 	if rc.httpResponse != nil {
 		fasthttp.ReleaseResponse(rc.httpResponse)
+		rc.httpResponse = nil
 	}
 
-	return nil
+	return err
 }
 
 // Client is v3io HTTP streaming client
 type Client struct {
-	url        *neturl.URL
+	urls       []*neturl.URL
+	nextURL    uint32 // round robin index to urls
 	logger     logger.Logger
 	session    *frames.Session
 	httpClient *fasthttp.Client
+	config     *frames.ClientConfig
 }
 
 var (
-	// Make sure we're implementing frames.Client
-	_ frames.Client = &Client{}
+	// Make sure we're implementing frames.ClientV2
+	_ frames.ClientV2 = &Client{}
 )
 
-// NewClient returns a new HTTP client
-func NewClient(url string, session *frames.Session, logger logger.Logger) (*Client, error) {
-	var err error
+// statusError is an error reply from the server
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+// retryable returns true if a call failing with err can be retried
+func retryable(err error) bool {
+	err = errors.Cause(err)
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+
+	if serr, ok := err.(*statusError); ok {
+		switch serr.status {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	return isNetworkError(err)
+}
+
+// isNetworkError returns true if err is a connection error (e.g. connection
+// refused or reset), other errors (e.g. bad replies) fail again on retry
+func isNetworkError(err error) bool {
+	switch err {
+	case fasthttp.ErrConnectionClosed, fasthttp.ErrNoFreeConns, fasthttp.ErrTimeout,
+		fasthttp.ErrDialTimeout, fasthttp.ErrTLSHandshakeTimeout:
+		return true
+	}
+
+	var netErr net.Error
+	return goerrors.As(err, &netErr) || goerrors.Is(err, syscall.ECONNREFUSED) || goerrors.Is(err, syscall.ECONNRESET)
+}
+
+// NewClient returns a new HTTP client. url can be a comma separated list of
+// servers, calls are load balanced between them. Use
+// http+unix:///path/to/socket to connect to a server on a Unix domain socket.
+func NewClient(url string, session *frames.Session, logger logger.Logger, options ...frames.ClientOption) (*Client, error) {
+	config, err := frames.NewClientConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+
 	if logger == nil {
 		logger, err = frames.NewLogger("info")
 		if err != nil {
@@ -90,17 +160,41 @@ func NewClient(url string, session *frames.Session, logger logger.Logger) (*Clie
 		url = os.Getenv("V3IO_URL")
 	}
 
-	if url == "" {
+	addresses := frames.SplitAddresses(url)
+	if len(addresses) == 0 {
 		return nil, fmt.Errorf("empty URL")
 	}
 
-	netURL, err := neturl.Parse(url)
-	if err != nil {
-		return nil, fmt.Errorf("bad URL - %s", err)
+	var urls []*neturl.URL
+	for i, address := range addresses {
+		// http://host1,host2 - host2 uses the scheme of host1
+		if i > 0 && !strings.Contains(address, "://") {
+			address = urls[0].Scheme + "://" + address
+		}
+
+		netURL, err := neturl.Parse(address)
+		if err != nil {
+			return nil, fmt.Errorf("bad URL - %s", err)
+		}
+
+		if netURL.Scheme == "" {
+			netURL.Scheme = "http"
+		}
+		urls = append(urls, netURL)
 	}
 
-	if netURL.Scheme == "" {
-		netURL.Scheme = "http"
+	var socketPath string
+	if urls[0].Scheme == unixScheme {
+		if len(urls) > 1 {
+			return nil, fmt.Errorf("%s URL can't be used with other servers", unixScheme)
+		}
+
+		socketPath = urls[0].Path
+		if socketPath == "" {
+			return nil, fmt.Errorf("missing socket path in %q", url)
+		}
+		// Requests are sent to the socket regardless of the host
+		urls[0] = &neturl.URL{Scheme: "http", Host: "localhost"}
 	}
 
 	if session == nil {
@@ -111,63 +205,159 @@ func NewClient(url string, session *frames.Session, logger logger.Logger) (*Clie
 		}
 	}
 
-	httpClient := fasthttp.Client{
-		TLSConfig: &tls.Config{
+	tlsConfig := config.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{
 			InsecureSkipVerify: true,
-		},
+		}
+	}
+
+	httpClient := fasthttp.Client{
+		TLSConfig:           tlsConfig,
+		MaxConnsPerHost:     config.MaxConnsPerHost,
+		MaxResponseBodySize: config.MaxMessageSize,
+	}
+
+	switch {
+	case socketPath != "":
+		httpClient.Dial = func(addr string) (net.Conn, error) {
+			return net.DialTimeout("unix", socketPath, config.DialTimeout)
+		}
+	case config.DialTimeout > 0:
+		httpClient.Dial = func(addr string) (net.Conn, error) {
+			return fasthttp.DialTimeout(addr, config.DialTimeout)
+		}
 	}
 
 	client := &Client{
-		url:        netURL,
+		urls:       urls,
 		session:    session,
 		logger:     logger,
 		httpClient: &httpClient,
+		config:     config,
 	}
 
 	return client, nil
 }
 
+// url returns the server URL for the next call
+func (c *Client) url() *neturl.URL {
+	if len(c.urls) == 1 {
+		return c.urls[0]
+	}
+
+	n := atomic.AddUint32(&c.nextURL, 1)
+	return c.urls[int(n%uint32(len(c.urls)))]
+}
+
+// newRequest returns a new POST request to path on the next server
+func (c *Client) newRequest(path string) *fasthttp.Request {
+	url := c.url()
+	httpRequest := fasthttp.AcquireRequest()
+	httpRequest.URI().SetScheme(url.Scheme)
+	httpRequest.URI().SetHost(url.Host)
+	httpRequest.URI().SetPath(url.Path + path)
+	httpRequest.Header.SetMethod("POST")
+	return httpRequest
+}
+
+// do sends the request (and releases it) while honoring ctx. On error the
+// response is released.
+func (c *Client) do(ctx context.Context, httpRequest *fasthttp.Request) (*fasthttp.Response, error) {
+	httpResponse := fasthttp.AcquireResponse()
+	done := make(chan error, 1)
+	go func() {
+		if deadline, ok := ctx.Deadline(); ok {
+			done <- c.httpClient.DoDeadline(httpRequest, httpResponse, deadline)
+			return
+		}
+		done <- c.httpClient.Do(httpRequest, httpResponse)
+	}()
+
+	select {
+	case err := <-done:
+		fasthttp.ReleaseRequest(httpRequest)
+		if err != nil {
+			fasthttp.ReleaseResponse(httpResponse)
+			return nil, err
+		}
+		return httpResponse, nil
+	case <-ctx.Done():
+		// Request and response are still used by the client
+		go func() {
+			<-done
+			fasthttp.ReleaseRequest(httpRequest)
+			fasthttp.ReleaseResponse(httpResponse)
+		}()
+		return nil, ctx.Err()
+	}
+}
+
 // Read runs a query on the client
-func (c *Client) Read(request *pb.ReadRequest) (frames.FrameIterator, error) {
+func (c *Client) Read(ctx context.Context, request *pb.ReadRequest) (frames.FrameIterator, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	return c.streamCall(ctx, "/read", request)
+}
+
+// History returns the request history log
+func (c *Client) History(ctx context.Context, request *pb.HistoryRequest) (frames.FrameIterator, error) {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
+	return c.streamCall(ctx, "/history", request)
+}
+
+// streamCall calls an API returning a stream of frames (with retries)
+func (c *Client) streamCall(ctx context.Context, path string, request interface{}) (frames.FrameIterator, error) {
 	marshalledRequest, err := json.Marshal(request)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to marshall request")
 	}
 
-	httpRequest := fasthttp.AcquireRequest()
-	httpRequest.URI().SetScheme(c.url.Scheme)
-	httpRequest.URI().SetHost(c.url.Host)
-	httpRequest.URI().SetPath(c.url.Path + "/read")
-	httpRequest.SetBody(marshalledRequest)
-	httpRequest.Header.SetContentType("application/json")
-	httpRequest.Header.SetMethod("POST")
-
-	httpResponse := fasthttp.AcquireResponse()
+	var httpResponse *fasthttp.Response
+	err = c.config.Retry.Retry(ctx, retryable, func() error {
+		httpRequest := c.newRequest(path)
+		httpRequest.SetBody(marshalledRequest)
+		httpRequest.Header.SetContentType("application/json")
+		if c.config.Compression != frames.NoCompression {
+			httpRequest.Header.Set("Accept-Encoding", c.config.Compression)
+		}
 
-	err = c.httpClient.Do(httpRequest, httpResponse)
-	if err != nil {
-		return nil, errors.Wrap(err, "Failed to call API")
-	}
+		var err error
+		httpResponse, err = c.do(ctx, httpRequest)
+		if err != nil {
+			return errors.Wrap(err, "Failed to call API")
+		}
 
-	fasthttp.ReleaseRequest(httpRequest)
+		if httpResponse.StatusCode() != http.StatusOK {
+			defer fasthttp.ReleaseResponse(httpResponse)
+			return &statusError{
+				status:  httpResponse.StatusCode(),
+				message: fmt.Sprintf("API returned with bad code - %d\n%s", httpResponse.StatusCode(), httpResponse.Body()),
+			}
+		}
 
-	if httpResponse.StatusCode() != http.StatusOK {
-		defer fasthttp.ReleaseResponse(httpResponse)
-		var buf bytes.Buffer
-		buf.Write(httpResponse.Body())
+		return nil
+	})
 
-		return nil, fmt.Errorf("API returned with bad code - %d\n%s", httpResponse.StatusCode(), buf.String())
+	if err != nil {
+		return nil, err
 	}
 
-	httpResponseReaderCloser := newHTTPResponseReaderCloser(httpResponse)
+	httpResponseReaderCloser, err := newHTTPResponseReaderCloser(httpResponse)
+	if err != nil {
+		fasthttp.ReleaseResponse(httpResponse)
+		return nil, errors.Wrap(err, "can't decompress response")
+	}
 
 	it := &streamFrameIterator{
-		reader:  &httpResponseReaderCloser,
-		decoder: frames.NewDecoder(&httpResponseReaderCloser),
+		ctx:     ctx,
+		reader:  httpResponseReaderCloser,
+		decoder: frames.NewDecoder(httpResponseReaderCloser),
 		logger:  c.logger,
 	}
 
@@ -175,7 +365,7 @@ func (c *Client) Read(request *pb.ReadRequest) (frames.FrameIterator, error) {
 }
 
 // Write writes data
-func (c *Client) Write(request *frames.WriteRequest) (frames.FrameAppender, error) {
+func (c *Client) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
 	if request.Backend == "" || request.Table == "" {
 		return nil, fmt.Errorf("missing request parameters")
 	}
@@ -189,35 +379,52 @@ func (c *Client) Write(request *frames.WriteRequest) (frames.FrameAppender, erro
 		return nil, err
 	}
 
+	// The initial request is compressed separately (as a complete compressed
+	// stream) so it can be sent before the HTTP call starts reading the pipe.
+	// The server decodes concatenated compressed streams as one.
 	var buf bytes.Buffer
-	enc := frames.NewEncoder(&buf)
+	initialWriter, err := frames.NewCompressWriter(c.config.Compression, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := frames.NewEncoder(initialWriter)
 	if err := enc.Encode(msg); err != nil {
 		return nil, errors.Wrap(err, "Can't encode request")
 	}
 
+	if err := initialWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "Can't compress request")
+	}
+
 	reader, writer := io.Pipe()
+	compressWriter, err := frames.NewCompressWriter(c.config.Compression, writer)
+	if err != nil {
+		return nil, err
+	}
 
-	httpRequest := fasthttp.AcquireRequest()
-	httpRequest.URI().SetScheme(c.url.Scheme)
-	httpRequest.URI().SetHost(c.url.Host)
-	httpRequest.URI().SetPath(c.url.Path + "/write")
+	httpRequest := c.newRequest("/write")
 	httpRequest.Header.SetContentType("application/json")
-	httpRequest.Header.SetMethod("POST")
+	if c.config.Compression != frames.NoCompression {
+		httpRequest.Header.Set("Content-Encoding", c.config.Compression)
+	}
 	httpRequest.SetBodyStream(io.MultiReader(&buf, reader), -1)
 
 	appender := &streamFrameAppender{
-		writer:  writer,
-		encoder: frames.NewEncoder(writer),
-		ch:      make(chan *appenderHTTPResponse, 1),
-		logger:  c.logger,
+		writer:         writer,
+		compressWriter: compressWriter,
+		encoder:        frames.NewEncoder(compressWriter),
+		ch:             make(chan *appenderHTTPResponse, 1),
+		logger:         c.logger,
 	}
 
 	// Call API in a goroutine since it's going to block reading from pipe
 	go func() {
-		httpResponse := fasthttp.AcquireResponse()
-		err := c.httpClient.Do(httpRequest, httpResponse)
+		httpResponse, err := c.do(ctx, httpRequest)
 		if err != nil {
 			c.logger.ErrorWith("error calling API", "error", err)
+			// Fail pending and future Add calls
+			writer.CloseWithError(err)
 		}
 
 		appender.ch <- &appenderHTTPResponse{httpResponse, err}
@@ -227,32 +434,61 @@ func (c *Client) Write(request *frames.WriteRequest) (frames.FrameAppender, erro
 }
 
 // Delete deletes data
-func (c *Client) Delete(request *pb.DeleteRequest) error {
+func (c *Client) Delete(ctx context.Context, request *pb.DeleteRequest) error {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	_, err := c.jsonCall("/delete", request, false)
-	return err
+	call := func() error {
+		_, err := c.jsonCall(ctx, "/delete", request, false)
+		return err
+	}
+
+	if !frames.IdempotentDelete(request) {
+		return call()
+	}
+
+	return c.config.Retry.Retry(ctx, retryable, call)
 }
 
 // Create creates a table
-func (c *Client) Create(request *pb.CreateRequest) error {
+func (c *Client) Create(ctx context.Context, request *pb.CreateRequest) error {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	_, err := c.jsonCall("/create", request, false)
-	return err
+	call := func() error {
+		_, err := c.jsonCall(ctx, "/create", request, false)
+		return err
+	}
+
+	if !frames.IdempotentCreate(request) {
+		return call()
+	}
+
+	return c.config.Retry.Retry(ctx, retryable, call)
 }
 
 // Exec executes a command
-func (c *Client) Exec(request *pb.ExecRequest) (frames.Frame, error) {
+func (c *Client) Exec(ctx context.Context, request *pb.ExecRequest) (frames.Frame, error) {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	httpResponse, err := c.jsonCall("/exec", request, true)
+	// Frames don't encode to JSON, send them base64 encoded
+	body := struct {
+		*pb.ExecRequest
+		Frame string `json:"frame,omitempty"`
+	}{ExecRequest: request}
+	if request.Frame != nil {
+		data, err := frames.MarshalFrame(frames.NewFrameFromProto(request.Frame))
+		if err != nil {
+			return nil, errors.Wrap(err, "can't marshal frame")
+		}
+		body.Frame = base64.StdEncoding.EncodeToString(data)
+	}
+
+	httpResponse, err := c.jsonCall(ctx, "/exec", body, true)
 	if err != nil {
 		return nil, err
 	}
@@ -278,27 +514,80 @@ func (c *Client) Exec(request *pb.ExecRequest) (frames.Frame, error) {
 	return frames.UnmarshalFrame(data)
 }
 
-func (c *Client) jsonCall(path string, request interface{}, returnResponse bool) (*fasthttp.Response, error) {
+// BatchWrite writes frames to several tables, either all of them are written
+// or none is. Batch writes are not retried.
+func (c *Client) BatchWrite(ctx context.Context, request *frames.BatchWriteRequest) (*frames.BatchWriteResult, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	// Frames don't encode to JSON, send them base64 encoded
+	body := &batchWriteBody{BatchWriteRequest: &pb.BatchWriteRequest{
+		Session:          request.Session,
+		Backend:          request.Backend,
+		VersionAttribute: request.VersionAttribute,
+	}}
+	for _, table := range request.Tables {
+		tableBody := &batchWriteTableBody{Table: table.Table, PartitionKeys: table.PartitionKeys}
+		if table.Frame != nil {
+			data, err := frames.MarshalFrame(table.Frame)
+			if err != nil {
+				return nil, errors.Wrapf(err, "can't marshal frame of table '%s'", table.Table)
+			}
+			tableBody.Frame = base64.StdEncoding.EncodeToString(data)
+		}
+		body.Tables = append(body.Tables, tableBody)
+	}
+
+	httpResponse, err := c.jsonCall(ctx, "/batch_write", body, true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fasthttp.ReleaseResponse(httpResponse)
+	reply := &pb.BatchWriteResponse{}
+	if err := json.Unmarshal(httpResponse.Body(), reply); err != nil {
+		return nil, errors.Wrap(err, "bad JSON reply")
+	}
+
+	return frames.BatchWriteResultFromProto(reply)
+}
+
+// Version returns the server version
+func (c *Client) Version(ctx context.Context) (string, error) {
+	var reply struct {
+		Version string `json:"version"`
+	}
+
+	err := c.config.Retry.Retry(ctx, retryable, func() error {
+		httpResponse, err := c.jsonCall(ctx, "/version", struct{}{}, true)
+		if err != nil {
+			return err
+		}
+
+		defer fasthttp.ReleaseResponse(httpResponse)
+		return errors.Wrap(json.Unmarshal(httpResponse.Body(), &reply), "bad JSON reply")
+	})
+
+	return reply.Version, err
+}
+
+func (c *Client) jsonCall(ctx context.Context, path string, request interface{}, returnResponse bool) (*fasthttp.Response, error) {
+	ctx, cancel := c.config.CallContext(ctx)
+	defer cancel()
+
 	var buf bytes.Buffer
 
 	if err := json.NewEncoder(&buf).Encode(request); err != nil {
 		return nil, errors.Wrap(err, "can't encode request")
 	}
 
-	httpRequest := fasthttp.AcquireRequest()
-	httpRequest.URI().SetScheme(c.url.Scheme)
-	httpRequest.URI().SetHost(c.url.Host)
-	httpRequest.URI().SetPath(path)
+	httpRequest := c.newRequest(path)
 	httpRequest.SetBody(buf.Bytes())
 	httpRequest.Header.SetContentType("application/json")
-	httpRequest.Header.SetMethod("POST")
 
-	httpResponse := fasthttp.AcquireResponse()
-
-	err := c.httpClient.Do(httpRequest, httpResponse)
-	fasthttp.ReleaseRequest(httpRequest)
+	httpResponse, err := c.do(ctx, httpRequest)
 	if err != nil {
-		fasthttp.ReleaseResponse(httpResponse)
 		return nil, err
 	}
 
@@ -306,8 +595,9 @@ func (c *Client) jsonCall(path string, request interface{}, returnResponse bool)
 		errMessage := fmt.Sprintf("error calling server (%d): %s",
 			httpResponse.StatusCode(),
 			string(httpResponse.Body()))
+		status := httpResponse.StatusCode()
 		fasthttp.ReleaseResponse(httpResponse)
-		return nil, fmt.Errorf("error calling server - %s", errMessage)
+		return nil, &statusError{status, fmt.Sprintf("error calling server - %s", errMessage)}
 	}
 
 	if !returnResponse {
@@ -320,6 +610,7 @@ func (c *Client) jsonCall(path string, request interface{}, returnResponse bool)
 
 // streamFrameIterator implements FrameIterator over io.Reader
 type streamFrameIterator struct {
+	ctx     context.Context
 	frame   frames.Frame
 	err     error
 	reader  io.Reader
@@ -328,6 +619,11 @@ type streamFrameIterator struct {
 }
 
 func (it *streamFrameIterator) Next() bool {
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
 	var err error
 	msg := &pb.Frame{}
 
@@ -371,10 +667,13 @@ type appenderHTTPResponse struct {
 
 // streamFrameAppender implements FrameAppender over io.Writer
 type streamFrameAppender struct {
-	writer  io.Writer
-	encoder *frames.Encoder
-	ch      chan *appenderHTTPResponse
-	logger  logger.Logger
+	writer         *io.PipeWriter
+	compressWriter frames.CompressWriter // compresses to writer
+	encoder        *frames.Encoder       // encodes to compressWriter
+	ch             chan *appenderHTTPResponse
+	logger         logger.Logger
+	failedRows     frames.Frame
+	replied        bool // server replied before reading all frames
 }
 
 func (a *streamFrameAppender) Add(frame frames.Frame) error {
@@ -383,25 +682,47 @@ func (a *streamFrameAppender) Add(frame frames.Frame) error {
 		return errors.New("unknown frame type")
 	}
 
-	if err := a.encoder.Encode(iface.Proto()); err != nil {
-		return errors.Wrap(err, "can't encode frame")
+	if a.replied {
+		return nil
+	}
+
+	err := a.encoder.Encode(iface.Proto())
+	if err == nil {
+		// Send the frame now so the server can start processing it
+		err = a.compressWriter.Flush()
+	}
+
+	// The server replied before reading all the data (e.g. on error), the
+	// reply is reported by WaitForComplete
+	if errors.Cause(err) == io.ErrClosedPipe {
+		a.replied = true
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "can't send frame")
 	}
 
 	return nil
 }
 
 func (a *streamFrameAppender) WaitForComplete(timeout time.Duration) error {
-	closer, ok := a.writer.(io.Closer)
-	if !ok {
-		return fmt.Errorf("writer is not a closer")
+	if !a.replied {
+		if err := a.compressWriter.Close(); err != nil && errors.Cause(err) != io.ErrClosedPipe {
+			return errors.Wrap(err, "can't close compressor")
+		}
 	}
 
-	if err := closer.Close(); err != nil {
+	if err := a.writer.Close(); err != nil {
 		return errors.Wrap(err, "can't close writer")
 	}
 
 	select {
 	case hr := <-a.ch:
+		if hr.err != nil {
+			return hr.err
+		}
+
 		if hr.httpResponse.StatusCode() != http.StatusOK {
 			err := fmt.Errorf("Server returned error: %d\n%s",
 				hr.httpResponse.StatusCode(),
@@ -411,8 +732,20 @@ func (a *streamFrameAppender) WaitForComplete(timeout time.Duration) error {
 			return err
 		}
 
-		fasthttp.ReleaseResponse(hr.httpResponse)
-		return hr.err
+		defer fasthttp.ReleaseResponse(hr.httpResponse)
+		var reply struct {
+			FailedRows []frames.RowError `json:"failed_rows"`
+		}
+		if err := json.Unmarshal(hr.httpResponse.Body(), &reply); err != nil {
+			return errors.Wrap(err, "can't decode write reply")
+		}
+
+		failedRows, err := frames.NewFailedRowsFrame(reply.FailedRows)
+		if err != nil {
+			return errors.Wrap(err, "can't create failed rows frame")
+		}
+		a.failedRows = failedRows
+		return nil
 	case <-time.After(timeout):
 		return fmt.Errorf("timeout after %s", timeout)
 	}
@@ -429,19 +762,30 @@ func pbWriteReq(req *frames.WriteRequest) (*pb.InitialWriteRequest, error) {
 	}
 
 	msg := &pb.InitialWriteRequest{
-		Session:       req.Session,
-		Backend:       req.Backend,
-		Table:         req.Table,
-		InitialData:   frMsg,
-		Expression:    req.Expression,
-		More:          req.HaveMore,
-		SaveMode:      req.SaveMode.String(),
-		PartitionKeys: req.PartitionKeys,
-		Condition:     req.Condition,
+		Session:         req.Session,
+		Backend:         req.Backend,
+		Table:           req.Table,
+		InitialData:     frMsg,
+		Expression:      req.Expression,
+		More:            req.HaveMore,
+		SaveMode:        req.SaveMode.String(),
+		PartitionKeys:   req.PartitionKeys,
+		Condition:       req.Condition,
+		ContinueOnError: req.ContinueOnError,
+		TimeZone:        req.TimeZone,
+		TimePrecision:   req.TimePrecision,
+		Ttl:             req.TTL,
+		SchemaMode:      req.SchemaMode,
 	}
 
 	return msg, nil
 }
 
+// FailedRows returns the rows the server failed to write, valid after
+// WaitForComplete when the write request has ContinueOnError set
+func (a *streamFrameAppender) FailedRows() frames.Frame {
+	return a.failedRows
+}
+
 func (a *streamFrameAppender) Close() {
 }
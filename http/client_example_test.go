@@ -21,6 +21,7 @@ such restriction.
 package http
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -38,6 +39,7 @@ func ExampleClient() {
 		fmt.Printf("can't connect to %q - %s", url, err)
 		return
 	}
+	ctx := context.Background()
 
 	frame, err := makeFrame()
 	if err != nil {
@@ -51,7 +53,7 @@ func ExampleClient() {
 		Table:   tableName,
 	}
 
-	appender, err := client.Write(writeReq)
+	appender, err := client.Write(ctx, writeReq)
 	if err != nil {
 		if err != nil {
 			fmt.Printf("can't write - %s", err)
@@ -76,7 +78,7 @@ func ExampleClient() {
 		MessageLimit: 100,
 	}
 
-	it, err := client.Read(readReq)
+	it, err := client.Read(ctx, readReq)
 	if err != nil {
 		fmt.Printf("can't query - %s", err)
 		return
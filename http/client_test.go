@@ -21,7 +21,15 @@ such restriction.
 package http
 
 import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"syscall"
 	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
 )
 
 func TestNewClient(t *testing.T) {
@@ -37,3 +45,47 @@ func TestNewClient(t *testing.T) {
 		t.Fatal("no logger")
 	}
 }
+
+func TestNewUnixSocketClient(t *testing.T) {
+	client, err := NewClient("http+unix:///run/framesd.sock", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if url := client.url().String(); url != "http://localhost" {
+		t.Fatalf("bad request URL - %s", url)
+	}
+
+	for _, url := range []string{"http+unix://", "http+unix:///run/framesd.sock,localhost:8080"} {
+		if _, err := NewClient(url, nil, nil); err == nil {
+			t.Fatalf("%q: no error", url)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	var badJSON interface{}
+	jsonErr := json.Unmarshal([]byte("{"), &badJSON)
+
+	cases := []struct {
+		err       error
+		retryable bool
+	}{
+		{&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{errors.Wrap(syscall.ECONNRESET, "Failed to call API"), true},
+		{fasthttp.ErrConnectionClosed, true},
+		{fasthttp.ErrTimeout, true},
+		{&statusError{status: http.StatusServiceUnavailable}, true},
+		{&statusError{status: http.StatusBadRequest}, false},
+		{errors.Wrap(jsonErr, "bad JSON reply"), false},
+		{errors.New("can't encode request"), false},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+	}
+
+	for _, c := range cases {
+		if retryable(c.err) != c.retryable {
+			t.Fatalf("%v: retryable != %v", c.err, c.retryable)
+		}
+	}
+}
@@ -21,12 +21,16 @@ such restriction.
 package http_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	nhttp "net/http"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -76,6 +80,7 @@ func TestEnd2End(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	ctx := context.Background()
 
 	frame, err := makeFrame()
 	if err != nil {
@@ -88,7 +93,7 @@ func TestEnd2End(t *testing.T) {
 		Table:   tableName,
 	}
 
-	appender, err := client.Write(writeReq)
+	appender, err := client.Write(ctx, writeReq)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -107,7 +112,7 @@ func TestEnd2End(t *testing.T) {
 		MessageLimit: 100,
 	}
 
-	it, err := client.Read(readReq)
+	it, err := client.Read(ctx, readReq)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -137,11 +142,147 @@ func TestEnd2End(t *testing.T) {
 		Backend: backendName,
 		Table:   tableName,
 		Command: "ping",
+		Frame:   frame.(pb.Framed).Proto(), // ignored, checks encoding
 	}
 
-	if _, err := client.Exec(execReq); err != nil {
+	if _, err := client.Exec(ctx, execReq); err != nil {
 		t.Fatalf("can't exec - %s", err)
 	}
+
+	if _, err := client.Version(ctx); err != nil {
+		t.Fatalf("can't get version - %s", err)
+	}
+
+	testFailover(t, url)
+
+	for _, compression := range []string{frames.GzipCompression, frames.ZstdCompression} {
+		testCompression(t, url, backendName, compression, frame)
+	}
+
+	testREST(t, url, backendName)
+}
+
+func testREST(t *testing.T, baseURL string, backend string) {
+	tableURL := fmt.Sprintf("%s/v1/%s/tables/rest", baseURL, backend)
+	body := `[{"id": 1, "name": "a", "score": 1.5}, {"id": 2, "name": "b", "score": 2.5}]`
+	req, err := nhttp.NewRequest(nhttp.MethodPut, tableURL+"/rows", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := nhttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("can't write - %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nhttp.StatusOK {
+		t.Fatalf("bad write status - %s", resp.Status)
+	}
+
+	resp, err = nhttp.Get(tableURL + "/rows")
+	if err != nil {
+		t.Fatalf("can't read - %s", err)
+	}
+	defer resp.Body.Close()
+
+	var reply struct {
+		Rows  []map[string]interface{}
+		Error string
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		t.Fatalf("can't decode read reply - %s", err)
+	}
+
+	if reply.Error != "" {
+		t.Fatalf("read error - %s", reply.Error)
+	}
+
+	if len(reply.Rows) != 2 {
+		t.Fatalf("bad number of rows - %d != 2", len(reply.Rows))
+	}
+
+	if name := reply.Rows[1]["name"]; name != "b" {
+		t.Fatalf("bad name in second row - %v", name)
+	}
+
+	csvBody := "id,name,score\n3,c,3.5\n4,d,4\n"
+	resp, err = nhttp.Post(tableURL+"/rows", "text/csv", strings.NewReader(csvBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nhttp.StatusMethodNotAllowed {
+		t.Fatalf("POST to rows should not be allowed - %s", resp.Status)
+	}
+
+	req, err = nhttp.NewRequest(nhttp.MethodPut, tableURL+"/rows?save_mode=overwriteTable", strings.NewReader(csvBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "text/csv")
+
+	resp, err = nhttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("can't write CSV - %s", err)
+	}
+	var writeReply struct {
+		NumRows int `json:"num_rows"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&writeReply)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("can't decode write reply - %s", err)
+	}
+	if writeReply.NumRows != 2 {
+		t.Fatalf("bad number of rows written - %d != 2", writeReply.NumRows)
+	}
+
+	req, err = nhttp.NewRequest(nhttp.MethodGet, tableURL+"/rows", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Encoding", "zstd;q=0.5, gzip")
+
+	resp, err = nhttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("can't read compressed - %s", err)
+	}
+	resp.Body.Close()
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != frames.GzipCompression {
+		t.Fatalf("bad content encoding - %q", encoding)
+	}
+
+	resp, err = nhttp.Get(tableURL + "/tail")
+	if err != nil {
+		t.Fatalf("can't tail - %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nhttp.StatusBadRequest {
+		t.Fatalf("tail should not be supported by csv backend - %s", resp.Status)
+	}
+
+	resp, err = nhttp.Get(fmt.Sprintf("%s/v1/openapi.json", baseURL))
+	if err != nil {
+		t.Fatalf("can't get OpenAPI document - %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nhttp.StatusOK {
+		t.Fatalf("bad OpenAPI status - %s", resp.Status)
+	}
+
+	req, err = nhttp.NewRequest(nhttp.MethodDelete, tableURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = nhttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("can't delete - %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nhttp.StatusNoContent {
+		t.Fatalf("bad delete status - %s", resp.Status)
+	}
 }
 
 func testGrafana(t *testing.T, baseURL string, backend string, table string) {
@@ -201,3 +342,170 @@ func makeFrame() (frames.Frame, error) {
 	}
 	return frames.NewFrameFromMap(columns, nil)
 }
+
+func testCompression(t *testing.T, url string, backend string, compression string, frame frames.Frame) {
+	client, err := http.NewClient(url, nil, nil, frames.WithCompression(compression))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tableName := "e2e-" + compression
+	appender, err := client.Write(ctx, &frames.WriteRequest{Backend: backend, Table: tableName})
+	if err != nil {
+		t.Fatalf("%s: can't write - %s", compression, err)
+	}
+
+	if err := appender.Add(frame); err != nil {
+		t.Fatalf("%s: can't add frame - %s", compression, err)
+	}
+
+	if err := appender.WaitForComplete(10 * time.Second); err != nil {
+		t.Fatalf("%s: can't complete write - %s", compression, err)
+	}
+
+	it, err := client.Read(ctx, &pb.ReadRequest{Backend: backend, Table: tableName, MessageLimit: 100})
+	if err != nil {
+		t.Fatalf("%s: can't read - %s", compression, err)
+	}
+
+	nRows := 0
+	for it.Next() {
+		nRows += it.At().Len()
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("%s: read error - %s", compression, err)
+	}
+
+	if nRows != frame.Len() {
+		t.Fatalf("%s: # of rows mismatch - %d != %d", compression, nRows, frame.Len())
+	}
+}
+
+// testFailover checks that idempotent calls are retried on another server
+func testFailover(t *testing.T, url string) {
+	deadPort, err := freePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Calls are round robin, the first call goes to the second (dead) server
+	urls := fmt.Sprintf("%s,http://localhost:%d", url, deadPort)
+	policy := frames.RetryPolicy{MaxAttempts: 2, Multiplier: 1}
+	client, err := http.NewClient(urls, nil, nil, frames.WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.Version(ctx); err != nil {
+		t.Fatalf("no failover - %s", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Version(ctx); err == nil {
+		t.Fatal("no error on canceled context")
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	tmpDir := t.TempDir()
+	backendName := "e2e-backend"
+	cfg := &frames.Config{
+		Log: frames.LogConfig{
+			Level: "debug",
+		},
+		Backends: []*frames.BackendConfig{
+			{
+				Name:    backendName,
+				Type:    "csv",
+				RootDir: tmpDir,
+			},
+		},
+		UnixSocketMode: "0600",
+	}
+
+	socketPath := filepath.Join(tmpDir, "framesd.sock")
+	srv, err := http.NewServer(cfg, "unix://"+socketPath, nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Fatalf("bad socket mode: %o != %o", mode, 0600)
+	}
+
+	frame, err := makeFrame()
+	if err != nil {
+		t.Fatalf("can't create frame - %s", err)
+	}
+
+	url := "http+unix://" + socketPath
+	client, err := frames.NewClient(url, &frames.Session{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCompression(t, url, backendName, frames.ZstdCompression, frame)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := client.Version(ctx); err != nil {
+		t.Fatalf("can't get version - %s", err)
+	}
+}
+
+func TestMaxRequestBodySize(t *testing.T) {
+	cfg := &frames.Config{
+		Backends: []*frames.BackendConfig{
+			{
+				Name:    "e2e-backend",
+				Type:    "csv",
+				RootDir: t.TempDir(),
+			},
+		},
+		HTTP: frames.HTTPConfig{MaxRequestBodySize: 1024},
+	}
+
+	port, err := freePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := http.NewServer(cfg, fmt.Sprintf(":%d", port), nil, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond) // Let server start
+
+	url := fmt.Sprintf("http://localhost:%d/create", port)
+	body := `{"backend": "e2e-backend", "table": "` + strings.Repeat("x", 10*1024) + `"}`
+	for _, chunked := range []bool{false, true} {
+		var reader io.Reader = strings.NewReader(body)
+		if chunked {
+			reader = io.MultiReader(reader) // Unknown length
+		}
+
+		resp, err := nhttp.Post(url, "application/json", reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != nhttp.StatusRequestEntityTooLarge {
+			t.Fatalf("bad status of oversized body (chunked=%v): %s", chunked, resp.Status)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/v3io/frames/pb"
+	"github.com/valyala/fasthttp"
+)
+
+// OpenAPI document generation. Query parameters are generated from the
+// protobuf request messages (see protoQueryFields) so the document stays in
+// sync with frames.proto.
+
+type openAPIObject = map[string]interface{}
+
+// openAPISchema returns the OpenAPI schema of a query field
+func openAPISchema(typ reflect.Type) openAPIObject {
+	switch typ.Kind() {
+	case reflect.Int64:
+		return openAPIObject{"type": "integer", "format": "int64"}
+	case reflect.Bool:
+		return openAPIObject{"type": "boolean"}
+	case reflect.Int32: // pb.ErrorOptions
+		return openAPIObject{"type": "string", "enum": []string{"fail", "ignore"}}
+	case reflect.Slice:
+		return openAPIObject{
+			"type":  "array",
+			"items": openAPISchema(typ.Elem()),
+		}
+	}
+
+	return openAPIObject{"type": "string"}
+}
+
+// openAPIQueryParams returns the query parameters of the msg fields, except
+// the excluded ones
+func openAPIQueryParams(msg interface{}, exclude ...string) []openAPIObject {
+	params := []openAPIObject{
+		openAPIParam("container", "query", "V3IO container (overrides server default)", openAPIObject{"type": "string"}),
+	}
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+
+	for _, field := range protoQueryFields(msg) {
+		if excluded[field.name] {
+			continue
+		}
+		param := openAPIParam(field.name, "query", "", openAPISchema(field.kind))
+		if field.kind.Kind() == reflect.Slice {
+			param["style"] = "form"
+			param["explode"] = false
+		}
+		params = append(params, param)
+	}
+
+	return params
+}
+
+func openAPIParam(name, in, description string, schema openAPIObject) openAPIObject {
+	param := openAPIObject{
+		"name":   name,
+		"in":     in,
+		"schema": schema,
+	}
+
+	if in == "path" {
+		param["required"] = true
+	}
+
+	if description != "" {
+		param["description"] = description
+	}
+
+	return param
+}
+
+func openAPIResponse(description string, schemaRef string) openAPIObject {
+	response := openAPIObject{"description": description}
+	if schemaRef != "" {
+		response["content"] = openAPIObject{
+			"application/json": openAPIObject{
+				"schema": openAPIObject{"$ref": "#/components/schemas/" + schemaRef},
+			},
+		}
+	}
+
+	return response
+}
+
+func openAPIErrorResponses(responses openAPIObject) openAPIObject {
+	responses["400"] = openAPIResponse("Bad request", "Error")
+	responses["500"] = openAPIResponse("Server error", "Error")
+	return responses
+}
+
+func openAPIJSONBody(description string, schema openAPIObject) openAPIObject {
+	return openAPIObject{
+		"description": description,
+		"content": openAPIObject{
+			"application/json": openAPIObject{"schema": schema},
+		},
+	}
+}
+
+func withPathParams(params []openAPIObject, names ...string) []openAPIObject {
+	var out []openAPIObject
+	for _, name := range names {
+		out = append(out, openAPIParam(name, "path", "", openAPIObject{"type": "string"}))
+	}
+
+	return append(out, params...)
+}
+
+// newOpenAPIDocument returns the JSON encoded OpenAPI document of the REST API
+func newOpenAPIDocument(version string) ([]byte, error) {
+	if version == "" {
+		version = "unknown"
+	}
+
+	rowsSchema := openAPIObject{
+		"type": "array",
+		"items": openAPIObject{
+			"type":                 "object",
+			"additionalProperties": true,
+		},
+	}
+
+	writeParams := []openAPIObject{
+		openAPIParam("index", "query", "Comma separated index columns", openAPIObject{"type": "string"}),
+		openAPIParam("save_mode", "query", "", openAPIObject{
+			"type": "string",
+			"enum": []string{"errorIfTableExists", "overwriteTable", "updateItem", "overwriteItem", "createNewItemsOnly"},
+		}),
+		openAPIParam("expression", "query", "Update expression template (NoSQL)", openAPIObject{"type": "string"}),
+		openAPIParam("condition", "query", "Update condition template (NoSQL)", openAPIObject{"type": "string"}),
+		openAPIParam("partition_keys", "query", "Comma separated partition columns (NoSQL)", openAPIObject{"type": "string"}),
+		openAPIParam("continue_on_error", "query", "Report rows that fail to write in failed_rows instead of failing (NoSQL)", openAPIObject{"type": "boolean"}),
+		openAPIParam("time_zone", "query", "Time zone of written time columns (NoSQL)", openAPIObject{"type": "string"}),
+		openAPIParam("time_precision", "query", "Precision of written time columns (NoSQL)", openAPIObject{
+			"type": "string",
+			"enum": []string{"s", "ms", "us", "ns"},
+		}),
+		openAPIParam("ttl", "query", "Item time to live (e.g. 1h) or expiry time column (NoSQL)", openAPIObject{"type": "string"}),
+		openAPIParam("schema_mode", "query", "Check of written columns against the table schema (NoSQL)", openAPIObject{"type": "string", "enum": []string{"evolve", "strict", "coerce"}}),
+		openAPIParam("format", "query", "Body format (default from Content-Type)", openAPIObject{
+			"type": "string",
+			"enum": []string{jsonRowsFormat, ndjsonFormat, jsonColumnsFormat, csvFormat},
+		}),
+		openAPIParam("time_columns", "query", "Comma separated columns holding RFC 3339 times or epoch nanoseconds", openAPIObject{"type": "string"}),
+		openAPIParam("batch_size", "query", "Rows per written frame, column types are set by the first frame", openAPIObject{"type": "integer"}),
+	}
+
+	tailParams := []openAPIObject{
+		openAPIParam("poll_interval", "query", "Time between polls (e.g. 500ms, default 1s)", openAPIObject{"type": "string"}),
+		openAPIParam("batch_size", "query", "Maximal rows per event (default is an event per poll)", openAPIObject{"type": "integer"}),
+		openAPIParam("max_wait", "query", "Maximal time rows wait for a full batch (default poll_interval)", openAPIObject{"type": "string"}),
+		openAPIParam("duration", "query", "Stop after this duration (default is until client disconnects)", openAPIObject{"type": "string"}),
+	}
+
+	writeBody := openAPIJSONBody("Rows to write (JSON list, or stream of column objects when format=columns)", rowsSchema)
+	writeBody["content"].(openAPIObject)["application/x-ndjson"] = openAPIObject{"schema": openAPIObject{"type": "string"}}
+	writeBody["content"].(openAPIObject)["text/csv"] = openAPIObject{"schema": openAPIObject{"type": "string"}}
+
+	tablePath := restPrefix + "{backend}/tables/{table}"
+	doc := openAPIObject{
+		"openapi": "3.0.3",
+		"info": openAPIObject{
+			"title":       "v3io frames",
+			"description": "Resource oriented API over frames backends",
+			"version":     version,
+		},
+		"paths": openAPIObject{
+			restPrefix + "version": openAPIObject{
+				"get": openAPIObject{
+					"summary":   "Server version",
+					"responses": openAPIObject{"200": openAPIResponse("Server version", "Version")},
+				},
+			},
+			tablePath: openAPIObject{
+				"put": openAPIObject{
+					"summary":     "Create table",
+					"parameters":  withPathParams(openAPIQueryParams(&pb.CreateRequest{}), "backend", "table"),
+					"requestBody": openAPIJSONBody("Optional create request (e.g. schema)", openAPIObject{"type": "object"}),
+					"responses":   openAPIErrorResponses(openAPIObject{"201": openAPIResponse("Table created", "")}),
+				},
+				"delete": openAPIObject{
+					"summary":    "Delete table",
+					"parameters": withPathParams(openAPIQueryParams(&pb.DeleteRequest{}, deleteRowSelectors...), "backend", "table"),
+					"responses":  openAPIErrorResponses(openAPIObject{"204": openAPIResponse("Table deleted", "")}),
+				},
+			},
+			tablePath + "/rows": openAPIObject{
+				"get": openAPIObject{
+					"summary":    "Read rows",
+					"parameters": withPathParams(openAPIQueryParams(&pb.ReadRequest{}), "backend", "table"),
+					"responses":  openAPIErrorResponses(openAPIObject{"200": openAPIResponse("Rows", "Rows")}),
+				},
+				"put": openAPIObject{
+					"summary":     "Write rows",
+					"parameters":  withPathParams(writeParams, "backend", "table"),
+					"requestBody": writeBody,
+					"responses":   openAPIErrorResponses(openAPIObject{"200": openAPIResponse("Write summary", "WriteSummary")}),
+				},
+				"delete": openAPIObject{
+					"summary":    "Delete rows matching filter (or time range)",
+					"parameters": withPathParams(openAPIQueryParams(&pb.DeleteRequest{}), "backend", "table"),
+					"responses":  openAPIErrorResponses(openAPIObject{"204": openAPIResponse("Rows deleted", "")}),
+				},
+			},
+			tablePath + "/tail": openAPIObject{
+				"get": openAPIObject{
+					"summary":     "Live tail of a stream shard or a TSDB table",
+					"description": "Polls for new data and pushes rows as Server-Sent Events (event types rows, error and end). Send Last-Event-ID to resume.",
+					"parameters":  withPathParams(append(openAPIQueryParams(&pb.ReadRequest{}), tailParams...), "backend", "table"),
+					"responses": openAPIErrorResponses(openAPIObject{
+						"200": openAPIObject{
+							"description": "Event stream",
+							"content": openAPIObject{
+								"text/event-stream": openAPIObject{"schema": openAPIObject{"type": "string"}},
+							},
+						},
+					}),
+				},
+			},
+			tablePath + "/exec/{command}": openAPIObject{
+				"post": openAPIObject{
+					"summary": "Execute backend command",
+					"parameters": withPathParams([]openAPIObject{
+						openAPIParam("container", "query", "V3IO container (overrides server default)", openAPIObject{"type": "string"}),
+						openAPIParam("expression", "query", "", openAPIObject{"type": "string"}),
+					}, "backend", "table", "command"),
+					"requestBody": openAPIJSONBody("Command arguments", openAPIObject{"type": "object"}),
+					"responses":   openAPIErrorResponses(openAPIObject{"200": openAPIResponse("Command result", "Rows")}),
+				},
+			},
+		},
+		"components": openAPIObject{
+			"schemas": openAPIObject{
+				"Error": openAPIObject{
+					"type":       "object",
+					"properties": openAPIObject{"error": openAPIObject{"type": "string"}},
+				},
+				"Rows": openAPIObject{
+					"type": "object",
+					"properties": openAPIObject{
+						"rows":  rowsSchema,
+						"error": openAPIObject{"type": "string"},
+						"marker": openAPIObject{
+							"type":        "string",
+							"description": "Pass as the marker query argument to continue the read (NoSQL, set only if there are more rows)",
+						},
+					},
+				},
+				"Version": openAPIObject{
+					"type":       "object",
+					"properties": openAPIObject{"version": openAPIObject{"type": "string"}},
+				},
+				"WriteSummary": openAPIObject{
+					"type": "object",
+					"properties": openAPIObject{
+						"num_frames": openAPIObject{"type": "integer"},
+						"num_rows":   openAPIObject{"type": "integer"},
+						"failed_rows": openAPIObject{
+							"type": "array",
+							"items": openAPIObject{
+								"type": "object",
+								"properties": openAPIObject{
+									"key":        openAPIObject{"type": "string"},
+									"error_code": openAPIObject{"type": "string"},
+									"message":    openAPIObject{"type": "string"},
+								},
+							},
+						},
+					},
+				},
+			},
+			"securitySchemes": openAPIObject{
+				"basic":  openAPIObject{"type": "http", "scheme": "basic"},
+				"bearer": openAPIObject{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []openAPIObject{{"basic": []string{}}, {"bearer": []string{}}},
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func (s *Server) handleOpenAPI(ctx *fasthttp.RequestCtx) {
+	if !ctx.IsGet() {
+		s.restError(ctx, http.StatusMethodNotAllowed, errUnsupportedMethod)
+		return
+	}
+
+	ctx.Response.Header.SetContentType("application/json")
+	ctx.SetBody(s.openAPI)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"container/list"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// Buckets not used for this long are removed
+	rateLimitIdleTimeout = 10 * time.Minute
+	// Maximal number of buckets, the least recently used is removed to add a
+	// bucket when there are more
+	rateLimitMaxBuckets = 100000
+)
+
+// tokenBucket is a token bucket for a single key
+type tokenBucket struct {
+	key      string
+	tokens   float64
+	lastSeen time.Time
+}
+
+// rateLimiter is a token bucket rate limiter per key
+type rateLimiter struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	lock      sync.Mutex
+	buckets   map[string]*list.Element // of lru
+	lru       *list.List               // of *tokenBucket, most recently used first
+	lastSweep time.Time
+
+	allowed  uint64
+	rejected uint64
+}
+
+// rateLimitStats are rate limiter metrics
+type rateLimitStats struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	Allowed           uint64  `json:"allowed"`
+	Rejected          uint64  `json:"rejected"`
+	ActiveKeys        int     `json:"active_keys"`
+}
+
+// newRateLimiter returns a new rate limiter, nil if rate limit is disabled
+func newRateLimiter(config frames.RateLimitConfig) *rateLimiter {
+	if config.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	return &rateLimiter{
+		rate:      config.RequestsPerSecond,
+		burst:     float64(config.Burst),
+		buckets:   make(map[string]*list.Element),
+		lru:       list.New(),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow returns true if a request for key is allowed. If not, it returns the
+// time until a token will be available.
+func (rl *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	if now.Sub(rl.lastSweep) > rateLimitIdleTimeout {
+		rl.sweep(now)
+	}
+
+	var bucket *tokenBucket
+	if elem, ok := rl.buckets[key]; ok {
+		bucket = elem.Value.(*tokenBucket)
+		rl.lru.MoveToFront(elem)
+	} else {
+		if len(rl.buckets) >= rateLimitMaxBuckets {
+			rl.evict()
+		}
+		bucket = &tokenBucket{key: key, tokens: rl.burst, lastSeen: now}
+		rl.buckets[key] = rl.lru.PushFront(bucket)
+	}
+
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	bucket.tokens = math.Min(rl.burst, bucket.tokens+elapsed*rl.rate)
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		atomic.AddUint64(&rl.rejected, 1)
+		wait := time.Duration((1 - bucket.tokens) / rl.rate * float64(time.Second))
+		return false, wait
+	}
+
+	bucket.tokens--
+	atomic.AddUint64(&rl.allowed, 1)
+	return true, 0
+}
+
+// sweep removes idle buckets, must be called with lock held
+func (rl *rateLimiter) sweep(now time.Time) {
+	for elem := rl.lru.Back(); elem != nil; elem = rl.lru.Back() {
+		if now.Sub(elem.Value.(*tokenBucket).lastSeen) <= rateLimitIdleTimeout {
+			break
+		}
+		rl.remove(elem)
+	}
+	rl.lastSweep = now
+}
+
+// evict removes the least recently used bucket, must be called with lock held
+func (rl *rateLimiter) evict() {
+	if elem := rl.lru.Back(); elem != nil {
+		rl.remove(elem)
+	}
+}
+
+// remove removes a bucket, must be called with lock held
+func (rl *rateLimiter) remove(elem *list.Element) {
+	rl.lru.Remove(elem)
+	delete(rl.buckets, elem.Value.(*tokenBucket).key)
+}
+
+func (rl *rateLimiter) stats() *rateLimitStats {
+	rl.lock.Lock()
+	activeKeys := len(rl.buckets)
+	rl.lock.Unlock()
+
+	return &rateLimitStats{
+		RequestsPerSecond: rl.rate,
+		Burst:             int(rl.burst),
+		Allowed:           atomic.LoadUint64(&rl.allowed),
+		Rejected:          atomic.LoadUint64(&rl.rejected),
+		ActiveKeys:        activeKeys,
+	}
+}
+
+// rateLimitKey returns the rate limit key of the request, the client IP.
+// Credentials in the authorization header are verified only by the backends,
+// so clients could get a new bucket by changing them on every request.
+//
+// Unix domain socket clients have no address, each of their connections gets
+// its own bucket.
+func (s *Server) rateLimitKey(ctx *fasthttp.RequestCtx) string {
+	if _, ok := ctx.RemoteAddr().(*net.UnixAddr); ok {
+		return "conn:" + strconv.FormatUint(ctx.ConnID(), 10)
+	}
+
+	return "ip:" + ctx.RemoteIP().String()
+}
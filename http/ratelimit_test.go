@@ -0,0 +1,124 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRateLimiter(t *testing.T) {
+	if rl := newRateLimiter(frames.RateLimitConfig{}); rl != nil {
+		t.Fatal("rate limiter created with zero rate")
+	}
+
+	rl := newRateLimiter(frames.RateLimitConfig{RequestsPerSecond: 2, Burst: 3})
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := rl.allow("bugs", now); !ok {
+			t.Fatalf("request %d in burst rejected", i)
+		}
+	}
+
+	ok, wait := rl.allow("bugs", now)
+	if ok {
+		t.Fatal("request over burst allowed")
+	}
+
+	if wait <= 0 || wait > time.Second {
+		t.Fatalf("bad wait time - %s", wait)
+	}
+
+	if ok, _ := rl.allow("daffy", now); !ok {
+		t.Fatal("request from other key rejected")
+	}
+
+	if ok, _ := rl.allow("bugs", now.Add(500*time.Millisecond)); !ok {
+		t.Fatal("request after refill rejected")
+	}
+
+	stats := rl.stats()
+	if stats.Allowed != 5 || stats.Rejected != 1 || stats.ActiveKeys != 2 {
+		t.Fatalf("bad stats - %+v", stats)
+	}
+
+	rl.allow("elmer", now.Add(2*rateLimitIdleTimeout))
+	if stats := rl.stats(); stats.ActiveKeys != 1 {
+		t.Fatalf("idle keys not removed - %+v", stats)
+	}
+}
+
+func TestRateLimiterMaxBuckets(t *testing.T) {
+	rl := newRateLimiter(frames.RateLimitConfig{RequestsPerSecond: 1, Burst: 1})
+	now := time.Now()
+
+	rl.allow("first", now)
+	for i := 0; i < rateLimitMaxBuckets; i++ {
+		rl.allow(fmt.Sprintf("key-%d", i), now.Add(time.Second))
+	}
+
+	stats := rl.stats()
+	if stats.ActiveKeys != rateLimitMaxBuckets {
+		t.Fatalf("bad number of buckets - %d", stats.ActiveKeys)
+	}
+
+	if _, ok := rl.buckets["first"]; ok {
+		t.Fatal("least recently used bucket not removed")
+	}
+
+	// Using a bucket makes it the most recently used
+	rl.allow("key-0", now.Add(2*time.Second))
+	rl.allow("last", now.Add(2*time.Second))
+	if _, ok := rl.buckets["key-0"]; !ok {
+		t.Fatal("recently used bucket removed")
+	}
+	if _, ok := rl.buckets["key-1"]; ok {
+		t.Fatal("least recently used bucket not removed")
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	s := &Server{}
+	key := func(addr net.Addr, authorization string) string {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Init(&fasthttp.Request{}, addr, nil)
+		ctx.Request.Header.Set("Authorization", authorization)
+		return s.rateLimitKey(ctx)
+	}
+
+	// Unverified credentials don't change the key
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1")}
+	if k1, k2 := key(addr, "Bearer one"), key(addr, "Basic Ym9iOnB3"); k1 != k2 || k1 != "ip:10.0.0.1" {
+		t.Fatalf("bad keys - %q, %q", k1, k2)
+	}
+
+	// Unix socket connections don't share a bucket
+	unixAddr := &net.UnixAddr{Net: "unix"}
+	if k1, k2 := key(unixAddr, ""), key(unixAddr, ""); k1 == k2 {
+		t.Fatalf("unix socket connections with the same key - %q", k1)
+	}
+}
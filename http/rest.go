@@ -0,0 +1,668 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	neturl "net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+	"github.com/valyala/fasthttp"
+)
+
+// REST API layout (all routes are relative to restPrefix):
+//
+//	GET    openapi.json                           OpenAPI description
+//	GET    version                                server version
+//	PUT    {backend}/tables/{table}               create table
+//	DELETE {backend}/tables/{table}               delete table
+//	GET    {backend}/tables/{table}/rows          read
+//	PUT    {backend}/tables/{table}/rows          write (JSON, NDJSON, columns or CSV)
+//	DELETE {backend}/tables/{table}/rows          delete rows matching filter
+//	GET    {backend}/tables/{table}/tail          live tail (Server-Sent Events)
+//	POST   {backend}/tables/{table}/exec/{cmd}    execute command
+//
+// Table names may contain slashes (e.g. KV directories), either plain or URL
+// escaped.
+const (
+	restPrefix      = "/v1/"
+	restOpenAPIPath = restPrefix + "openapi.json"
+)
+
+// restRoute is a parsed REST path
+type restRoute struct {
+	backend  string
+	table    string
+	resource string // "", "rows", "tail" or "exec"
+	command  string
+}
+
+// queryField is a protobuf request field that can be set from a query argument
+type queryField struct {
+	name  string // JSON (and query argument) name
+	index int    // struct field index
+	kind  reflect.Type
+}
+
+// Fields that come from the URL path or from the authorization header
+var restReservedFields = map[string]bool{
+	"session": true,
+	"backend": true,
+	"table":   true,
+}
+
+var (
+	errorOptionsType     = reflect.TypeOf(pb.ErrorOptions(0))
+	errUnsupportedMethod = errors.New("unsupported method")
+)
+
+// protoQueryFields returns the fields of msg (a pointer to protobuf message)
+// that can be set from query arguments
+func protoQueryFields(msg interface{}) []queryField {
+	typ := reflect.TypeOf(msg).Elem()
+	var fields []queryField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || restReservedFields[name] {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Int64, reflect.Bool:
+		case reflect.Int32:
+			if field.Type != errorOptionsType {
+				continue
+			}
+		case reflect.Slice:
+			elemKind := field.Type.Elem().Kind()
+			if elemKind != reflect.String && elemKind != reflect.Int64 {
+				continue
+			}
+		default:
+			continue
+		}
+
+		fields = append(fields, queryField{name, i, field.Type})
+	}
+
+	return fields
+}
+
+// queryToProto sets fields in msg (a pointer to protobuf message) from query
+// arguments. Lists are comma separated.
+func queryToProto(args *fasthttp.Args, msg interface{}) error {
+	val := reflect.ValueOf(msg).Elem()
+	for _, field := range protoQueryFields(msg) {
+		if !args.Has(field.name) {
+			continue
+		}
+
+		arg := string(args.Peek(field.name))
+		fld := val.Field(field.index)
+		switch field.kind.Kind() {
+		case reflect.String:
+			fld.SetString(arg)
+		case reflect.Int64:
+			i, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return errors.Errorf("bad integer value for %q - %q", field.name, arg)
+			}
+			fld.SetInt(i)
+		case reflect.Bool:
+			if arg == "" { // ?reset_index is the same as ?reset_index=true
+				arg = "true"
+			}
+			b, err := strconv.ParseBool(arg)
+			if err != nil {
+				return errors.Errorf("bad boolean value for %q - %q", field.name, arg)
+			}
+			fld.SetBool(b)
+		case reflect.Int32:
+			opt, ok := pb.ErrorOptions_value[strings.ToUpper(arg)]
+			if !ok {
+				return errors.Errorf("bad value for %q - %q (should be fail or ignore)", field.name, arg)
+			}
+			fld.SetInt(int64(opt))
+		case reflect.Slice:
+			parts := splitList(arg)
+			slice := reflect.MakeSlice(field.kind, len(parts), len(parts))
+			for i, part := range parts {
+				if field.kind.Elem().Kind() == reflect.String {
+					slice.Index(i).SetString(part)
+					continue
+				}
+
+				n, err := strconv.ParseInt(part, 10, 64)
+				if err != nil {
+					return errors.Errorf("bad integer value for %q - %q", field.name, part)
+				}
+				slice.Index(i).SetInt(n)
+			}
+			fld.Set(slice)
+		}
+	}
+
+	return nil
+}
+
+func splitList(arg string) []string {
+	var out []string
+	for _, part := range strings.Split(arg, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+
+	return out
+}
+
+// parseRESTPath parses path (without restPrefix) to a route
+func parseRESTPath(path string) (*restRoute, error) {
+	var parts []string
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		part, err := neturl.PathUnescape(part)
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad path segment %q", part)
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) < 3 || parts[1] != "tables" {
+		return nil, fmt.Errorf("unknown path - %q", restPrefix+path)
+	}
+
+	route := &restRoute{backend: parts[0]}
+	tableParts := parts[2:]
+	n := len(tableParts)
+	switch {
+	case n > 1 && (tableParts[n-1] == "rows" || tableParts[n-1] == "tail"):
+		route.resource = tableParts[n-1]
+		tableParts = tableParts[:n-1]
+	case n > 2 && tableParts[n-2] == "exec":
+		route.resource = "exec"
+		route.command = tableParts[n-1]
+		tableParts = tableParts[:n-2]
+	}
+
+	route.table = strings.Join(tableParts, "/")
+	if route.table == "" {
+		return nil, fmt.Errorf("missing table name")
+	}
+
+	return route, nil
+}
+
+func (s *Server) handleREST(ctx *fasthttp.RequestCtx, path string) {
+	switch path {
+	case restOpenAPIPath:
+		s.handleOpenAPI(ctx)
+		return
+	case restPrefix + "version":
+		if !ctx.IsGet() {
+			s.restError(ctx, http.StatusMethodNotAllowed, errUnsupportedMethod)
+			return
+		}
+		_ = s.replyJSON(ctx, map[string]interface{}{"version": s.version})
+		return
+	}
+
+	route, err := parseRESTPath(strings.TrimPrefix(path, restPrefix))
+	if err != nil {
+		if isWriteRoute(ctx, path) {
+			s.restWriteError(ctx, http.StatusNotFound, err)
+			return
+		}
+		s.restError(ctx, http.StatusNotFound, err)
+		return
+	}
+
+	method := string(ctx.Method())
+	switch {
+	case route.resource == "" && method == http.MethodPut:
+		s.handleRESTCreate(ctx, route)
+	case route.resource == "" && method == http.MethodDelete:
+		s.handleRESTDelete(ctx, route, false)
+	case route.resource == "rows" && method == http.MethodGet:
+		s.handleRESTRead(ctx, route)
+	case route.resource == "rows" && method == http.MethodPut:
+		s.handleRESTWrite(ctx, route)
+	case route.resource == "rows" && method == http.MethodDelete:
+		s.handleRESTDelete(ctx, route, true)
+	case route.resource == "tail" && method == http.MethodGet:
+		s.handleRESTTail(ctx, route)
+	case route.resource == "exec" && method == http.MethodPost:
+		s.handleRESTExec(ctx, route)
+	default:
+		s.restError(ctx, http.StatusMethodNotAllowed, fmt.Errorf("unsupported method %s", method))
+	}
+}
+
+// restSession returns a session from the authorization header and the
+// "container" and "path" query arguments
+func (s *Server) restSession(ctx *fasthttp.RequestCtx) (*frames.Session, frames.SecretString, frames.SecretString) {
+	args := ctx.QueryArgs()
+	session := &frames.Session{
+		Container: string(args.Peek("container")),
+		Path:      string(args.Peek("path")),
+	}
+	s.httpAuth(ctx, session)
+
+	password := frames.InitSecretString(session.Password)
+	token := frames.InitSecretString(session.Token)
+	session.Password = ""
+	session.Token = ""
+
+	return session, password, token
+}
+
+func (s *Server) handleRESTRead(ctx *fasthttp.RequestCtx, route *restRoute) {
+	requestInner := &pb.ReadRequest{}
+	if err := queryToProto(ctx.QueryArgs(), requestInner); err != nil {
+		s.restError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	requestInner.Backend = route.backend
+	requestInner.Table = route.table
+
+	request := &frames.ReadRequest{Proto: requestInner}
+	requestInner.Session, request.Password, request.Token = s.restSession(ctx)
+
+	s.logger.DebugWith("REST read request", "request", request)
+
+	ch := make(chan frames.Frame)
+	var apiError error
+	go func() {
+		defer close(ch)
+		apiError = s.api.Read(request, ch)
+		if apiError != nil {
+			s.logger.ErrorWith("error reading", "error", apiError)
+		}
+	}()
+
+	ctx.Response.Header.SetContentType("application/json")
+	compression := s.responseCompression(ctx)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		cw, err := frames.NewCompressWriter(compression, w)
+		if err != nil { // Can't happen, compression was negotiated
+			s.logger.ErrorWith("can't create compressor", "error", err)
+			for range ch {
+			}
+			return
+		}
+
+		// Reply is {"rows": [...], "error": "...", "marker": "..."}, error is
+		// set only on failure and marker only if the read has more rows
+		bw := bufio.NewWriter(cw)
+		_, _ = bw.WriteString(`{"rows":[`)
+		first := true
+		marker := ""
+		for frame := range ch {
+			if err != nil {
+				continue // drain channel
+			}
+
+			marker, _ = frame.Labels()[frames.MarkerLabel].(string)
+
+			if err = writeJSONRows(bw, frame, &first); err != nil {
+				s.logger.ErrorWith("can't encode rows", "error", err)
+				continue
+			}
+
+			if err = flushAll(bw, cw, w); err != nil {
+				s.logger.ErrorWith("can't flush", "error", err)
+			}
+		}
+		_, _ = bw.WriteString("]")
+
+		if err == nil {
+			err = apiError
+		}
+
+		if err != nil {
+			msg, _ := json.Marshal(err.Error())
+			_, _ = bw.WriteString(`,"error":`)
+			_, _ = bw.Write(msg)
+		} else if marker != "" {
+			msg, _ := json.Marshal(marker)
+			_, _ = bw.WriteString(`,"marker":`)
+			_, _ = bw.Write(msg)
+		}
+		_, _ = bw.WriteString("}\n")
+		_ = bw.Flush()
+		if err := cw.Close(); err != nil {
+			s.logger.ErrorWith("can't close compressor", "error", err)
+		}
+	})
+}
+
+type flusher interface {
+	Flush() error
+}
+
+func flushAll(flushers ...flusher) error {
+	for _, f := range flushers {
+		if err := f.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handleRESTWrite(ctx *fasthttp.RequestCtx, route *restRoute) {
+	args := ctx.QueryArgs()
+	saveMode, err := frames.SaveModeFromString(string(args.Peek("save_mode")))
+	if err != nil {
+		s.restWriteError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	format, err := bodyFormat(string(args.Peek("format")), string(ctx.Request.Header.ContentType()))
+	if err != nil {
+		s.restWriteError(ctx, http.StatusUnsupportedMediaType, err)
+		return
+	}
+
+	options := &bodyDecoderOptions{
+		indices:     splitList(string(args.Peek("index"))),
+		timeColumns: make(map[string]bool),
+	}
+	for _, name := range splitList(string(args.Peek("time_columns"))) {
+		options.timeColumns[name] = true
+	}
+	continueOnError := false
+	if args.Has("continue_on_error") {
+		arg := string(args.Peek("continue_on_error"))
+		if arg == "" { // ?continue_on_error is the same as ?continue_on_error=true
+			arg = "true"
+		}
+		continueOnError, err = strconv.ParseBool(arg)
+		if err != nil {
+			s.restWriteError(ctx, http.StatusBadRequest, errors.Errorf("bad boolean value for \"continue_on_error\" - %q", arg))
+			return
+		}
+	}
+
+	if args.Has("batch_size") {
+		options.batchSize, err = args.GetUint("batch_size")
+		if err != nil {
+			s.restWriteError(ctx, http.StatusBadRequest, errors.Wrap(err, "bad batch_size"))
+			return
+		}
+	}
+
+	bodyStream := ctx.RequestBodyStream()
+	if bodyStream == nil {
+		bodyStream = bytes.NewReader(ctx.PostBody())
+	}
+
+	body, err := s.requestBodyReader(ctx, bodyStream)
+	if err != nil {
+		s.restWriteError(ctx, http.StatusUnsupportedMediaType, err)
+		return
+	}
+	defer body.Close()
+
+	dec, err := newBodyDecoder(format, body, options)
+	if err != nil {
+		s.restWriteError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	request := &frames.WriteRequest{
+		Backend:         route.backend,
+		Table:           route.table,
+		Expression:      string(args.Peek("expression")),
+		Condition:       string(args.Peek("condition")),
+		PartitionKeys:   splitList(string(args.Peek("partition_keys"))),
+		SaveMode:        saveMode,
+		ContinueOnError: continueOnError,
+		TimeZone:        string(args.Peek("time_zone")),
+		TimePrecision:   string(args.Peek("time_precision")),
+		TTL:             string(args.Peek("ttl")),
+		SchemaMode:      string(args.Peek("schema_mode")),
+	}
+	request.Session, request.Password, request.Token = s.restSession(ctx)
+
+	var decodeError error
+	next := func() (frames.Frame, error) {
+		frame, err := dec.Next()
+		if err != nil && err != io.EOF {
+			decodeError = err
+		}
+		return frame, err
+	}
+
+	result, err := s.api.WriteStream(request, next, nil)
+	if decodeError != nil {
+		s.logger.ErrorWith("decode error", "error", decodeError)
+		s.restWriteError(ctx, http.StatusBadRequest, decodeError)
+		return
+	}
+
+	if err != nil {
+		s.logger.ErrorWith("write error", "error", err)
+		s.restWriteError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	reply := map[string]interface{}{
+		"num_frames": result.Frames,
+		"num_rows":   result.Rows,
+	}
+	if len(result.FailedRows) > 0 {
+		reply["failed_rows"] = result.FailedRows
+	}
+	_ = s.replyJSON(ctx, reply)
+}
+
+func (s *Server) handleRESTCreate(ctx *fasthttp.RequestCtx, route *restRoute) {
+	requestInner := &pb.CreateRequest{}
+	if body := ctx.PostBody(); len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, requestInner); err != nil {
+			s.restError(ctx, http.StatusBadRequest, errors.Wrap(err, "bad request body"))
+			return
+		}
+	}
+
+	if err := queryToProto(ctx.QueryArgs(), requestInner); err != nil {
+		s.restError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	requestInner.Backend = route.backend
+	requestInner.Table = route.table
+
+	request := &frames.CreateRequest{Proto: requestInner}
+	requestInner.Session, request.Password, request.Token = s.restSession(ctx)
+
+	s.logger.InfoWith("REST create", "request", request)
+	if err := s.api.Create(request); err != nil {
+		s.restError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusCreated)
+	_ = s.replyJSON(ctx, map[string]interface{}{"backend": route.backend, "table": route.table})
+}
+
+// deleteRowSelectors are the delete request fields that select rows, they are
+// accepted only by the rows resource
+var deleteRowSelectors = []string{"filter", "start", "end", "metrics"}
+
+// handleRESTDelete deletes a table, or only the rows matching the request
+// when rowsOnly is set
+func (s *Server) handleRESTDelete(ctx *fasthttp.RequestCtx, route *restRoute, rowsOnly bool) {
+	requestInner := &pb.DeleteRequest{}
+	if err := queryToProto(ctx.QueryArgs(), requestInner); err != nil {
+		s.restError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	requestInner.Backend = route.backend
+	requestInner.Table = route.table
+
+	hasSelector := requestInner.Filter != "" || requestInner.Start != "" ||
+		requestInner.End != "" || len(requestInner.Metrics) > 0
+	if rowsOnly && !hasSelector {
+		s.restError(ctx, http.StatusBadRequest, fmt.Errorf("deleting rows requires filter, start, end or metrics"))
+		return
+	}
+	if !rowsOnly && hasSelector {
+		s.restError(ctx, http.StatusBadRequest, fmt.Errorf("use the rows resource to delete with filter, start, end or metrics"))
+		return
+	}
+
+	request := &frames.DeleteRequest{Proto: requestInner}
+	requestInner.Session, request.Password, request.Token = s.restSession(ctx)
+
+	if err := s.api.Delete(request); err != nil {
+		s.restError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.SetStatusCode(http.StatusNoContent)
+}
+
+func (s *Server) handleRESTExec(ctx *fasthttp.RequestCtx, route *restRoute) {
+	requestInner := &pb.ExecRequest{
+		Backend:    route.backend,
+		Table:      route.table,
+		Command:    route.command,
+		Expression: string(ctx.QueryArgs().Peek("expression")),
+	}
+
+	if body := ctx.PostBody(); len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &requestInner.Args); err != nil {
+			s.restError(ctx, http.StatusBadRequest, errors.Wrap(err, "body should be a JSON object of arguments"))
+			return
+		}
+	}
+
+	request := &frames.ExecRequest{Proto: requestInner}
+	requestInner.Session, request.Password, request.Token = s.restSession(ctx)
+
+	frame, err := s.api.Exec(request)
+	if err != nil {
+		s.restError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	_, _ = w.WriteString(`{"rows":[`)
+	if frame != nil {
+		first := true
+		if err := writeJSONRows(w, frame, &first); err != nil {
+			s.restError(ctx, http.StatusInternalServerError, errors.Wrap(err, "can't encode rows"))
+			return
+		}
+	}
+	_, _ = w.WriteString("]}\n")
+	_ = w.Flush()
+
+	ctx.Response.Header.SetContentType("application/json")
+	ctx.SetBody(buf.Bytes())
+}
+
+// restWriteError replies with a write error, the rest of the request body is
+// not read so the connection isn't reused
+func (s *Server) restWriteError(ctx *fasthttp.RequestCtx, status int, err error) {
+	ctx.SetConnectionClose()
+	s.restError(ctx, status, err)
+}
+
+func (s *Server) restError(ctx *fasthttp.RequestCtx, status int, err error) {
+	ctx.Response.Header.SetContentType("application/json")
+	ctx.SetStatusCode(status)
+	if err := json.NewEncoder(ctx).Encode(map[string]string{"error": err.Error()}); err != nil {
+		s.logger.ErrorWith("can't encode JSON", "error", err)
+	}
+}
+
+// writeJSONRows writes frame rows (including index columns) as JSON objects,
+// first is used to place separators between rows across frames
+func writeJSONRows(w *bufio.Writer, frame frames.Frame, first *bool) error {
+	iter := frame.IterRows(true)
+	for iter.Next() {
+		row := iter.Row()
+		for name, value := range row {
+			row[name] = jsonSafeValue(value)
+			if frame.IsNull(iter.RowNum(), name) {
+				row[name] = nil
+			}
+		}
+
+		if !*first {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		*first = false
+
+		// Marshal (unlike Encoder) doesn't add a newline, which will break
+		// Server-Sent Events data lines
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+// jsonSafeValue replaces values that can't be encoded to JSON (NaN, ±Inf)
+func jsonSafeValue(value interface{}) interface{} {
+	if f, ok := value.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return nil
+	}
+
+	return value
+}
+
+// jsonToGoValue converts json.Number to int64 or float64
+func jsonToGoValue(value interface{}) interface{} {
+	num, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+
+	if i, err := num.Int64(); err == nil {
+		return i
+	}
+
+	f, _ := num.Float64()
+	return f
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/v3io/frames/pb"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseRESTPath(t *testing.T) {
+	testCases := []struct {
+		path  string
+		route restRoute
+	}{
+		{"kv/tables/t1", restRoute{backend: "kv", table: "t1"}},
+		{"kv/tables/t1/rows", restRoute{backend: "kv", table: "t1", resource: "rows"}},
+		{"kv/tables/dir/t1/rows", restRoute{backend: "kv", table: "dir/t1", resource: "rows"}},
+		{"kv/tables/dir%2Ft1/rows", restRoute{backend: "kv", table: "dir/t1", resource: "rows"}},
+		{"stream/tables/s1/tail", restRoute{backend: "stream", table: "s1", resource: "tail"}},
+		{"kv/tables/t1/exec/infer", restRoute{backend: "kv", table: "t1", resource: "exec", command: "infer"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			route, err := parseRESTPath(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *route != tc.route {
+				t.Fatalf("bad route: %+v != %+v", *route, tc.route)
+			}
+		})
+	}
+
+	for _, path := range []string{"kv", "kv/tables", "kv/rows/t1", "tables/t1"} {
+		if _, err := parseRESTPath(path); err == nil {
+			t.Fatalf("%q: no error", path)
+		}
+	}
+}
+
+func TestQueryToProto(t *testing.T) {
+	args := &fasthttp.Args{}
+	args.Parse("columns=a,b&filter=x>2&limit=10&reset_index&segments=1,3&table=ignored")
+
+	request := &pb.ReadRequest{}
+	if err := queryToProto(args, request); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &pb.ReadRequest{
+		Columns:    []string{"a", "b"},
+		Filter:     "x>2",
+		Limit:      10,
+		ResetIndex: true,
+		Segments:   []int64{1, 3},
+	}
+
+	if !reflect.DeepEqual(request, expected) {
+		t.Fatalf("bad request: %+v != %+v", request, expected)
+	}
+
+	deleteRequest := &pb.DeleteRequest{}
+	args.Parse("if_missing=ignore")
+	if err := queryToProto(args, deleteRequest); err != nil {
+		t.Fatal(err)
+	}
+
+	if deleteRequest.IfMissing != pb.ErrorOptions_IGNORE {
+		t.Fatalf("bad if_missing - %v", deleteRequest.IfMissing)
+	}
+
+	args.Parse("limit=ten")
+	if err := queryToProto(args, request); err == nil {
+		t.Fatal("no error on bad integer")
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	data, err := newOpenAPIDocument("1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Info struct {
+			Version string
+		}
+		Paths map[string]map[string]interface{}
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Info.Version != "1.2.3" {
+		t.Fatalf("bad version - %q", doc.Info.Version)
+	}
+
+	rows, ok := doc.Paths[restPrefix+"{backend}/tables/{table}/rows"]
+	if !ok {
+		t.Fatal("rows path missing")
+	}
+
+	for _, method := range []string{"get", "put", "delete"} {
+		if _, ok := rows[method]; !ok {
+			t.Fatalf("rows %s missing", method)
+		}
+	}
+
+	// Row selectors are rejected by table deletes
+	paramNames := func(operation interface{}) map[string]bool {
+		names := make(map[string]bool)
+		for _, param := range operation.(map[string]interface{})["parameters"].([]interface{}) {
+			names[param.(map[string]interface{})["name"].(string)] = true
+		}
+		return names
+	}
+
+	tableParams := paramNames(doc.Paths[restPrefix+"{backend}/tables/{table}"]["delete"])
+	rowsParams := paramNames(rows["delete"])
+	for _, name := range deleteRowSelectors {
+		if tableParams[name] {
+			t.Fatalf("table delete has %s parameter", name)
+		}
+		if !rowsParams[name] {
+			t.Fatalf("rows delete is missing %s parameter", name)
+		}
+	}
+	if !tableParams["if_missing"] {
+		t.Fatal("table delete is missing if_missing parameter")
+	}
+}
@@ -27,8 +27,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/nuclio/logger"
 	"github.com/pkg/errors"
@@ -59,10 +63,14 @@ type Server struct {
 	api     *api.API
 	logger  logger.Logger
 	version string
+	openAPI []byte // OpenAPI description of the REST API
+
+	rateLimiter *rateLimiter // nil if rate limit is disabled
 }
 
-// NewServer creates a new server
-func NewServer(config *frames.Config, addr string, logger logger.Logger, historyServer *utils.HistoryServer, version string) (*Server, error) {
+// NewServer creates a new server, options are passed to the API (e.g.
+// api.WithV3ioContext)
+func NewServer(config *frames.Config, addr string, logger logger.Logger, historyServer *utils.HistoryServer, version string, options ...api.Option) (*Server, error) {
 	var err error
 
 	if err := config.Validate(); err != nil {
@@ -80,7 +88,7 @@ func NewServer(config *frames.Config, addr string, logger logger.Logger, history
 		}
 	}
 
-	api, err := api.New(logger, config, historyServer)
+	api, err := api.New(logger, config, historyServer, options...)
 	if err != nil {
 		return nil, errors.Wrap(err, "can't create API")
 	}
@@ -93,6 +101,13 @@ func NewServer(config *frames.Config, addr string, logger logger.Logger, history
 		logger:  logger,
 		api:     api,
 		version: version,
+
+		rateLimiter: newRateLimiter(config.HTTP.RateLimit),
+	}
+
+	srv.openAPI, err = newOpenAPIDocument(version)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't create OpenAPI document")
 	}
 
 	srv.initRoutes()
@@ -100,21 +115,41 @@ func NewServer(config *frames.Config, addr string, logger logger.Logger, history
 	return srv, nil
 }
 
-// Start starts the server
+// Start starts the server, the address is host:port or unix:///path/to/socket
 func (s *Server) Start() error {
 	if state := s.State(); state != frames.ReadyState {
 		s.logger.ErrorWith("start from bad state", "state", state)
 		return fmt.Errorf("bad state - %s", state)
 	}
 
+	httpConfig := s.config.HTTP
 	s.server = &fasthttp.Server{
-		Handler: s.handler,
-		// TODO: Configuration?
-		MaxRequestBodySize: 8 * (1 << 30), // 8GB
+		Handler:            s.handler,
+		MaxRequestBodySize: httpConfig.MaxRequestBodySize,
+		ReadTimeout:        time.Duration(httpConfig.ReadTimeoutSeconds) * time.Second,
+		WriteTimeout:       time.Duration(httpConfig.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:        time.Duration(httpConfig.IdleTimeoutSeconds) * time.Second,
+		MaxConnsPerIP:      httpConfig.MaxConnsPerIP,
+		Concurrency:        httpConfig.Concurrency,
+		// Let write handlers decode the body while it's being received, other
+		// handlers read it whole (see readBody)
+		StreamRequestBody: true,
+	}
+
+	mode, err := s.config.SocketMode()
+	if err != nil {
+		s.SetError(err)
+		return err
+	}
+
+	lis, err := frames.Listen(s.address, mode)
+	if err != nil {
+		s.SetError(err)
+		return err
 	}
 
 	go func() {
-		err := s.server.ListenAndServe(s.address)
+		err := s.server.Serve(lis)
 		if err != nil {
 			s.logger.ErrorWith("error running HTTP server", "error", err)
 			s.SetError(err)
@@ -130,7 +165,35 @@ func (s *Server) handler(ctx *fasthttp.RequestCtx) {
 	// Avoid something like a double slash causing a misroute to status due to the fact that ctx.URI() and ctx.Path()
 	// translate a path like //read to /, which in turn causes the plaintext status being returned to a client that is
 	// expecteing a binary response (which currently results in a Python MemoryError on the client side).
-	canonicalPath := path.Clean(string(ctx.Request.Header.RequestURI()))
+	requestPath := string(ctx.Request.Header.RequestURI())
+	if i := strings.IndexByte(requestPath, '?'); i >= 0 {
+		requestPath = requestPath[:i]
+	}
+	canonicalPath := path.Clean(requestPath)
+
+	// Internal routes (status, config, metrics) are not rate limited
+	if s.rateLimiter != nil && !strings.HasPrefix(canonicalPath, "/_/") {
+		allowed, wait := s.rateLimiter.allow(s.rateLimitKey(ctx), time.Now())
+		if !allowed {
+			ctx.Response.Header.Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			ctx.Error("rate limit exceeded", http.StatusTooManyRequests)
+			// The request body is streamed and not read, don't reuse the connection
+			if ctx.Request.Header.ContentLength() != 0 {
+				ctx.SetConnectionClose()
+			}
+			return
+		}
+	}
+
+	if !isWriteRoute(ctx, canonicalPath) && !s.readBody(ctx) {
+		return
+	}
+
+	if strings.HasPrefix(canonicalPath, restPrefix) {
+		s.handleREST(ctx, canonicalPath)
+		return
+	}
+
 	fn, ok := s.routes[canonicalPath]
 	if !ok {
 		ctx.Error(fmt.Sprintf("unknown path - %q", string(ctx.Path())), http.StatusNotFound)
@@ -140,6 +203,52 @@ func (s *Server) handler(ctx *fasthttp.RequestCtx) {
 	fn(ctx)
 }
 
+// isWriteRoute returns true if the request is handled by a write handler,
+// which decodes the body while it's being received
+func isWriteRoute(ctx *fasthttp.RequestCtx, path string) bool {
+	if path == "/write" {
+		return true
+	}
+
+	return strings.HasPrefix(path, restPrefix) && strings.HasSuffix(path, "/rows") && ctx.IsPut()
+}
+
+// readBody reads the streamed request body, up to MaxRequestBodySize. It
+// replies with an error and returns false if the body can't be read or is
+// too large.
+func (s *Server) readBody(ctx *fasthttp.RequestCtx) bool {
+	stream := ctx.RequestBodyStream()
+	if stream == nil {
+		return true
+	}
+
+	maxSize := s.config.HTTP.MaxRequestBodySize
+	if maxSize <= 0 {
+		maxSize = fasthttp.DefaultMaxRequestBodySize
+	}
+
+	if ctx.Request.Header.ContentLength() > maxSize {
+		ctx.Error("request body too large", http.StatusRequestEntityTooLarge)
+		ctx.SetConnectionClose()
+		return false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(stream, int64(maxSize)+1))
+	if err != nil {
+		ctx.Error(fmt.Sprintf("can't read request body - %s", err), http.StatusBadRequest)
+		ctx.SetConnectionClose()
+		return false
+	}
+	if len(body) > maxSize {
+		ctx.Error("request body too large", http.StatusRequestEntityTooLarge)
+		ctx.SetConnectionClose()
+		return false
+	}
+
+	ctx.Request.SetBody(body)
+	return true
+}
+
 func (s *Server) handleStatus(ctx *fasthttp.RequestCtx) {
 	status := map[string]interface{}{
 		"state": s.State(),
@@ -185,8 +294,30 @@ func (s *Server) handleRead(ctx *fasthttp.RequestCtx) {
 		}
 	}()
 
+	s.streamFrames(ctx, ch, &apiError)
+}
+
+// streamFrames streams frames from ch as the response body, compressed
+// according to the request Accept-Encoding. apiError is checked after ch is
+// closed.
+func (s *Server) streamFrames(ctx *fasthttp.RequestCtx, ch chan frames.Frame, apiError *error) {
+	compression := s.responseCompression(ctx)
 	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
-		enc := frames.NewEncoder(w)
+		cw, err := frames.NewCompressWriter(compression, w)
+		if err != nil { // Can't happen, compression was negotiated
+			s.logger.ErrorWith("can't create compressor", "error", err)
+			for range ch {
+			}
+			return
+		}
+
+		defer func() {
+			if err := cw.Close(); err != nil {
+				s.logger.ErrorWith("can't close compressor", "error", err)
+			}
+		}()
+
+		enc := frames.NewEncoder(cw)
 		for frame := range ch {
 			iface, ok := frame.(pb.Framed)
 			if !ok {
@@ -199,18 +330,50 @@ func (s *Server) handleRead(ctx *fasthttp.RequestCtx) {
 				s.writeError(enc, err)
 			}
 
+			if err := cw.Flush(); err != nil {
+				s.logger.ErrorWith("can't flush compressor", "error", err)
+				s.writeError(enc, err)
+			}
+
 			if err := w.Flush(); err != nil {
 				s.logger.ErrorWith("can't flush", "error", err)
 				s.writeError(enc, err)
 			}
 		}
 
-		if apiError != nil {
-			s.writeError(enc, apiError)
+		if *apiError != nil {
+			s.writeError(enc, *apiError)
 		}
 	})
 }
 
+// responseCompression negotiates the response compression and sets the
+// Content-Encoding header accordingly
+func (s *Server) responseCompression(ctx *fasthttp.RequestCtx) string {
+	if s.config.HTTP.DisableCompression {
+		return frames.NoCompression
+	}
+
+	compression := frames.NegotiateCompression(string(ctx.Request.Header.Peek("Accept-Encoding")))
+	if compression != frames.NoCompression {
+		ctx.Response.Header.Set("Content-Encoding", compression)
+		ctx.Response.Header.Add("Vary", "Accept-Encoding")
+	}
+
+	return compression
+}
+
+// requestBodyReader returns the request body, decompressed according to
+// Content-Encoding
+func (s *Server) requestBodyReader(ctx *fasthttp.RequestCtx, body io.Reader) (io.ReadCloser, error) {
+	encoding := strings.ToLower(strings.TrimSpace(string(ctx.Request.Header.Peek("Content-Encoding"))))
+	if encoding == "identity" {
+		encoding = frames.NoCompression
+	}
+
+	return frames.NewDecompressReader(encoding, body)
+}
+
 func (s *Server) writeError(enc *frames.Encoder, err error) {
 	msg := &pb.Frame{
 		Error: err.Error(),
@@ -224,15 +387,31 @@ func (s *Server) handleWrite(ctx *fasthttp.RequestCtx) {
 	}
 
 	reader, writer := io.Pipe()
+	bodyDone := make(chan struct{})
 	go func() {
+		defer close(bodyDone)
 		err := ctx.Request.BodyWriteTo(writer)
-		if err != nil {
+		if err != nil && err != io.ErrClosedPipe {
 			s.logger.ErrorWith("Failed to write request body", "error", err)
 		}
 		_ = writer.Close()
 	}()
+	// The request is released after we return, stop reading it before that
+	defer func() {
+		_ = reader.Close()
+		<-bodyDone
+	}()
+
+	body, err := s.requestBodyReader(ctx, reader)
+	if err != nil {
+		_ = reader.Close()
+		s.logger.ErrorWith("bad content encoding", "error", err)
+		ctx.Error(err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	defer body.Close()
 
-	dec := frames.NewDecoder(reader)
+	dec := frames.NewDecoder(body)
 
 	// First message is the write reqeust
 	req := &pb.InitialWriteRequest{}
@@ -256,15 +435,20 @@ func (s *Server) handleWrite(ctx *fasthttp.RequestCtx) {
 	}
 
 	request := &frames.WriteRequest{
-		Session:       req.Session,
-		Backend:       req.Backend,
-		Table:         req.Table,
-		ImmidiateData: frame,
-		Condition:     req.Condition,
-		Expression:    req.Expression,
-		HaveMore:      req.More,
-		SaveMode:      saveMode,
-		PartitionKeys: req.PartitionKeys,
+		Session:         req.Session,
+		Backend:         req.Backend,
+		Table:           req.Table,
+		ImmidiateData:   frame,
+		Condition:       req.Condition,
+		Expression:      req.Expression,
+		HaveMore:        req.More,
+		SaveMode:        saveMode,
+		PartitionKeys:   req.PartitionKeys,
+		ContinueOnError: req.ContinueOnError,
+		TimeZone:        req.TimeZone,
+		TimePrecision:   req.TimePrecision,
+		TTL:             req.Ttl,
+		SchemaMode:      req.SchemaMode,
 	}
 
 	s.httpAuth(ctx, request.Session)
@@ -273,47 +457,59 @@ func (s *Server) handleWrite(ctx *fasthttp.RequestCtx) {
 	req.Session.Password = ""
 	req.Session.Token = ""
 
-	var nFrames, nRows int
-	var writeError error
-
-	ch := make(chan frames.Frame, 1)
-	done := make(chan bool)
-	go func() {
-		defer close(done)
-		nFrames, nRows, writeError = s.api.Write(request, ch)
-	}()
-
-	for writeError == nil {
+	var decodeError error
+	next := func() (frames.Frame, error) {
 		msg := &pb.Frame{}
-		err := dec.Decode(msg)
-		if err != nil {
+		if err := dec.Decode(msg); err != nil {
 			if err != io.EOF {
-				s.logger.ErrorWith("decode error", "error", err)
-				ctx.Error("decode error", http.StatusInternalServerError)
+				decodeError = err
 			}
-			break
+			return nil, err
 		}
 
-		ch <- frames.NewFrameFromProto(msg)
+		return frames.NewFrameFromProto(msg), nil
 	}
 
-	close(ch)
-	<-done
+	result, err := s.api.WriteStream(request, next, nil)
+	if decodeError != nil {
+		s.logger.ErrorWith("decode error", "error", decodeError)
+		ctx.Error("decode error", http.StatusInternalServerError)
+		return
+	}
 
-	// We can't handle writeError right after .Write since it's done in a goroutine
-	if writeError != nil {
-		s.logger.ErrorWith("write error", "error", writeError)
-		ctx.Error("write error: "+writeError.Error(), http.StatusInternalServerError)
+	if err != nil {
+		s.logger.ErrorWith("write error", "error", err)
+		ctx.Error("write error: "+err.Error(), http.StatusInternalServerError)
+		// The rest of the request body is not read, don't reuse the connection
+		ctx.SetConnectionClose()
 		return
 	}
 
 	reply := map[string]interface{}{
-		"num_frames": nFrames,
-		"num_rows":   nRows,
+		"num_frames": result.Frames,
+		"num_rows":   result.Rows,
+	}
+	if len(result.FailedRows) > 0 {
+		reply["failed_rows"] = result.FailedRows
 	}
 	_ = s.replyJSON(ctx, reply)
 }
 
+// decodeExecFrame decodes a base64 encoded exec request frame
+func decodeExecFrame(data string) (*pb.Frame, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "bad base64 encoding of frame")
+	}
+
+	frame, err := frames.UnmarshalFrame(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "bad frame")
+	}
+
+	return frame.(pb.Framed).Proto(), nil
+}
+
 func (s *Server) handleCreate(ctx *fasthttp.RequestCtx) {
 	if !ctx.IsPost() { // ctx.PostBody() blocks on GET
 		ctx.Error("unsupported method", http.StatusMethodNotAllowed)
@@ -377,6 +573,18 @@ func (s *Server) handleDelete(ctx *fasthttp.RequestCtx) {
 	s.replyOK(ctx)
 }
 
+func (s *Server) handleMetrics(ctx *fasthttp.RequestCtx) {
+	metrics := map[string]interface{}{
+		"open_connections": s.server.GetOpenConnectionsCount(),
+	}
+
+	if s.rateLimiter != nil {
+		metrics["rate_limit"] = s.rateLimiter.stats()
+	}
+
+	_ = s.replyJSON(ctx, metrics)
+}
+
 func (s *Server) handleConfig(ctx *fasthttp.RequestCtx) {
 	_ = s.replyJSON(ctx, s.config)
 }
@@ -403,11 +611,26 @@ func (s *Server) handleExec(ctx *fasthttp.RequestCtx) {
 	}
 
 	requestInner := &pb.ExecRequest{}
-	if err := json.Unmarshal(ctx.PostBody(), requestInner); err != nil {
+	body := struct {
+		*pb.ExecRequest
+		Frame string `json:"frame"` // base64 encoded (see frames.MarshalFrame)
+	}{ExecRequest: requestInner}
+	if err := json.Unmarshal(ctx.PostBody(), &body); err != nil {
 		s.logger.ErrorWith("can't decode request", "error", err)
 		ctx.Error(fmt.Sprintf("bad request - %s", err), http.StatusBadRequest)
 		return
 	}
+
+	if body.Frame != "" {
+		frame, err := decodeExecFrame(body.Frame)
+		if err != nil {
+			s.logger.ErrorWith("can't decode frame", "error", err)
+			ctx.Error(fmt.Sprintf("bad request - %s", err), http.StatusBadRequest)
+			return
+		}
+		requestInner.Frame = frame
+	}
+
 	request := &frames.ExecRequest{
 		Proto: requestInner,
 	}
@@ -442,6 +665,62 @@ func (s *Server) handleExec(ctx *fasthttp.RequestCtx) {
 	})
 }
 
+// batchWriteBody is the JSON body of a batch write, frames are base64 encoded
+// (see frames.MarshalFrame)
+type batchWriteBody struct {
+	*pb.BatchWriteRequest
+	Tables []*batchWriteTableBody `json:"tables"`
+}
+
+type batchWriteTableBody struct {
+	Table         string   `json:"table"`
+	Frame         string   `json:"frame,omitempty"`
+	PartitionKeys []string `json:"partition_keys,omitempty"`
+}
+
+func (s *Server) handleBatchWrite(ctx *fasthttp.RequestCtx) {
+	if !ctx.IsPost() { // ctx.PostBody() blocks on GET
+		ctx.Error("unsupported method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requestInner := &pb.BatchWriteRequest{}
+	body := &batchWriteBody{BatchWriteRequest: requestInner}
+	if err := json.Unmarshal(ctx.PostBody(), body); err != nil {
+		s.logger.ErrorWith("can't decode request", "error", err)
+		ctx.Error(fmt.Sprintf("bad request - %s", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, table := range body.Tables {
+		tableInner := &pb.BatchWriteTable{Table: table.Table, PartitionKeys: table.PartitionKeys}
+		if table.Frame != "" {
+			frame, err := decodeExecFrame(table.Frame)
+			if err != nil {
+				s.logger.ErrorWith("can't decode frame", "error", err, "table", table.Table)
+				ctx.Error(fmt.Sprintf("bad request - table '%s': %s", table.Table, err), http.StatusBadRequest)
+				return
+			}
+			tableInner.Frame = frame
+		}
+		requestInner.Tables = append(requestInner.Tables, tableInner)
+	}
+
+	if requestInner.Session == nil {
+		requestInner.Session = &frames.Session{}
+	}
+	s.httpAuth(ctx, requestInner.Session)
+
+	result, err := s.api.BatchWrite(frames.BatchWriteRequestFromProto(requestInner))
+	if result == nil {
+		ctx.Error(fmt.Sprintf("can't batch write - %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Aborted batches are replied with the table results and the error
+	_ = s.replyJSON(ctx, result.Proto(err))
+}
+
 func (s *Server) handleVersion(ctx *fasthttp.RequestCtx) {
 	if !ctx.IsPost() { // ctx.PostBody() blocks on GET
 		ctx.Error("unsupported method", http.StatusMethodNotAllowed)
@@ -542,11 +821,13 @@ func (s *Server) handleHistory(ctx *fasthttp.RequestCtx) {
 		Proto: requestInner,
 	}
 
-	s.httpAuth(ctx, request.Proto.Session)
-	request.Password = frames.InitSecretString(request.Proto.Session.Password)
-	request.Token = frames.InitSecretString(request.Proto.Session.Token)
-	request.Proto.Session.Password = ""
-	request.Proto.Session.Token = ""
+	if requestInner.Session != nil {
+		s.httpAuth(ctx, requestInner.Session)
+		request.Password = frames.InitSecretString(requestInner.Session.Password)
+		request.Token = frames.InitSecretString(requestInner.Session.Token)
+		requestInner.Session.Password = ""
+		requestInner.Session.Token = ""
+	}
 
 	ch := make(chan frames.Frame)
 	var apiError error
@@ -558,30 +839,7 @@ func (s *Server) handleHistory(ctx *fasthttp.RequestCtx) {
 		}
 	}()
 
-	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
-		enc := frames.NewEncoder(w)
-		for frame := range ch {
-			iface, ok := frame.(pb.Framed)
-			if !ok {
-				s.logger.Error("unknown frame type")
-				s.writeError(enc, fmt.Errorf("unknown frame type"))
-			}
-
-			if err := enc.Encode(iface.Proto()); err != nil {
-				s.logger.ErrorWith("can't encode result", "error", err)
-				s.writeError(enc, err)
-			}
-
-			if err := w.Flush(); err != nil {
-				s.logger.ErrorWith("can't flush", "error", err)
-				s.writeError(enc, err)
-			}
-		}
-
-		if apiError != nil {
-			s.writeError(enc, apiError)
-		}
-	})
+	s.streamFrames(ctx, ch, &apiError)
 }
 
 // based on https://github.com/buaazp/fasthttprouter/tree/master/examples/auth
@@ -632,17 +890,19 @@ func (s *Server) parseBearerAuth(auth []byte, session *frames.Session) {
 
 func (s *Server) initRoutes() {
 	s.routes = map[string]func(*fasthttp.RequestCtx){
-		"/_/config": s.handleConfig,
-		"/_/status": s.handleStatus,
-		"/create":   s.handleCreate,
-		"/delete":   s.handleDelete,
-		"/read":     s.handleRead,
-		"/write":    s.handleWrite,
-		"/exec":     s.handleExec,
-		"/history":  s.handleHistory,
-		"/":         s.handleStatus,
-		"/query":    s.handleSimpleJSONQuery,
-		"/search":   s.handleSimpleJSONSearch,
-		"/version":  s.handleVersion,
+		"/_/config":    s.handleConfig,
+		"/_/metrics":   s.handleMetrics,
+		"/_/status":    s.handleStatus,
+		"/create":      s.handleCreate,
+		"/delete":      s.handleDelete,
+		"/read":        s.handleRead,
+		"/write":       s.handleWrite,
+		"/batch_write": s.handleBatchWrite,
+		"/exec":        s.handleExec,
+		"/history":     s.handleHistory,
+		"/":            s.handleStatus,
+		"/query":       s.handleSimpleJSONQuery,
+		"/search":      s.handleSimpleJSONSearch,
+		"/version":     s.handleVersion,
 	}
 }
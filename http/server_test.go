@@ -23,6 +23,7 @@ package http
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/v3io/frames"
@@ -98,3 +99,86 @@ func TestBearerAuth(t *testing.T) {
 		t.Fatalf("bad token: %q != %q", session.Token, token)
 	}
 }
+
+func TestRateLimit(t *testing.T) {
+	srv, err := createServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.rateLimiter = newRateLimiter(frames.RateLimitConfig{RequestsPerSecond: 1, Burst: 1})
+
+	statusCode := func(uri string) int {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI(uri)
+		srv.handler(ctx)
+		return ctx.Response.StatusCode()
+	}
+
+	if code := statusCode("/"); code != http.StatusOK {
+		t.Fatalf("first request failed - %d", code)
+	}
+
+	if code := statusCode("/"); code != http.StatusTooManyRequests {
+		t.Fatalf("request over limit not rejected - %d", code)
+	}
+
+	if code := statusCode("/_/status"); code != http.StatusOK {
+		t.Fatalf("internal route rate limited - %d", code)
+	}
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/write")
+	ctx.Request.SetBodyString("data")
+	ctx.Request.Header.SetContentLength(4)
+	srv.handler(ctx)
+	if ctx.Response.StatusCode() != http.StatusTooManyRequests || !ctx.Response.ConnectionClose() {
+		t.Fatalf("connection of rejected request with a body not closed - %d", ctx.Response.StatusCode())
+	}
+}
+
+func TestRESTWriteErrorConnectionClose(t *testing.T) {
+	srv, err := createServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The write body is streamed, it's not read on errors
+	for _, uri := range []string{"/v1/weather/tables/t1/rows?save_mode=bad", "/v1/weather/rows"} {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(http.MethodPut)
+		ctx.Request.SetRequestURI(uri)
+		ctx.Request.SetBodyString("a,b\n1,2\n")
+		srv.handler(ctx)
+
+		if ctx.Response.StatusCode() < http.StatusBadRequest {
+			t.Fatalf("%s: no error - %d", uri, ctx.Response.StatusCode())
+		}
+		if !ctx.Response.ConnectionClose() {
+			t.Fatalf("%s: connection not closed after error", uri)
+		}
+	}
+}
+
+func TestReadBodyTooLarge(t *testing.T) {
+	srv, err := createServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.config.HTTP.MaxRequestBodySize = 4
+
+	// Known and unknown (chunked) length
+	for _, size := range []int{10, -1} {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(http.MethodPost)
+		ctx.Request.SetRequestURI("/create")
+		ctx.Request.SetBodyStream(strings.NewReader("0123456789"), size)
+		srv.handler(ctx)
+
+		if ctx.Response.StatusCode() != http.StatusRequestEntityTooLarge {
+			t.Fatalf("%d: bad status - %d", size, ctx.Response.StatusCode())
+		}
+		// The rest of the body is not read
+		if !ctx.Response.ConnectionClose() {
+			t.Fatalf("%d: connection not closed", size)
+		}
+	}
+}
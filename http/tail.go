@@ -0,0 +1,500 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+	tsdbutils "github.com/v3io/v3io-tsdb/pkg/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// Live tail (GET {backend}/tables/{table}/tail) keeps polling a stream shard
+// or a TSDB table and pushes new rows as Server-Sent Events:
+//
+//	id: <cursor>
+//	event: rows
+//	data: {"rows":[...]}
+//
+// The event id is the last stream sequence number or the last TSDB time (in
+// milliseconds) sent. Clients reconnecting with a Last-Event-ID header resume
+// after it. TSDB rows are read by series, so events sent in the middle of a
+// poll have an id of <last time>:<poll end>:<rows of the poll sent>, resuming
+// from it reads the poll range again and skips the rows that were sent.
+//
+// Backpressure: the server polls only after the previous event was written,
+// so a slow client slows down polling and nothing is buffered on the server.
+// Clients control batching with batch_size (rows per event) and max_wait.
+
+const (
+	defaultTailPollInterval = time.Second
+	minTailPollInterval     = 100 * time.Millisecond
+	tailHeartbeatInterval   = 15 * time.Second
+)
+
+// tailCursor advances read requests between polls
+type tailCursor interface {
+	// next returns the read request of the next poll
+	next(now time.Time) *pb.ReadRequest
+	// observe is called with every non-empty frame read, it returns the frame
+	// to send (nil if there's nothing to send)
+	observe(frame frames.Frame) frames.Frame
+	// done is called after a successful poll
+	done()
+	// id returns the current position (SSE event id)
+	id() string
+	// resume sets the position from a previous event id
+	resume(id string) error
+}
+
+// streamTailCursor reads a stream shard, continuing from the last sequence
+// number seen
+type streamTailCursor struct {
+	request *pb.ReadRequest
+	lastSeq int64
+}
+
+func newStreamTailCursor(request *pb.ReadRequest, now time.Time) (*streamTailCursor, error) {
+	if request.ShardId == "" {
+		return nil, fmt.Errorf("missing shard_id")
+	}
+
+	switch strings.ToLower(request.Seek) {
+	case "", "latest", "late":
+		// Seeking to latest in every poll will miss records that arrive
+		// between polls, start from the tail start time instead
+		request.Seek = "time"
+		request.Start = strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)
+	}
+
+	return &streamTailCursor{request: request}, nil
+}
+
+func (c *streamTailCursor) next(now time.Time) *pb.ReadRequest {
+	request := *c.request
+	if c.lastSeq > 0 {
+		request.Seek = "seq"
+		request.Sequence = c.lastSeq + 1
+		request.Start = ""
+	}
+
+	return &request
+}
+
+func (c *streamTailCursor) observe(frame frames.Frame) frames.Frame {
+	if seq, ok := frame.Labels()["last_seq"].(int64); ok && seq > c.lastSeq {
+		c.lastSeq = seq
+	}
+	return frame
+}
+
+func (c *streamTailCursor) done() {}
+
+func (c *streamTailCursor) id() string {
+	if c.lastSeq == 0 {
+		return ""
+	}
+	return strconv.FormatInt(c.lastSeq, 10)
+}
+
+func (c *streamTailCursor) resume(id string) error {
+	seq, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return errors.Errorf("bad event id - %q", id)
+	}
+
+	c.lastSeq = seq
+	return nil
+}
+
+// tsdbTailCursor reads consecutive time ranges from a TSDB table. Samples
+// arriving with a time before the current range are not sent.
+type tsdbTailCursor struct {
+	request  *pb.ReadRequest
+	from     int64 // milliseconds
+	to       int64 // end of the current poll
+	rows     int   // rows of the current poll observed
+	resumeTo int64 // end of the poll to read again after resume, 0 if none
+	skip     int   // rows of the current poll to skip (sent before resume)
+}
+
+func newTSDBTailCursor(request *pb.ReadRequest, now time.Time) (*tsdbTailCursor, error) {
+	from := now.UnixNano() / int64(time.Millisecond)
+	if request.Start != "" {
+		var err error
+		from, err = tsdbutils.Str2unixTime(request.Start)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad start")
+		}
+	}
+
+	return &tsdbTailCursor{request: request, from: from}, nil
+}
+
+func (c *tsdbTailCursor) next(now time.Time) *pb.ReadRequest {
+	c.to = now.UnixNano() / int64(time.Millisecond)
+	if c.resumeTo != 0 {
+		c.to, c.resumeTo = c.resumeTo, 0
+	}
+	c.rows = 0
+	request := *c.request
+	request.Start = strconv.FormatInt(c.from, 10)
+	request.End = strconv.FormatInt(c.to, 10)
+	return &request
+}
+
+func (c *tsdbTailCursor) observe(frame frames.Frame) frames.Frame {
+	c.rows += frame.Len()
+	if c.skip == 0 {
+		return frame
+	}
+
+	if c.skip >= frame.Len() {
+		c.skip -= frame.Len()
+		return nil
+	}
+
+	// Can't fail, the slice is in the frame
+	frame, _ = frame.Slice(c.skip, frame.Len())
+	c.skip = 0
+	return frame
+}
+
+func (c *tsdbTailCursor) done() {
+	if c.to >= c.from {
+		c.from = c.to + 1
+	}
+	c.rows, c.skip = 0, 0
+}
+
+func (c *tsdbTailCursor) id() string {
+	if c.rows > 0 {
+		return fmt.Sprintf("%d:%d:%d", c.from-1, c.to, c.rows)
+	}
+	return strconv.FormatInt(c.from-1, 10)
+}
+
+func (c *tsdbTailCursor) resume(id string) error {
+	fields := strings.Split(id, ":")
+	if len(fields) != 1 && len(fields) != 3 {
+		return errors.Errorf("bad event id - %q", id)
+	}
+
+	last, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return errors.Errorf("bad event id - %q", id)
+	}
+
+	if len(fields) == 3 {
+		to, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || to <= last {
+			return errors.Errorf("bad event id - %q", id)
+		}
+		skip, err := strconv.Atoi(fields[2])
+		if err != nil || skip < 0 {
+			return errors.Errorf("bad event id - %q", id)
+		}
+		c.resumeTo, c.skip = to, skip
+	}
+
+	c.from = last + 1
+	return nil
+}
+
+// tailOptions are the client controlled tail options
+type tailOptions struct {
+	pollInterval time.Duration
+	batchSize    int           // maximal rows per event, 0 means event per poll
+	maxWait      time.Duration // maximal time rows wait for a full batch
+	duration     time.Duration // 0 means until client disconnects
+}
+
+func parseTailOptions(args *fasthttp.Args) (*tailOptions, error) {
+	options := &tailOptions{pollInterval: defaultTailPollInterval}
+
+	durations := []struct {
+		name  string
+		value *time.Duration
+	}{
+		{"poll_interval", &options.pollInterval},
+		{"max_wait", &options.maxWait},
+		{"duration", &options.duration},
+	}
+
+	for _, d := range durations {
+		if !args.Has(d.name) {
+			continue
+		}
+
+		value, err := time.ParseDuration(string(args.Peek(d.name)))
+		if err != nil || value < 0 {
+			return nil, errors.Errorf("bad duration for %q - %q", d.name, args.Peek(d.name))
+		}
+		*d.value = value
+	}
+
+	if options.pollInterval < minTailPollInterval {
+		return nil, errors.Errorf("poll_interval must be at least %s", minTailPollInterval)
+	}
+
+	if args.Has("batch_size") {
+		batchSize, err := strconv.Atoi(string(args.Peek("batch_size")))
+		if err != nil || batchSize < 0 {
+			return nil, errors.Errorf("bad batch_size - %q", args.Peek("batch_size"))
+		}
+		options.batchSize = batchSize
+	}
+
+	if options.maxWait == 0 {
+		options.maxWait = options.pollInterval
+	}
+
+	return options, nil
+}
+
+// tailer polls a cursor and writes events
+type tailer struct {
+	cursor  tailCursor
+	options *tailOptions
+	read    func(request *frames.ReadRequest, out chan frames.Frame) error
+	// newRequest wraps the proto request with credentials
+	newRequest func(*pb.ReadRequest) *frames.ReadRequest
+	now        func() time.Time
+	sleep      func(time.Duration)
+
+	pending     []frames.Frame
+	pendingRows int
+	firstRowAt  time.Time // arrival time of first pending row
+	lastWriteAt time.Time
+}
+
+// run polls until the client disconnects, an error or options.duration
+func (t *tailer) run(w *bufio.Writer) error {
+	start := t.now()
+	t.lastWriteAt = start
+
+	for {
+		if err := t.poll(w); err != nil {
+			if errors.Cause(err) == errTailWrite {
+				return err
+			}
+			return writeTailEvent(w, "error", "", map[string]string{"error": err.Error()})
+		}
+
+		now := t.now()
+		if t.pendingRows > 0 && (t.options.batchSize == 0 || now.Sub(t.firstRowAt) >= t.options.maxWait) {
+			if err := t.flush(w); err != nil {
+				return err
+			}
+		}
+
+		if t.options.duration > 0 && now.Sub(start) >= t.options.duration {
+			if err := t.flush(w); err != nil {
+				return err
+			}
+			return writeTailEvent(w, "end", t.cursor.id(), struct{}{})
+		}
+
+		// Heartbeat lets us detect disconnected clients when there's no data
+		if now.Sub(t.lastWriteAt) >= tailHeartbeatInterval {
+			if _, err := w.WriteString(": keep-alive\n\n"); err != nil {
+				return errTailWrite
+			}
+			if err := w.Flush(); err != nil {
+				return errTailWrite
+			}
+			t.lastWriteAt = now
+		}
+
+		t.sleep(t.options.pollInterval)
+	}
+}
+
+var errTailWrite = errors.New("can't write event")
+
+// poll reads new data, full batches are written while reading
+func (t *tailer) poll(w *bufio.Writer) error {
+	ch := make(chan frames.Frame)
+	errCh := make(chan error, 1)
+	request := t.newRequest(t.cursor.next(t.now()))
+	go func() {
+		defer close(ch)
+		errCh <- t.read(request, ch)
+	}()
+
+	var writeErr error
+	for frame := range ch {
+		if writeErr != nil || frame.Len() == 0 {
+			continue // drain channel
+		}
+
+		if frame = t.cursor.observe(frame); frame == nil {
+			continue
+		}
+		if t.pendingRows == 0 {
+			t.firstRowAt = t.now()
+		}
+		t.pending = append(t.pending, frame)
+		t.pendingRows += frame.Len()
+
+		if t.options.batchSize > 0 && t.pendingRows >= t.options.batchSize {
+			writeErr = t.flush(w)
+		}
+	}
+
+	if err := <-errCh; err != nil {
+		return err
+	}
+
+	if writeErr != nil {
+		return writeErr
+	}
+
+	t.cursor.done()
+	return nil
+}
+
+// flush writes pending rows as a single event
+func (t *tailer) flush(w *bufio.Writer) error {
+	if t.pendingRows == 0 {
+		return nil
+	}
+
+	first := true
+	fmt.Fprintf(w, "id: %s\nevent: rows\ndata: {\"rows\":[", t.cursor.id())
+	for _, frame := range t.pending {
+		if err := writeJSONRows(w, frame, &first); err != nil {
+			return err
+		}
+	}
+	_, _ = w.WriteString("]}\n\n")
+	t.pending, t.pendingRows = nil, 0
+
+	if err := w.Flush(); err != nil {
+		return errTailWrite
+	}
+
+	t.lastWriteAt = t.now()
+	return nil
+}
+
+func writeTailEvent(w *bufio.Writer, event string, id string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, body)
+	if err := w.Flush(); err != nil {
+		return errTailWrite
+	}
+
+	return nil
+}
+
+// backendType returns the type of a configured backend
+func (s *Server) backendType(name string) string {
+	for _, backend := range s.config.Backends {
+		if backend.Name == name {
+			return backend.Type
+		}
+	}
+
+	return ""
+}
+
+func (s *Server) handleRESTTail(ctx *fasthttp.RequestCtx, route *restRoute) {
+	requestInner := &pb.ReadRequest{}
+	if err := queryToProto(ctx.QueryArgs(), requestInner); err != nil {
+		s.restError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	requestInner.Backend = route.backend
+	requestInner.Table = route.table
+
+	options, err := parseTailOptions(ctx.QueryArgs())
+	if err != nil {
+		s.restError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var cursor tailCursor
+	now := time.Now()
+	switch backendType := s.backendType(route.backend); backendType {
+	case "stream":
+		cursor, err = newStreamTailCursor(requestInner, now)
+	case "tsdb":
+		cursor, err = newTSDBTailCursor(requestInner, now)
+	case "":
+		s.restError(ctx, http.StatusNotFound, fmt.Errorf("unknown backend - %q", route.backend))
+		return
+	default:
+		err = fmt.Errorf("tail is not supported for %s backends", backendType)
+	}
+
+	if err == nil {
+		if lastID := ctx.Request.Header.Peek("Last-Event-ID"); len(lastID) > 0 {
+			err = cursor.resume(string(lastID))
+		}
+	}
+
+	if err != nil {
+		s.restError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	session, password, token := s.restSession(ctx)
+	t := &tailer{
+		cursor:  cursor,
+		options: options,
+		read:    s.api.Read,
+		newRequest: func(requestInner *pb.ReadRequest) *frames.ReadRequest {
+			requestInner.Session = session
+			return &frames.ReadRequest{Proto: requestInner, Password: password, Token: token}
+		},
+		now:   time.Now,
+		sleep: time.Sleep,
+	}
+
+	s.logger.DebugWith("tail request", "request", requestInner, "options", options)
+
+	ctx.Response.Header.SetContentType("text/event-stream")
+	ctx.Response.Header.Set("Cache-Control", "no-cache")
+	// Disable proxy (e.g. nginx) buffering
+	ctx.Response.Header.Set("X-Accel-Buffering", "no")
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := t.run(w); err != nil {
+			s.logger.DebugWith("tail stopped", "error", err)
+		}
+	})
+}
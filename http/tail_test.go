@@ -0,0 +1,306 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseTailOptions(t *testing.T) {
+	args := &fasthttp.Args{}
+	options, err := parseTailOptions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.pollInterval != defaultTailPollInterval || options.maxWait != defaultTailPollInterval {
+		t.Fatalf("bad defaults - %+v", options)
+	}
+
+	args.Parse("poll_interval=200ms&batch_size=10&max_wait=2s&duration=1m")
+	options, err = parseTailOptions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := tailOptions{
+		pollInterval: 200 * time.Millisecond,
+		batchSize:    10,
+		maxWait:      2 * time.Second,
+		duration:     time.Minute,
+	}
+	if *options != expected {
+		t.Fatalf("bad options: %+v != %+v", *options, expected)
+	}
+
+	for _, query := range []string{"poll_interval=1ms", "batch_size=-1", "max_wait=forever"} {
+		args.Parse(query)
+		if _, err := parseTailOptions(args); err == nil {
+			t.Fatalf("%q: no error", query)
+		}
+	}
+}
+
+// fakeStreamShard returns records with sequence numbers after the requested
+// one, n new records are "added" on every read
+type fakeStreamShard struct {
+	lastSeq  int64
+	n        int
+	requests []*pb.ReadRequest
+}
+
+func (s *fakeStreamShard) read(request *frames.ReadRequest, out chan frames.Frame) error {
+	s.requests = append(s.requests, request.Proto)
+	from := int64(1)
+	if request.Proto.Seek == "seq" {
+		from = request.Proto.Sequence
+	}
+
+	s.lastSeq += int64(s.n)
+	var rows []map[string]interface{}
+	for seq := from; seq <= s.lastSeq; seq++ {
+		rows = append(rows, map[string]interface{}{"seq_number": seq})
+	}
+
+	if len(rows) == 0 {
+		frame, err := frames.NewFrameFromRows(nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		out <- frame
+		return nil
+	}
+
+	frame, err := frames.NewFrameFromRows(rows, nil, map[string]interface{}{"last_seq": s.lastSeq})
+	if err != nil {
+		return err
+	}
+	out <- frame
+	return nil
+}
+
+func newTestTailer(cursor tailCursor, options *tailOptions, read func(*frames.ReadRequest, chan frames.Frame) error) *tailer {
+	now := time.Unix(0, 0)
+	return &tailer{
+		cursor:  cursor,
+		options: options,
+		read:    read,
+		newRequest: func(request *pb.ReadRequest) *frames.ReadRequest {
+			return &frames.ReadRequest{Proto: request}
+		},
+		now:   func() time.Time { return now },
+		sleep: func(d time.Duration) { now = now.Add(d) },
+	}
+}
+
+func TestTailStream(t *testing.T) {
+	request := &pb.ReadRequest{Table: "s1", ShardId: "0", Seek: "earliest"}
+	cursor, err := newStreamTailCursor(request, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shard := &fakeStreamShard{n: 2}
+	options := &tailOptions{
+		pollInterval: time.Second,
+		batchSize:    3,
+		maxWait:      time.Minute,
+		duration:     3 * time.Second,
+	}
+
+	var buf bytes.Buffer
+	tl := newTestTailer(cursor, options, shard.read)
+	if err := tl.run(bufio.NewWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(shard.requests) != 4 {
+		t.Fatalf("bad number of polls - %d", len(shard.requests))
+	}
+
+	if shard.requests[0].Seek != "earliest" {
+		t.Fatalf("bad first seek - %q", shard.requests[0].Seek)
+	}
+
+	for i, request := range shard.requests[1:] {
+		if seq := int64(2*(i+1) + 1); request.Seek != "seq" || request.Sequence != seq {
+			t.Fatalf("poll %d: bad seek - %s/%d (expected seq/%d)", i+1, request.Seek, request.Sequence, seq)
+		}
+	}
+
+	// 8 rows, batches of (at least) 3 rows and the rest before end
+	expected := strings.Join([]string{
+		"id: 4\nevent: rows\ndata: {\"rows\":[{\"seq_number\":1},{\"seq_number\":2},{\"seq_number\":3},{\"seq_number\":4}]}\n\n",
+		"id: 8\nevent: rows\ndata: {\"rows\":[{\"seq_number\":5},{\"seq_number\":6},{\"seq_number\":7},{\"seq_number\":8}]}\n\n",
+		"id: 8\nevent: end\ndata: {}\n\n",
+	}, "")
+	if out := buf.String(); out != expected {
+		t.Fatalf("bad events:\n%s\nexpected:\n%s", out, expected)
+	}
+}
+
+func TestTailStreamLatest(t *testing.T) {
+	now := time.Unix(1546300800, 0)
+	request := &pb.ReadRequest{Table: "s1", ShardId: "0", Seek: "latest"}
+	cursor, err := newStreamTailCursor(request, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := cursor.next(now)
+	if next.Seek != "time" || next.Start != "1546300800000" {
+		t.Fatalf("bad first request - %s/%s", next.Seek, next.Start)
+	}
+
+	if err := cursor.resume("17"); err != nil {
+		t.Fatal(err)
+	}
+
+	next = cursor.next(now)
+	if next.Seek != "seq" || next.Sequence != 18 {
+		t.Fatalf("bad resumed request - %s/%d", next.Seek, next.Sequence)
+	}
+
+	if _, err := newStreamTailCursor(&pb.ReadRequest{Table: "s1"}, now); err == nil {
+		t.Fatal("no error on missing shard")
+	}
+}
+
+func TestTailTSDB(t *testing.T) {
+	request := &pb.ReadRequest{Table: "t1", Start: "0"}
+	cursor, err := newTSDBTailCursor(request, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ranges []string
+	read := func(request *frames.ReadRequest, out chan frames.Frame) error {
+		ranges = append(ranges, request.Proto.Start+"-"+request.Proto.End)
+		if len(ranges) == 2 {
+			return fmt.Errorf("oops")
+		}
+		return nil
+	}
+
+	options := &tailOptions{pollInterval: time.Second, maxWait: time.Second}
+	var buf bytes.Buffer
+	tl := newTestTailer(cursor, options, read)
+	if err := tl.run(bufio.NewWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRanges := []string{"0-0", "1-1000"}
+	if strings.Join(ranges, ",") != strings.Join(expectedRanges, ",") {
+		t.Fatalf("bad ranges: %v != %v", ranges, expectedRanges)
+	}
+
+	if out := buf.String(); out != "event: error\ndata: {\"error\":\"oops\"}\n\n" {
+		t.Fatalf("bad events - %q", out)
+	}
+}
+
+// fakeTSDBRead returns a frame per series ("a" and "b"), each with a row at
+// the end of the requested range
+func fakeTSDBRead(ranges *[]string) func(*frames.ReadRequest, chan frames.Frame) error {
+	return func(request *frames.ReadRequest, out chan frames.Frame) error {
+		*ranges = append(*ranges, request.Proto.Start+"-"+request.Proto.End)
+		start, _ := strconv.ParseInt(request.Proto.Start, 10, 64)
+		end, _ := strconv.ParseInt(request.Proto.End, 10, 64)
+		if end < start {
+			return nil
+		}
+
+		for _, series := range []string{"a", "b"} {
+			rows := []map[string]interface{}{{"series": series, "time": end}}
+			frame, err := frames.NewFrameFromRows(rows, nil, nil)
+			if err != nil {
+				return err
+			}
+			out <- frame
+		}
+		return nil
+	}
+}
+
+func TestTailTSDBResume(t *testing.T) {
+	options := &tailOptions{pollInterval: time.Second, batchSize: 1, maxWait: time.Second, duration: 2 * time.Second}
+	cursor, err := newTSDBTailCursor(&pb.ReadRequest{Table: "t1", Start: "0"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ranges []string
+	var buf bytes.Buffer
+	if err := newTestTailer(cursor, options, fakeTSDBRead(&ranges)).run(bufio.NewWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The event of series "a" is sent in the middle of the 1-1000 poll
+	event := "id: 0:1000:1\nevent: rows\ndata: {\"rows\":[{\"series\":\"a\",\"time\":1000}]}\n\n"
+	if out := buf.String(); !strings.Contains(out, event) {
+		t.Fatalf("missing event %q in:\n%s", event, out)
+	}
+
+	// Reconnecting after it reads the poll range again, without series "a"
+	cursor, err = newTSDBTailCursor(&pb.ReadRequest{Table: "t1"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cursor.resume("0:1000:1"); err != nil {
+		t.Fatal(err)
+	}
+
+	ranges = nil
+	buf.Reset()
+	options.duration = time.Second
+	if err := newTestTailer(cursor, options, fakeTSDBRead(&ranges)).run(bufio.NewWriter(&buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRanges := []string{"1-1000", "1001-1000"}
+	if strings.Join(ranges, ",") != strings.Join(expectedRanges, ",") {
+		t.Fatalf("bad ranges: %v != %v", ranges, expectedRanges)
+	}
+
+	expected := strings.Join([]string{
+		"id: 0:1000:2\nevent: rows\ndata: {\"rows\":[{\"series\":\"b\",\"time\":1000}]}\n\n",
+		"id: 1000\nevent: end\ndata: {}\n\n",
+	}, "")
+	if out := buf.String(); out != expected {
+		t.Fatalf("bad events:\n%s\nexpected:\n%s", out, expected)
+	}
+
+	for _, id := range []string{"1:2", "x:1000:1", "0:1000:-1", "1000:0:1"} {
+		if err := cursor.resume(id); err == nil {
+			t.Fatalf("%q: no error", id)
+		}
+	}
+}
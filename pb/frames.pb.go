@@ -118,6 +118,7 @@ type Column struct {
 	Strings              []string  `protobuf:"bytes,7,rep,name=strings,proto3" json:"strings,omitempty"`
 	Times                []int64   `protobuf:"varint,8,rep,packed,name=times,proto3" json:"times,omitempty"`
 	Bools                []bool    `protobuf:"varint,9,rep,packed,name=bools,proto3" json:"bools,omitempty"`
+	TimeZone             string    `protobuf:"bytes,10,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
 	XXX_unrecognized     []byte    `json:"-"`
 	XXX_sizecache        int32     `json:"-"`
@@ -210,6 +211,13 @@ func (m *Column) GetBools() []bool {
 	return nil
 }
 
+func (m *Column) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
 // Union of values
 type Value struct {
 	// Types that are valid to be assigned to Value:
@@ -886,6 +894,8 @@ type ReadRequest struct {
 	ShardingKeys      []string `protobuf:"bytes,18,rep,name=sharding_keys,json=shardingKeys,proto3" json:"sharding_keys,omitempty"`
 	SortKeyRangeStart string   `protobuf:"bytes,19,opt,name=sort_key_range_start,json=sortKeyRangeStart,proto3" json:"sort_key_range_start,omitempty"`
 	SortKeyRangeEnd   string   `protobuf:"bytes,20,opt,name=sort_key_range_end,json=sortKeyRangeEnd,proto3" json:"sort_key_range_end,omitempty"`
+	TimeZone          string   `protobuf:"bytes,30,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	IncludeExpired    bool     `protobuf:"varint,31,opt,name=include_expired,json=includeExpired,proto3" json:"include_expired,omitempty"`
 	// TSDB
 	Start             string `protobuf:"bytes,21,opt,name=start,proto3" json:"start,omitempty"`
 	End               string `protobuf:"bytes,22,opt,name=end,proto3" json:"end,omitempty"`
@@ -1072,6 +1082,20 @@ func (m *ReadRequest) GetSortKeyRangeEnd() string {
 	return ""
 }
 
+func (m *ReadRequest) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetIncludeExpired() bool {
+	if m != nil {
+		return m.IncludeExpired
+	}
+	return false
+}
+
 func (m *ReadRequest) GetStart() string {
 	if m != nil {
 		return m.Start
@@ -1138,6 +1162,11 @@ type InitialWriteRequest struct {
 	PartitionKeys        []string `protobuf:"bytes,7,rep,name=partition_keys,json=partitionKeys,proto3" json:"partition_keys,omitempty"`
 	Condition            string   `protobuf:"bytes,8,opt,name=condition,proto3" json:"condition,omitempty"`
 	SaveMode             string   `protobuf:"bytes,9,opt,name=save_mode,json=saveMode,proto3" json:"save_mode,omitempty"`
+	ContinueOnError      bool     `protobuf:"varint,10,opt,name=continue_on_error,json=continueOnError,proto3" json:"continue_on_error,omitempty"`
+	TimeZone             string   `protobuf:"bytes,11,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	TimePrecision        string   `protobuf:"bytes,12,opt,name=time_precision,json=timePrecision,proto3" json:"time_precision,omitempty"`
+	Ttl                  string   `protobuf:"bytes,13,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	SchemaMode           string   `protobuf:"bytes,14,opt,name=schema_mode,json=schemaMode,proto3" json:"schema_mode,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1230,6 +1259,41 @@ func (m *InitialWriteRequest) GetSaveMode() string {
 	return ""
 }
 
+func (m *InitialWriteRequest) GetContinueOnError() bool {
+	if m != nil {
+		return m.ContinueOnError
+	}
+	return false
+}
+
+func (m *InitialWriteRequest) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
+func (m *InitialWriteRequest) GetTimePrecision() string {
+	if m != nil {
+		return m.TimePrecision
+	}
+	return ""
+}
+
+func (m *InitialWriteRequest) GetTtl() string {
+	if m != nil {
+		return m.Ttl
+	}
+	return ""
+}
+
+func (m *InitialWriteRequest) GetSchemaMode() string {
+	if m != nil {
+		return m.SchemaMode
+	}
+	return ""
+}
+
 type WriteRequest struct {
 	// Types that are valid to be assigned to Type:
 	//	*WriteRequest_Request
@@ -1378,6 +1442,7 @@ func _WriteRequest_OneofSizer(msg proto.Message) (n int) {
 type WriteRespose struct {
 	Frames               int64    `protobuf:"varint,1,opt,name=frames,proto3" json:"frames,omitempty"`
 	Rows                 int64    `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"`
+	FailedRows           *Frame   `protobuf:"bytes,3,opt,name=failed_rows,json=failedRows,proto3" json:"failed_rows,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1421,6 +1486,13 @@ func (m *WriteRespose) GetRows() int64 {
 	return 0
 }
 
+func (m *WriteRespose) GetFailedRows() *Frame {
+	if m != nil {
+		return m.FailedRows
+	}
+	return nil
+}
+
 // CreateRequest is a table creation request
 type CreateRequest struct {
 	Session  *Session     `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
@@ -1773,6 +1845,7 @@ type ExecRequest struct {
 	Command              string            `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
 	Args                 map[string]*Value `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	Expression           string            `protobuf:"bytes,6,opt,name=expression,proto3" json:"expression,omitempty"`
+	Frame                *Frame            `protobuf:"bytes,7,opt,name=frame,proto3" json:"frame,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -1844,6 +1917,13 @@ func (m *ExecRequest) GetExpression() string {
 	return ""
 }
 
+func (m *ExecRequest) GetFrame() *Frame {
+	if m != nil {
+		return m.Frame
+	}
+	return nil
+}
+
 type VersionResponse struct {
 	Version              string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1992,6 +2072,297 @@ func (m *HistoryRequest) GetMaxDuration() int64 {
 	return 0
 }
 
+// WriteAck acknowledges a frame written with WriteStream
+type WriteAck struct {
+	Frame                int64    `protobuf:"varint,1,opt,name=frame,proto3" json:"frame,omitempty"`
+	Rows                 int64    `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"`
+	FailedRows           *Frame   `protobuf:"bytes,3,opt,name=failed_rows,json=failedRows,proto3" json:"failed_rows,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriteAck) Reset()         { *m = WriteAck{} }
+func (m *WriteAck) String() string { return proto.CompactTextString(m) }
+func (*WriteAck) ProtoMessage()    {}
+func (*WriteAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_e3d1b436579e21b2, []int{22}
+}
+func (m *WriteAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteAck.Unmarshal(m, b)
+}
+func (m *WriteAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteAck.Marshal(b, m, deterministic)
+}
+func (dst *WriteAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteAck.Merge(dst, src)
+}
+func (m *WriteAck) XXX_Size() int {
+	return xxx_messageInfo_WriteAck.Size(m)
+}
+func (m *WriteAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteAck proto.InternalMessageInfo
+
+func (m *WriteAck) GetFrame() int64 {
+	if m != nil {
+		return m.Frame
+	}
+	return 0
+}
+
+func (m *WriteAck) GetRows() int64 {
+	if m != nil {
+		return m.Rows
+	}
+	return 0
+}
+
+func (m *WriteAck) GetFailedRows() *Frame {
+	if m != nil {
+		return m.FailedRows
+	}
+	return nil
+}
+
+// BatchWriteTable is the frame written to a table in a batch write
+type BatchWriteTable struct {
+	Table                string   `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	Frame                *Frame   `protobuf:"bytes,2,opt,name=frame,proto3" json:"frame,omitempty"`
+	PartitionKeys        []string `protobuf:"bytes,3,rep,name=partition_keys,json=partitionKeys,proto3" json:"partition_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchWriteTable) Reset()         { *m = BatchWriteTable{} }
+func (m *BatchWriteTable) String() string { return proto.CompactTextString(m) }
+func (*BatchWriteTable) ProtoMessage()    {}
+func (*BatchWriteTable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_e3d1b436579e21b2, []int{23}
+}
+func (m *BatchWriteTable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchWriteTable.Unmarshal(m, b)
+}
+func (m *BatchWriteTable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchWriteTable.Marshal(b, m, deterministic)
+}
+func (dst *BatchWriteTable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchWriteTable.Merge(dst, src)
+}
+func (m *BatchWriteTable) XXX_Size() int {
+	return xxx_messageInfo_BatchWriteTable.Size(m)
+}
+func (m *BatchWriteTable) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchWriteTable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchWriteTable proto.InternalMessageInfo
+
+func (m *BatchWriteTable) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *BatchWriteTable) GetFrame() *Frame {
+	if m != nil {
+		return m.Frame
+	}
+	return nil
+}
+
+func (m *BatchWriteTable) GetPartitionKeys() []string {
+	if m != nil {
+		return m.PartitionKeys
+	}
+	return nil
+}
+
+// BatchWriteRequest writes frames to several tables of a backend, either all
+// of them are written or none is
+type BatchWriteRequest struct {
+	Session              *Session           `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Backend              string             `protobuf:"bytes,2,opt,name=backend,proto3" json:"backend,omitempty"`
+	Tables               []*BatchWriteTable `protobuf:"bytes,3,rep,name=tables,proto3" json:"tables,omitempty"`
+	VersionAttribute     string             `protobuf:"bytes,4,opt,name=version_attribute,json=versionAttribute,proto3" json:"version_attribute,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *BatchWriteRequest) Reset()         { *m = BatchWriteRequest{} }
+func (m *BatchWriteRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchWriteRequest) ProtoMessage()    {}
+func (*BatchWriteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_e3d1b436579e21b2, []int{24}
+}
+func (m *BatchWriteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchWriteRequest.Unmarshal(m, b)
+}
+func (m *BatchWriteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchWriteRequest.Marshal(b, m, deterministic)
+}
+func (dst *BatchWriteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchWriteRequest.Merge(dst, src)
+}
+func (m *BatchWriteRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchWriteRequest.Size(m)
+}
+func (m *BatchWriteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchWriteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchWriteRequest proto.InternalMessageInfo
+
+func (m *BatchWriteRequest) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *BatchWriteRequest) GetBackend() string {
+	if m != nil {
+		return m.Backend
+	}
+	return ""
+}
+
+func (m *BatchWriteRequest) GetTables() []*BatchWriteTable {
+	if m != nil {
+		return m.Tables
+	}
+	return nil
+}
+
+func (m *BatchWriteRequest) GetVersionAttribute() string {
+	if m != nil {
+		return m.VersionAttribute
+	}
+	return ""
+}
+
+// BatchTableResult is the outcome of a table in a batch write
+type BatchTableResult struct {
+	Table                string   `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	Status               string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Items                int64    `protobuf:"varint,3,opt,name=items,proto3" json:"items,omitempty"`
+	Error                string   `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchTableResult) Reset()         { *m = BatchTableResult{} }
+func (m *BatchTableResult) String() string { return proto.CompactTextString(m) }
+func (*BatchTableResult) ProtoMessage()    {}
+func (*BatchTableResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_e3d1b436579e21b2, []int{25}
+}
+func (m *BatchTableResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchTableResult.Unmarshal(m, b)
+}
+func (m *BatchTableResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchTableResult.Marshal(b, m, deterministic)
+}
+func (dst *BatchTableResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchTableResult.Merge(dst, src)
+}
+func (m *BatchTableResult) XXX_Size() int {
+	return xxx_messageInfo_BatchTableResult.Size(m)
+}
+func (m *BatchTableResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchTableResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchTableResult proto.InternalMessageInfo
+
+func (m *BatchTableResult) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *BatchTableResult) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *BatchTableResult) GetItems() int64 {
+	if m != nil {
+		return m.Items
+	}
+	return 0
+}
+
+func (m *BatchTableResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type BatchWriteResponse struct {
+	Committed            bool                `protobuf:"varint,1,opt,name=committed,proto3" json:"committed,omitempty"`
+	Tables               []*BatchTableResult `protobuf:"bytes,2,rep,name=tables,proto3" json:"tables,omitempty"`
+	Error                string              `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *BatchWriteResponse) Reset()         { *m = BatchWriteResponse{} }
+func (m *BatchWriteResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchWriteResponse) ProtoMessage()    {}
+func (*BatchWriteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_e3d1b436579e21b2, []int{26}
+}
+func (m *BatchWriteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchWriteResponse.Unmarshal(m, b)
+}
+func (m *BatchWriteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchWriteResponse.Marshal(b, m, deterministic)
+}
+func (dst *BatchWriteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchWriteResponse.Merge(dst, src)
+}
+func (m *BatchWriteResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchWriteResponse.Size(m)
+}
+func (m *BatchWriteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchWriteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchWriteResponse proto.InternalMessageInfo
+
+func (m *BatchWriteResponse) GetCommitted() bool {
+	if m != nil {
+		return m.Committed
+	}
+	return false
+}
+
+func (m *BatchWriteResponse) GetTables() []*BatchTableResult {
+	if m != nil {
+		return m.Tables
+	}
+	return nil
+}
+
+func (m *BatchWriteResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Column)(nil), "pb.Column")
 	proto.RegisterType((*Value)(nil), "pb.Value")
@@ -2019,6 +2390,11 @@ func init() {
 	proto.RegisterMapType((map[string]*Value)(nil), "pb.ExecRequest.ArgsEntry")
 	proto.RegisterType((*VersionResponse)(nil), "pb.VersionResponse")
 	proto.RegisterType((*HistoryRequest)(nil), "pb.HistoryRequest")
+	proto.RegisterType((*WriteAck)(nil), "pb.WriteAck")
+	proto.RegisterType((*BatchWriteTable)(nil), "pb.BatchWriteTable")
+	proto.RegisterType((*BatchWriteRequest)(nil), "pb.BatchWriteRequest")
+	proto.RegisterType((*BatchTableResult)(nil), "pb.BatchTableResult")
+	proto.RegisterType((*BatchWriteResponse)(nil), "pb.BatchWriteResponse")
 	proto.RegisterEnum("pb.DType", DType_name, DType_value)
 	proto.RegisterEnum("pb.ErrorOptions", ErrorOptions_name, ErrorOptions_value)
 	proto.RegisterEnum("pb.Column_Kind", Column_Kind_name, Column_Kind_value)
@@ -2043,6 +2419,8 @@ type FramesClient interface {
 	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
 	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (Frames_HistoryClient, error)
 	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+	WriteStream(ctx context.Context, opts ...grpc.CallOption) (Frames_WriteStreamClient, error)
+	BatchWrite(ctx context.Context, in *BatchWriteRequest, opts ...grpc.CallOption) (*BatchWriteResponse, error)
 }
 
 type framesClient struct {
@@ -2187,6 +2565,46 @@ func (c *framesClient) Version(ctx context.Context, in *VersionRequest, opts ...
 	return out, nil
 }
 
+func (c *framesClient) WriteStream(ctx context.Context, opts ...grpc.CallOption) (Frames_WriteStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Frames_serviceDesc.Streams[3], "/pb.Frames/WriteStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &framesWriteStreamClient{stream}
+	return x, nil
+}
+
+type Frames_WriteStreamClient interface {
+	Send(*WriteRequest) error
+	Recv() (*WriteAck, error)
+	grpc.ClientStream
+}
+
+type framesWriteStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *framesWriteStreamClient) Send(m *WriteRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *framesWriteStreamClient) Recv() (*WriteAck, error) {
+	m := new(WriteAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *framesClient) BatchWrite(ctx context.Context, in *BatchWriteRequest, opts ...grpc.CallOption) (*BatchWriteResponse, error) {
+	out := new(BatchWriteResponse)
+	err := c.cc.Invoke(ctx, "/pb.Frames/BatchWrite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FramesServer is the server API for Frames service.
 type FramesServer interface {
 	Read(*ReadRequest, Frames_ReadServer) error
@@ -2196,6 +2614,8 @@ type FramesServer interface {
 	Exec(context.Context, *ExecRequest) (*ExecResponse, error)
 	History(*HistoryRequest, Frames_HistoryServer) error
 	Version(context.Context, *VersionRequest) (*VersionResponse, error)
+	WriteStream(Frames_WriteStreamServer) error
+	BatchWrite(context.Context, *BatchWriteRequest) (*BatchWriteResponse, error)
 }
 
 func RegisterFramesServer(s *grpc.Server, srv FramesServer) {
@@ -2342,6 +2762,50 @@ func _Frames_Version_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Frames_WriteStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FramesServer).WriteStream(&framesWriteStreamServer{stream})
+}
+
+type Frames_WriteStreamServer interface {
+	Send(*WriteAck) error
+	Recv() (*WriteRequest, error)
+	grpc.ServerStream
+}
+
+type framesWriteStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *framesWriteStreamServer) Send(m *WriteAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *framesWriteStreamServer) Recv() (*WriteRequest, error) {
+	m := new(WriteRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Frames_BatchWrite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchWriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FramesServer).BatchWrite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Frames/BatchWrite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FramesServer).BatchWrite(ctx, req.(*BatchWriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Frames_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.Frames",
 	HandlerType: (*FramesServer)(nil),
@@ -2362,6 +2826,10 @@ var _Frames_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _Frames_Version_Handler,
 		},
+		{
+			MethodName: "BatchWrite",
+			Handler:    _Frames_BatchWrite_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2379,6 +2847,12 @@ var _Frames_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Frames_History_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WriteStream",
+			Handler:       _Frames_WriteStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "frames.proto",
 }
@@ -2386,129 +2860,150 @@ var _Frames_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("frames.proto", fileDescriptor_frames_e3d1b436579e21b2) }
 
 var fileDescriptor_frames_e3d1b436579e21b2 = []byte{
-	// 1969 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xcd, 0x72, 0x1b, 0xc7,
-	0x11, 0xe6, 0xe2, 0x7f, 0x1b, 0x20, 0x08, 0x8f, 0x65, 0x79, 0x05, 0xcb, 0x11, 0xb5, 0x92, 0x63,
-	0x96, 0x65, 0x51, 0x09, 0x9d, 0xaa, 0xa4, 0x7c, 0x48, 0x4a, 0x14, 0x41, 0x91, 0x11, 0x44, 0xa6,
-	0x96, 0x8c, 0x7c, 0x44, 0x0d, 0xb1, 0x03, 0x68, 0xc2, 0xfd, 0x81, 0x66, 0x06, 0x22, 0x91, 0x43,
-	0x8e, 0xc9, 0x39, 0xa9, 0xca, 0x13, 0xe4, 0x05, 0xf2, 0x0e, 0x39, 0xe5, 0x0d, 0xf2, 0x10, 0xb9,
-	0xe4, 0x94, 0x6b, 0xaa, 0x7b, 0x66, 0x81, 0x05, 0xa5, 0xe4, 0xe0, 0x8a, 0x6e, 0xd3, 0x5f, 0xf7,
-	0xcc, 0x74, 0x7f, 0xdb, 0xdd, 0xd3, 0x00, 0x74, 0x26, 0x8a, 0xa7, 0x42, 0xef, 0xce, 0x54, 0x6e,
-	0x72, 0x56, 0x99, 0x5d, 0x84, 0xbf, 0xaf, 0x40, 0xe3, 0x59, 0x9e, 0xcc, 0xd3, 0x8c, 0x3d, 0x80,
-	0xda, 0xa5, 0xcc, 0xe2, 0xc0, 0xdb, 0xf6, 0x76, 0xba, 0x7b, 0x5b, 0xbb, 0xb3, 0x8b, 0x5d, 0xab,
-	0xd9, 0x7d, 0x21, 0xb3, 0x38, 0x22, 0x25, 0x63, 0x50, 0xcb, 0x78, 0x2a, 0x82, 0xca, 0xb6, 0xb7,
-	0xe3, 0x47, 0xb4, 0x66, 0xf7, 0xa0, 0x1e, 0x9b, 0xc5, 0x4c, 0x04, 0x55, 0xda, 0xe9, 0xe3, 0xce,
-	0x83, 0xf3, 0xc5, 0x4c, 0x44, 0x16, 0xc7, 0x4d, 0x5a, 0xfe, 0x56, 0x04, 0xb5, 0x6d, 0x6f, 0xa7,
-	0x1a, 0xd1, 0x1a, 0x31, 0x99, 0x19, 0x1d, 0xd4, 0xb7, 0xab, 0x88, 0xe1, 0x9a, 0xdd, 0x86, 0xc6,
-	0x24, 0xc9, 0xb9, 0xd1, 0x41, 0x63, 0xbb, 0xba, 0xe3, 0x45, 0x4e, 0x62, 0x01, 0x34, 0xb5, 0x51,
-	0x32, 0x9b, 0xea, 0xa0, 0xb9, 0x5d, 0xdd, 0xf1, 0xa3, 0x42, 0x64, 0xb7, 0xa0, 0x6e, 0x64, 0x2a,
-	0x74, 0xd0, 0xa2, 0x63, 0xac, 0x80, 0xe8, 0x45, 0x9e, 0x27, 0x3a, 0xf0, 0xb7, 0xab, 0x3b, 0xad,
-	0xc8, 0x0a, 0xe1, 0x5d, 0xa8, 0x61, 0x20, 0xcc, 0x87, 0xfa, 0xd9, 0xf0, 0xf8, 0xd9, 0xa0, 0xb7,
-	0x81, 0xcb, 0xe1, 0xd3, 0xfd, 0xc1, 0xb0, 0xe7, 0x85, 0xbf, 0x83, 0xfa, 0x2b, 0x9e, 0xcc, 0x05,
-	0xbb, 0x05, 0x35, 0xf9, 0x96, 0x27, 0x44, 0x43, 0xf5, 0x68, 0x23, 0x22, 0x09, 0xd1, 0x09, 0xa2,
-	0x18, 0xb7, 0x87, 0xe8, 0xc4, 0xa1, 0x1a, 0x51, 0x0c, 0xdc, 0x47, 0x54, 0x3b, 0xd4, 0x20, 0x5a,
-	0x2b, 0x4e, 0x30, 0x0e, 0xbd, 0x40, 0xb4, 0xbe, 0xed, 0xed, 0xb4, 0x10, 0x45, 0x69, 0xbf, 0x09,
-	0xf5, 0xb7, 0x78, 0x6d, 0xf8, 0x67, 0x0f, 0x36, 0x4f, 0xe6, 0x49, 0x42, 0x4e, 0xe8, 0x97, 0x7c,
-	0xc6, 0x0e, 0xa0, 0x9d, 0xcd, 0x93, 0xc4, 0x7e, 0x03, 0x1d, 0x78, 0xdb, 0xd5, 0x9d, 0xf6, 0x5e,
-	0x88, 0xe4, 0xae, 0xd9, 0x91, 0xe4, 0x8c, 0x06, 0x99, 0x51, 0x8b, 0xa8, 0xbc, 0xad, 0xff, 0x73,
-	0xe8, 0xdd, 0x34, 0x60, 0x3d, 0xa8, 0x5e, 0x8a, 0x05, 0x45, 0xe8, 0x47, 0xb8, 0x44, 0xc6, 0xc8,
-	0x0d, 0x8a, 0xaf, 0x15, 0x59, 0xe1, 0xdb, 0xca, 0xcf, 0xbc, 0xf0, 0x4f, 0x15, 0xa8, 0x1f, 0x62,
-	0xd6, 0xb0, 0x87, 0xd0, 0x1c, 0xaf, 0xf9, 0x02, 0xab, 0x14, 0x89, 0x0a, 0x15, 0x5a, 0xc9, 0x2c,
-	0x96, 0x63, 0xa1, 0x83, 0xca, 0xbb, 0x56, 0x4e, 0xc5, 0x1e, 0x43, 0x23, 0xe1, 0x17, 0x22, 0xd1,
-	0x41, 0x95, 0x8c, 0x3e, 0x41, 0x23, 0xba, 0x66, 0x77, 0x48, 0xb8, 0x8d, 0xc4, 0x19, 0xa1, 0x7b,
-	0x42, 0xa9, 0x5c, 0x11, 0xa5, 0x7e, 0x64, 0x05, 0xb6, 0x67, 0x09, 0x1a, 0x91, 0xb3, 0x36, 0x93,
-	0xda, 0x7b, 0x1f, 0xbd, 0x43, 0x50, 0x04, 0xd9, 0x52, 0xec, 0x1f, 0x40, 0xbb, 0x74, 0xc1, 0x7b,
-	0x98, 0xb8, 0x57, 0x66, 0xa2, 0x6d, 0x93, 0x99, 0xf6, 0x96, 0x49, 0xf9, 0xb7, 0x07, 0xed, 0xb3,
-	0xf1, 0x6b, 0x91, 0xf2, 0x43, 0x29, 0x92, 0x55, 0x55, 0x78, 0xa5, 0xaa, 0xe8, 0x41, 0x35, 0xce,
-	0xc7, 0xae, 0x50, 0x70, 0xc9, 0x1e, 0x40, 0x33, 0x16, 0x13, 0x3e, 0x4f, 0x0c, 0x25, 0xcc, 0xda,
-	0xe1, 0x85, 0x06, 0x8f, 0xa2, 0x5a, 0xb2, 0x91, 0xd2, 0x9a, 0xfd, 0x02, 0x60, 0xa6, 0xf2, 0x99,
-	0x50, 0x46, 0x2e, 0xe3, 0xbc, 0x87, 0x7b, 0x4b, 0x3e, 0xec, 0xfe, 0x6a, 0x69, 0x61, 0xb9, 0x2b,
-	0x6d, 0xe9, 0x1f, 0xc1, 0xd6, 0x0d, 0xf5, 0xf7, 0x8d, 0xfc, 0x14, 0x7c, 0x7b, 0xe9, 0x0b, 0xb1,
-	0x60, 0xf7, 0xa1, 0xa3, 0x5f, 0x73, 0x15, 0xcb, 0x6c, 0x3a, 0xb2, 0x87, 0x61, 0x71, 0xb6, 0x0b,
-	0xec, 0x05, 0x1d, 0xda, 0xd6, 0xb9, 0x32, 0x85, 0x45, 0x85, 0x2c, 0xc0, 0x41, 0x2f, 0xc4, 0x22,
-	0xfc, 0xbb, 0x07, 0xed, 0x73, 0x7e, 0x91, 0x08, 0x7b, 0xec, 0x32, 0x7e, 0xaf, 0x14, 0xff, 0x5d,
-	0xf0, 0x91, 0x52, 0x3d, 0xe3, 0xe3, 0xa2, 0xf3, 0xac, 0x80, 0x25, 0xf9, 0xd5, 0x77, 0xc9, 0xaf,
-	0xad, 0xc8, 0x0f, 0xa0, 0xc9, 0x13, 0xc9, 0xb5, 0x23, 0xd0, 0x8f, 0x0a, 0x91, 0x7d, 0x09, 0x8d,
-	0x09, 0x32, 0x68, 0xbb, 0x4e, 0xdb, 0x76, 0xbe, 0x12, 0xb3, 0x91, 0x53, 0xb3, 0x7b, 0x96, 0xb2,
-	0x26, 0xd1, 0xb3, 0xb9, 0xb2, 0x7a, 0x21, 0x16, 0xc4, 0x60, 0xd8, 0x01, 0xf8, 0x65, 0x2e, 0xb3,
-	0x33, 0xa3, 0xe6, 0x63, 0x13, 0xfe, 0xc5, 0x83, 0xe6, 0x99, 0xd0, 0x5a, 0xe6, 0x19, 0xfa, 0x33,
-	0x57, 0x49, 0xc1, 0xf6, 0x5c, 0x25, 0x18, 0xd3, 0x38, 0xcf, 0x0c, 0x97, 0x99, 0x50, 0x45, 0x4c,
-	0x4b, 0x00, 0x63, 0x9a, 0x71, 0xf3, 0xba, 0x88, 0x09, 0xd7, 0x88, 0xcd, 0xb5, 0x28, 0x6a, 0x80,
-	0xd6, 0xac, 0x0f, 0xad, 0x19, 0xd7, 0xfa, 0x2a, 0x57, 0x31, 0x35, 0x16, 0x3f, 0x5a, 0xca, 0xd4,
-	0x1b, 0xf3, 0x4b, 0x91, 0x05, 0x0d, 0x5b, 0x34, 0x24, 0xb0, 0x2e, 0x54, 0x64, 0x4c, 0x31, 0xf8,
-	0x51, 0x45, 0xc6, 0xe1, 0x1f, 0x9a, 0xd0, 0x8e, 0x04, 0x8f, 0x23, 0xf1, 0x66, 0x2e, 0xb4, 0x61,
-	0x5f, 0x40, 0x53, 0x5b, 0xa7, 0xc9, 0xdb, 0xf6, 0x5e, 0x9b, 0x02, 0xb5, 0x50, 0x54, 0xe8, 0x90,
-	0xce, 0x0b, 0x3e, 0xbe, 0x14, 0x59, 0xec, 0x9c, 0x2f, 0x44, 0xa4, 0x53, 0x13, 0x2d, 0x2e, 0xc9,
-	0x89, 0xce, 0xd2, 0x17, 0x8e, 0x9c, 0x1a, 0x53, 0x23, 0xe6, 0x86, 0x8f, 0x26, 0xb9, 0x4a, 0xb9,
-	0x71, 0x61, 0x01, 0x42, 0x87, 0x84, 0xb0, 0xcf, 0x01, 0x54, 0x7e, 0x35, 0x4a, 0xf8, 0x22, 0x9f,
-	0x1b, 0xdb, 0x37, 0x23, 0x5f, 0xe5, 0x57, 0x43, 0x02, 0x70, 0x7f, 0x3a, 0x4f, 0x8c, 0x1c, 0xc9,
-	0x2c, 0x16, 0xd7, 0x14, 0x65, 0x2b, 0x02, 0x82, 0x8e, 0x11, 0x41, 0x02, 0xde, 0xcc, 0x85, 0x5a,
-	0xb8, 0x68, 0xad, 0x40, 0xb4, 0xa0, 0x37, 0x41, 0xcb, 0xd1, 0x82, 0x02, 0xc6, 0x53, 0x34, 0x37,
-	0xdf, 0xa6, 0x47, 0xd1, 0xd0, 0xf0, 0x51, 0x92, 0x89, 0x11, 0x2a, 0x00, 0xda, 0xe0, 0x24, 0x76,
-	0x07, 0x5a, 0x53, 0x95, 0xcf, 0x67, 0xa3, 0x8b, 0x45, 0xd0, 0xb6, 0x14, 0x90, 0xbc, 0xbf, 0x60,
-	0x21, 0xd4, 0x7e, 0x93, 0xcb, 0x2c, 0xe8, 0x50, 0x3e, 0x75, 0x91, 0x80, 0x55, 0x5e, 0x44, 0xa4,
-	0x43, 0x37, 0x12, 0x99, 0x4a, 0x13, 0x6c, 0xd2, 0xa3, 0x68, 0x05, 0xf6, 0x00, 0x36, 0x53, 0xa1,
-	0x35, 0x9f, 0x8a, 0x91, 0xd5, 0x76, 0x49, 0xdb, 0x71, 0xe0, 0x90, 0x8c, 0x6e, 0x43, 0x23, 0xe5,
-	0xea, 0x52, 0xa8, 0x60, 0xcb, 0x7a, 0x64, 0x25, 0x24, 0x44, 0x09, 0x2d, 0x8c, 0x23, 0xe4, 0x73,
-	0x4b, 0x08, 0x41, 0x96, 0x90, 0x3e, 0xb4, 0xb4, 0x98, 0xa6, 0x02, 0xdf, 0xdd, 0x1e, 0x3d, 0x98,
-	0x4b, 0x99, 0x7d, 0x01, 0x5d, 0x93, 0x1b, 0x9e, 0x8c, 0x96, 0x16, 0x1f, 0xd1, 0xd5, 0x9b, 0x84,
-	0x9e, 0x15, 0x66, 0x0f, 0x60, 0xb3, 0x5c, 0xf2, 0x3a, 0x60, 0xc4, 0x56, 0xa7, 0x54, 0xf3, 0x9a,
-	0x3d, 0x81, 0x5b, 0x58, 0xe1, 0x68, 0x30, 0x52, 0x3c, 0x9b, 0x8a, 0x91, 0x36, 0x5c, 0x99, 0xe0,
-	0x63, 0x72, 0xf7, 0x23, 0xd4, 0x61, 0xcd, 0xa0, 0xe6, 0x0c, 0x15, 0xec, 0x11, 0xb0, 0x1b, 0x1b,
-	0x30, 0xb1, 0x6e, 0x91, 0xf9, 0x56, 0xd9, 0x7c, 0x90, 0x51, 0x5e, 0xdb, 0xe3, 0x3e, 0xb1, 0x1f,
-	0x90, 0x04, 0xac, 0x30, 0xdc, 0x73, 0xdb, 0x56, 0x98, 0xb0, 0xa3, 0x8a, 0x36, 0x62, 0x16, 0x7c,
-	0x6a, 0xeb, 0x05, 0xd7, 0x6c, 0x1b, 0xda, 0x7c, 0x3a, 0x55, 0x62, 0xca, 0x4d, 0xae, 0x74, 0x10,
-	0x90, 0xaa, 0x0c, 0xb1, 0xc7, 0xc0, 0x0a, 0x51, 0xe6, 0xd9, 0xe8, 0x4a, 0x66, 0x71, 0x7e, 0x15,
-	0xdc, 0xb5, 0x9e, 0x97, 0x34, 0xdf, 0x91, 0x82, 0x2e, 0x11, 0xe2, 0x32, 0xb8, 0xe3, 0x2e, 0x11,
-	0xe2, 0x12, 0x33, 0x83, 0xe8, 0x18, 0xc9, 0x38, 0xe8, 0xdb, 0xcc, 0x20, 0xf9, 0x38, 0xb6, 0x5f,
-	0xe0, 0xcd, 0x5c, 0x64, 0x63, 0x11, 0x7c, 0x46, 0xfc, 0x2e, 0xe5, 0xf0, 0xaf, 0x15, 0xf8, 0xf8,
-	0x38, 0x93, 0x46, 0xf2, 0xe4, 0x3b, 0x25, 0x8d, 0xf8, 0xbf, 0x55, 0xe4, 0x32, 0xe3, 0xab, 0xe5,
-	0x8c, 0xff, 0x1a, 0x3a, 0xd2, 0xde, 0x36, 0xc2, 0x9a, 0xa3, 0xfa, 0x73, 0x5d, 0x9f, 0x1e, 0xe2,
-	0xa8, 0xed, 0xd4, 0x07, 0xdc, 0x70, 0xf6, 0x03, 0x00, 0x71, 0x3d, 0x53, 0xce, 0x0f, 0xdb, 0x6a,
-	0x4a, 0x08, 0xf2, 0x90, 0xe6, 0x4a, 0xb8, 0x2a, 0xa4, 0x35, 0xa6, 0xd4, 0x8c, 0x2b, 0x23, 0x89,
-	0x48, 0x4a, 0x16, 0x3b, 0xbd, 0x6d, 0x2e, 0x51, 0xca, 0x16, 0xdb, 0x09, 0x63, 0x02, 0x5c, 0x51,
-	0xae, 0x00, 0xf6, 0x19, 0xf8, 0x9a, 0xbf, 0x15, 0xa3, 0x34, 0x8f, 0x45, 0xe0, 0xdb, 0x16, 0x87,
-	0xc0, 0xcb, 0x3c, 0x16, 0x61, 0x06, 0x9d, 0x35, 0xaa, 0xbe, 0x81, 0xa6, 0xb2, 0x4b, 0x47, 0xd5,
-	0xa7, 0x18, 0xce, 0x7b, 0x48, 0x3d, 0xda, 0x88, 0x0a, 0x4b, 0x76, 0x1f, 0xea, 0x34, 0x16, 0x97,
-	0xdf, 0x3d, 0x62, 0xe0, 0x68, 0x23, 0xb2, 0x9a, 0xfd, 0x86, 0x7d, 0x94, 0xc2, 0x6f, 0x97, 0xf7,
-	0xe9, 0x59, 0xae, 0x05, 0xf5, 0x06, 0x9a, 0xa8, 0xed, 0xb4, 0x18, 0x39, 0x09, 0xd9, 0x50, 0xf9,
-	0x95, 0xa6, 0x13, 0xab, 0x11, 0xad, 0xc3, 0x7f, 0x56, 0x60, 0xf3, 0x99, 0x12, 0xfc, 0x83, 0x7f,
-	0xd8, 0x55, 0x03, 0xae, 0xfd, 0xef, 0x06, 0xfc, 0x18, 0x7c, 0x39, 0x19, 0x89, 0x6b, 0xa9, 0x69,
-	0x0e, 0xc7, 0xd9, 0xbd, 0x87, 0xb6, 0x03, 0x9c, 0xae, 0x4e, 0x67, 0x48, 0xbf, 0x8e, 0x5a, 0x72,
-	0x32, 0x20, 0x0b, 0x0a, 0x8a, 0x1b, 0xe1, 0x9e, 0x13, 0x5a, 0x63, 0x5a, 0x14, 0x35, 0x21, 0xb4,
-	0xeb, 0xb3, 0x25, 0x84, 0xfd, 0x14, 0x3e, 0x2d, 0x57, 0xd3, 0x54, 0xf1, 0x6c, 0x9e, 0x70, 0x25,
-	0xcd, 0xc2, 0x7d, 0xe9, 0xdb, 0x25, 0xf5, 0xf3, 0x95, 0x16, 0x99, 0xa5, 0x9a, 0xd1, 0xf4, 0xcd,
-	0xab, 0x91, 0x93, 0xd8, 0x97, 0xb0, 0xa5, 0x84, 0x11, 0x19, 0x1d, 0xf7, 0x3a, 0x9f, 0x2b, 0x4d,
-	0x6d, 0xb9, 0x1a, 0x75, 0x97, 0xf0, 0x11, 0xa2, 0x61, 0x0f, 0xba, 0x05, 0xdb, 0x7a, 0x96, 0x67,
-	0x5a, 0x84, 0xff, 0xf2, 0x60, 0xf3, 0x40, 0x24, 0xe2, 0x83, 0x7f, 0x80, 0xd5, 0x8b, 0x51, 0x5b,
-	0x7b, 0x31, 0x9e, 0x00, 0xc8, 0xc9, 0x28, 0x95, 0x5a, 0xcb, 0x6c, 0xfa, 0x5f, 0x09, 0xf7, 0xe5,
-	0xe4, 0xa5, 0x35, 0x59, 0x75, 0xba, 0xc6, 0x7b, 0x3a, 0x5d, 0x73, 0xd5, 0xe9, 0x02, 0x68, 0xa6,
-	0xc2, 0x28, 0x39, 0xb6, 0xbf, 0x83, 0xfc, 0xa8, 0x10, 0x91, 0x85, 0x22, 0x64, 0xc7, 0x42, 0x0f,
-	0xba, 0xaf, 0x84, 0xa2, 0x00, 0x2d, 0x0b, 0xe1, 0x33, 0xe8, 0x0c, 0xae, 0xc5, 0xb8, 0xb0, 0xc0,
-	0x39, 0xd0, 0xd6, 0x83, 0x77, 0xb3, 0x23, 0x58, 0xfc, 0xbd, 0xd9, 0xfd, 0xc7, 0x0a, 0xb4, 0xed,
-	0x29, 0x1f, 0x94, 0x5a, 0x7a, 0xa6, 0xd3, 0x94, 0x67, 0xb1, 0xe3, 0xb6, 0x10, 0xd9, 0x63, 0xa8,
-	0x71, 0x35, 0x2d, 0xa6, 0xe3, 0x3b, 0x44, 0xeb, 0xca, 0x9f, 0xdd, 0xa7, 0x6a, 0xea, 0xe6, 0x62,
-	0x32, 0xbb, 0xd1, 0xcf, 0x1a, 0x37, 0xfb, 0x59, 0x7f, 0x1f, 0xfc, 0xe5, 0x96, 0xef, 0x3b, 0x2b,
-	0x3f, 0x82, 0xad, 0x25, 0xd5, 0x8e, 0xdb, 0x00, 0x9a, 0x6f, 0x2d, 0xe4, 0x4e, 0x2b, 0xc4, 0xf0,
-	0x6f, 0x15, 0xe8, 0x1e, 0x49, 0x6d, 0x72, 0xb5, 0xf8, 0xc0, 0x1c, 0xbe, 0x6f, 0x8e, 0xbc, 0x0d,
-	0x0d, 0x3e, 0x36, 0xab, 0xd6, 0xee, 0x24, 0xf6, 0x10, 0xba, 0xa9, 0xcc, 0xec, 0xf3, 0x3d, 0xc2,
-	0x1f, 0xd7, 0x8e, 0xaa, 0x4e, 0x8a, 0xe3, 0x0c, 0x57, 0xe6, 0x5c, 0xd2, 0x2f, 0xc3, 0x6e, 0xca,
-	0xaf, 0xcb, 0x56, 0x4d, 0x67, 0xc5, 0xaf, 0x57, 0x56, 0x6b, 0x13, 0x6f, 0xeb, 0xe6, 0xc4, 0x7b,
-	0x1f, 0xf0, 0xcc, 0x51, 0x3c, 0x57, 0xd4, 0x0b, 0x5c, 0xd9, 0xb7, 0x53, 0x99, 0x1d, 0x38, 0x88,
-	0x4c, 0xf8, 0xf5, 0xca, 0x04, 0x9c, 0x09, 0xbf, 0x2e, 0x4c, 0xbe, 0x7a, 0x05, 0x75, 0xfa, 0xe7,
-	0x81, 0xb5, 0xa0, 0x76, 0x72, 0x7a, 0x82, 0xbf, 0xf1, 0xdb, 0xd0, 0x3c, 0x3e, 0x39, 0x1f, 0x3c,
-	0x1f, 0x44, 0x3d, 0x0f, 0x7f, 0xf0, 0x1f, 0x0e, 0x4f, 0x9f, 0x9e, 0xf7, 0x2a, 0x0c, 0xa0, 0x71,
-	0x76, 0x1e, 0x1d, 0x9f, 0x3c, 0xef, 0x55, 0xd1, 0xfa, 0xfc, 0xf8, 0xe5, 0xa0, 0x57, 0x43, 0xeb,
-	0xfd, 0xd3, 0xd3, 0xe1, 0xe0, 0xe9, 0x49, 0xaf, 0x4e, 0x87, 0xfc, 0x7a, 0x38, 0xec, 0x35, 0xbe,
-	0x7a, 0x08, 0x9d, 0x72, 0x91, 0xa2, 0xe6, 0xf0, 0xe9, 0xf1, 0xb0, 0xb7, 0x81, 0xc7, 0x1c, 0x3f,
-	0x3f, 0x39, 0x8d, 0x06, 0x3d, 0x6f, 0xef, 0x1f, 0x15, 0x68, 0x1c, 0xda, 0x17, 0xe0, 0x87, 0x50,
-	0xc3, 0xa9, 0x9a, 0x51, 0xf3, 0x2d, 0xcd, 0xd7, 0xfd, 0x55, 0x39, 0x85, 0x1b, 0x3f, 0xf2, 0xd8,
-	0x13, 0xa8, 0xd3, 0x8b, 0xc2, 0xa8, 0x11, 0x94, 0x9f, 0xa8, 0x7e, 0x19, 0xa1, 0xe7, 0x26, 0xdc,
-	0xd8, 0xf1, 0xd8, 0x8f, 0xa1, 0x61, 0xfb, 0x1a, 0xa3, 0x5f, 0xba, 0x6b, 0x2f, 0x4a, 0x9f, 0x95,
-	0x21, 0x57, 0xf0, 0x1b, 0xb8, 0xc5, 0x36, 0x01, 0xbb, 0x65, 0xad, 0x07, 0xda, 0x2d, 0x37, 0x7a,
-	0xc4, 0x06, 0x7b, 0x04, 0x35, 0xac, 0x1e, 0xeb, 0x7e, 0xa9, 0x8e, 0xac, 0x53, 0xe5, 0x76, 0x11,
-	0x6e, 0xb0, 0xaf, 0xa1, 0xe9, 0x32, 0x97, 0xd1, 0x69, 0xeb, 0x69, 0x7c, 0x33, 0xe2, 0x9f, 0x40,
-	0xd3, 0x55, 0x85, 0xb5, 0x5e, 0xef, 0x46, 0xfd, 0x8f, 0xd7, 0xb0, 0xe2, 0x8e, 0x8b, 0x06, 0xfd,
-	0x65, 0xf5, 0xcd, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x12, 0xa6, 0xdd, 0xce, 0xc2, 0x12, 0x00,
-	0x00,
+	// 2311 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xcd, 0x72, 0x5b, 0xc7,
+	0xb1, 0x26, 0x70, 0x40, 0xfc, 0x34, 0x40, 0x10, 0x1a, 0xc9, 0xf2, 0x31, 0xfc, 0x23, 0xfa, 0xc8,
+	0xbe, 0x66, 0x49, 0x16, 0xed, 0x2b, 0xdf, 0xaa, 0x7b, 0xeb, 0x56, 0x25, 0x29, 0x52, 0x82, 0x24,
+	0x46, 0x14, 0xe9, 0x3a, 0x64, 0xec, 0xaa, 0x6c, 0x90, 0x21, 0xce, 0x00, 0x9a, 0xf0, 0xfc, 0xc0,
+	0x33, 0x03, 0x91, 0xf0, 0x22, 0x0f, 0x91, 0xaa, 0x3c, 0x81, 0xf7, 0x59, 0xe4, 0x15, 0xb2, 0x4a,
+	0x55, 0xb2, 0xca, 0x6b, 0x64, 0x93, 0x55, 0xb6, 0xa9, 0xee, 0x99, 0xf3, 0x03, 0x88, 0x76, 0x55,
+	0x5c, 0xd6, 0x6e, 0xfa, 0xeb, 0x9e, 0x99, 0xee, 0x6f, 0xba, 0x7b, 0xe6, 0x1c, 0xe8, 0x4d, 0x15,
+	0x4f, 0x84, 0xde, 0x9b, 0xab, 0xcc, 0x64, 0xac, 0x3e, 0x3f, 0x0f, 0xbe, 0xab, 0x43, 0xf3, 0x51,
+	0x16, 0x2f, 0x92, 0x94, 0xdd, 0x85, 0xc6, 0x85, 0x4c, 0x23, 0xbf, 0xb6, 0x53, 0xdb, 0xed, 0x3f,
+	0xdc, 0xde, 0x9b, 0x9f, 0xef, 0x59, 0xcd, 0xde, 0x73, 0x99, 0x46, 0x21, 0x29, 0x19, 0x83, 0x46,
+	0xca, 0x13, 0xe1, 0xd7, 0x77, 0x6a, 0xbb, 0x9d, 0x90, 0xc6, 0xec, 0x0e, 0x6c, 0x46, 0x66, 0x39,
+	0x17, 0xbe, 0x47, 0x33, 0x3b, 0x38, 0xf3, 0xf1, 0xd9, 0x72, 0x2e, 0x42, 0x8b, 0xe3, 0x24, 0x2d,
+	0xbf, 0x15, 0x7e, 0x63, 0xa7, 0xb6, 0xeb, 0x85, 0x34, 0x46, 0x4c, 0xa6, 0x46, 0xfb, 0x9b, 0x3b,
+	0x1e, 0x62, 0x38, 0x66, 0xb7, 0xa1, 0x39, 0x8d, 0x33, 0x6e, 0xb4, 0xdf, 0xdc, 0xf1, 0x76, 0x6b,
+	0xa1, 0x93, 0x98, 0x0f, 0x2d, 0x6d, 0x94, 0x4c, 0x67, 0xda, 0x6f, 0xed, 0x78, 0xbb, 0x9d, 0x30,
+	0x17, 0xd9, 0x2d, 0xd8, 0x34, 0x32, 0x11, 0xda, 0x6f, 0xd3, 0x32, 0x56, 0x40, 0xf4, 0x3c, 0xcb,
+	0x62, 0xed, 0x77, 0x76, 0xbc, 0xdd, 0x76, 0x68, 0x05, 0xf6, 0x2e, 0x74, 0x50, 0x3d, 0xfe, 0x36,
+	0x4b, 0x85, 0x0f, 0xe4, 0x7f, 0x1b, 0x81, 0x5f, 0x67, 0xa9, 0x08, 0xde, 0x83, 0x06, 0x46, 0xc9,
+	0x3a, 0xb0, 0x79, 0x7a, 0x74, 0xf8, 0x68, 0x34, 0xd8, 0xc0, 0xe1, 0xd1, 0xfe, 0xc1, 0xe8, 0x68,
+	0x50, 0x0b, 0x7e, 0x07, 0x9b, 0x5f, 0xf1, 0x78, 0x21, 0xd8, 0x2d, 0x68, 0xc8, 0x57, 0x3c, 0x26,
+	0x8e, 0xbc, 0x67, 0x1b, 0x21, 0x49, 0x88, 0x4e, 0x11, 0x45, 0x52, 0x6a, 0x88, 0x4e, 0x1d, 0xaa,
+	0x11, 0x45, 0x56, 0x3a, 0x88, 0x6a, 0x87, 0x1a, 0x44, 0x1b, 0xf9, 0x0a, 0xc6, 0xa1, 0xe7, 0x88,
+	0x6e, 0xee, 0xd4, 0x76, 0xdb, 0x88, 0xa2, 0x74, 0xd0, 0x82, 0xcd, 0x57, 0xb8, 0x6d, 0xf0, 0x87,
+	0x1a, 0x6c, 0x1d, 0x2f, 0xe2, 0x98, 0x9c, 0xd0, 0x2f, 0xf8, 0x9c, 0x3d, 0x86, 0x6e, 0xba, 0x88,
+	0x63, 0x7b, 0x40, 0xda, 0xaf, 0xed, 0x78, 0xbb, 0xdd, 0x87, 0x01, 0x32, 0xbf, 0x62, 0xb7, 0x77,
+	0x5c, 0x1a, 0x8d, 0x52, 0xa3, 0x96, 0x61, 0x75, 0xda, 0xf0, 0xe7, 0x30, 0x58, 0x37, 0x60, 0x03,
+	0xf0, 0x2e, 0xc4, 0x92, 0x22, 0xec, 0x84, 0x38, 0x64, 0xb7, 0x9c, 0x1b, 0x14, 0x5f, 0x3b, 0xb4,
+	0xc2, 0xff, 0xd7, 0xff, 0xaf, 0x16, 0xfc, 0xbe, 0x0e, 0x9b, 0x4f, 0x30, 0xa5, 0xd8, 0x47, 0xd0,
+	0x9a, 0xac, 0xf8, 0x02, 0x65, 0xfe, 0x84, 0xb9, 0x0a, 0xad, 0x64, 0x1a, 0xc9, 0x89, 0xd0, 0x7e,
+	0xfd, 0x75, 0x2b, 0xa7, 0x62, 0x0f, 0xa0, 0x19, 0xf3, 0x73, 0x11, 0x6b, 0xdf, 0x23, 0xa3, 0xb7,
+	0xd0, 0x88, 0xb6, 0xd9, 0x3b, 0x22, 0xdc, 0x46, 0xe2, 0x8c, 0xd0, 0x3d, 0xa1, 0x54, 0xa6, 0x88,
+	0xd2, 0x4e, 0x68, 0x05, 0xf6, 0xd0, 0x12, 0x34, 0x26, 0x67, 0x6d, 0x9a, 0x75, 0x1f, 0xde, 0x78,
+	0x8d, 0xa0, 0x10, 0xd2, 0x42, 0x1c, 0x3e, 0x86, 0x6e, 0x65, 0x83, 0x6b, 0x98, 0xb8, 0x53, 0x65,
+	0xa2, 0x6b, 0x33, 0x9d, 0xe6, 0x56, 0x49, 0xf9, 0x57, 0x0d, 0xba, 0xa7, 0x93, 0x97, 0x22, 0xe1,
+	0x4f, 0xa4, 0x88, 0xcb, 0x92, 0xa9, 0x55, 0x4a, 0x66, 0x00, 0x5e, 0x94, 0x4d, 0x5c, 0x15, 0xe1,
+	0x90, 0xdd, 0x85, 0x56, 0x24, 0xa6, 0x7c, 0x11, 0x1b, 0xdf, 0x5b, 0x5f, 0x3c, 0xd7, 0xe0, 0x52,
+	0x54, 0x68, 0x36, 0x52, 0x1a, 0xb3, 0x5f, 0x00, 0xcc, 0x55, 0x36, 0x17, 0xca, 0xc8, 0x22, 0xce,
+	0x3b, 0x38, 0xb7, 0xe2, 0xc3, 0xde, 0x97, 0x85, 0x85, 0xe5, 0xae, 0x32, 0x65, 0xf8, 0x0c, 0xb6,
+	0xd7, 0xd4, 0x3f, 0x36, 0xf2, 0x13, 0xe8, 0xd8, 0x4d, 0x9f, 0x8b, 0x25, 0xfb, 0x10, 0x7a, 0xfa,
+	0x25, 0x57, 0x91, 0x4c, 0x67, 0x63, 0xbb, 0x18, 0x56, 0x6e, 0x37, 0xc7, 0x9e, 0xd3, 0xa2, 0x5d,
+	0x9d, 0x29, 0x93, 0x5b, 0xd4, 0xc9, 0x02, 0x1c, 0xf4, 0x5c, 0x2c, 0x83, 0xbf, 0xd4, 0xa0, 0x7b,
+	0xc6, 0xcf, 0x63, 0x61, 0x97, 0x2d, 0xe2, 0xaf, 0x55, 0xe2, 0x7f, 0x0f, 0x3a, 0x48, 0xa9, 0x9e,
+	0xf3, 0x49, 0xde, 0x96, 0x4a, 0xa0, 0x20, 0xdf, 0x7b, 0x9d, 0xfc, 0x46, 0x49, 0xbe, 0x0f, 0x2d,
+	0x1e, 0x4b, 0xae, 0x1d, 0x81, 0x9d, 0x30, 0x17, 0xd9, 0x27, 0xd0, 0x9c, 0x22, 0x83, 0xb6, 0x25,
+	0x75, 0x6d, 0x5b, 0xac, 0x30, 0x1b, 0x3a, 0x35, 0xbb, 0x63, 0x29, 0x6b, 0x11, 0x3d, 0x5b, 0xa5,
+	0xd5, 0x73, 0xb1, 0x24, 0x06, 0x83, 0x1e, 0xc0, 0x2f, 0x33, 0x99, 0x9e, 0x1a, 0xb5, 0x98, 0x98,
+	0xe0, 0xbb, 0x1a, 0xb4, 0x4e, 0x85, 0xd6, 0x32, 0x4b, 0xd1, 0x9f, 0x85, 0x8a, 0x73, 0xb6, 0x17,
+	0x2a, 0xc6, 0x98, 0x26, 0x59, 0x6a, 0xb8, 0x4c, 0x85, 0xca, 0x63, 0x2a, 0x00, 0x8c, 0x69, 0xce,
+	0xcd, 0xcb, 0x3c, 0x26, 0x1c, 0x23, 0xb6, 0xd0, 0x22, 0xaf, 0x01, 0x1a, 0xb3, 0x21, 0xb4, 0xe7,
+	0x5c, 0xeb, 0xcb, 0x4c, 0x45, 0xd4, 0x58, 0x3a, 0x61, 0x21, 0x53, 0xe3, 0xcc, 0x2e, 0x44, 0xea,
+	0x37, 0x6d, 0xd1, 0x90, 0xc0, 0xfa, 0x50, 0x97, 0x11, 0xc5, 0xd0, 0x09, 0xeb, 0x32, 0x0a, 0xfe,
+	0xd6, 0x82, 0x6e, 0x28, 0x78, 0x14, 0x8a, 0x6f, 0x16, 0x42, 0x1b, 0xf6, 0x31, 0xb4, 0xb4, 0x75,
+	0x9a, 0xbc, 0xed, 0x3e, 0xec, 0x52, 0xa0, 0x16, 0x0a, 0x73, 0x1d, 0xd2, 0x79, 0xce, 0x27, 0x17,
+	0x22, 0x8d, 0x9c, 0xf3, 0xb9, 0x88, 0x74, 0x6a, 0xa2, 0xc5, 0x25, 0x39, 0xd1, 0x59, 0x39, 0xe1,
+	0xd0, 0xa9, 0x31, 0x35, 0x22, 0x6e, 0xf8, 0x78, 0x9a, 0xa9, 0x84, 0x1b, 0x17, 0x16, 0x20, 0xf4,
+	0x84, 0x10, 0xf6, 0x3e, 0x80, 0xca, 0x2e, 0xc7, 0x31, 0x5f, 0x66, 0x0b, 0x63, 0xfb, 0x66, 0xd8,
+	0x51, 0xd9, 0xe5, 0x11, 0x01, 0x38, 0x3f, 0x59, 0xc4, 0x46, 0x8e, 0x65, 0x1a, 0x89, 0x2b, 0x8a,
+	0xb2, 0x1d, 0x02, 0x41, 0x87, 0x88, 0x20, 0x01, 0xdf, 0x2c, 0x84, 0x5a, 0xba, 0x68, 0xad, 0x80,
+	0xa8, 0x41, 0x6f, 0xfc, 0xb6, 0x45, 0x49, 0xc0, 0x78, 0xf2, 0xe6, 0xd6, 0xb1, 0xe9, 0xe1, 0x44,
+	0xba, 0xb1, 0x64, 0x6c, 0x84, 0x72, 0x17, 0x8a, 0x93, 0xd8, 0x3b, 0xd0, 0x9e, 0xa9, 0x6c, 0x31,
+	0x1f, 0x9f, 0x2f, 0xfd, 0xae, 0xa5, 0x80, 0xe4, 0x83, 0x25, 0x0b, 0xa0, 0xf1, 0xdb, 0x4c, 0xa6,
+	0x7e, 0x8f, 0xf2, 0xa9, 0x8f, 0x04, 0x94, 0x79, 0x11, 0x92, 0x0e, 0xdd, 0x88, 0x65, 0x22, 0x8d,
+	0xbf, 0x45, 0x37, 0xa6, 0x15, 0xd8, 0x5d, 0xd8, 0x4a, 0x84, 0xd6, 0x7c, 0x26, 0xc6, 0x56, 0xdb,
+	0x27, 0x6d, 0xcf, 0x81, 0x47, 0x64, 0x74, 0x1b, 0x9a, 0x09, 0x57, 0x17, 0x42, 0xf9, 0xdb, 0xd6,
+	0x23, 0x2b, 0x21, 0x21, 0x4a, 0x68, 0x61, 0x1c, 0x21, 0xef, 0x5b, 0x42, 0x08, 0xb2, 0x84, 0x0c,
+	0xa1, 0xad, 0xc5, 0x2c, 0x11, 0x78, 0x29, 0x0f, 0xe8, 0x36, 0x2d, 0x64, 0xf6, 0x31, 0xf4, 0x4d,
+	0x66, 0x78, 0x3c, 0x2e, 0x2c, 0x6e, 0xd0, 0xd6, 0x5b, 0x84, 0x9e, 0xe6, 0x66, 0x77, 0x61, 0xab,
+	0x5a, 0xf2, 0xda, 0x67, 0xc4, 0x56, 0xaf, 0x52, 0xf3, 0x9a, 0x7d, 0x06, 0xb7, 0xb0, 0xc2, 0xd1,
+	0x60, 0xac, 0x78, 0x3a, 0x13, 0x63, 0x6d, 0xb8, 0x32, 0xfe, 0x4d, 0x72, 0xf7, 0x06, 0xea, 0xb0,
+	0x66, 0x50, 0x73, 0x8a, 0x0a, 0x76, 0x1f, 0xd8, 0xda, 0x04, 0x4c, 0xac, 0x5b, 0x64, 0xbe, 0x5d,
+	0x35, 0x1f, 0xa5, 0x94, 0xd7, 0x76, 0xb9, 0xb7, 0xec, 0x01, 0x92, 0x80, 0x15, 0x86, 0x73, 0x6e,
+	0xdb, 0x0a, 0x13, 0xf6, 0x1d, 0xa3, 0x8d, 0x98, 0xfb, 0x6f, 0xdb, 0x7a, 0xc1, 0x31, 0xdb, 0x81,
+	0x2e, 0x9f, 0xcd, 0x94, 0x98, 0x71, 0x93, 0x29, 0xed, 0xfb, 0xa4, 0xaa, 0x42, 0xec, 0x01, 0xb0,
+	0x5c, 0x94, 0x59, 0x3a, 0xbe, 0x94, 0x69, 0x94, 0x5d, 0xfa, 0xef, 0x59, 0xcf, 0x2b, 0x9a, 0xaf,
+	0x49, 0x41, 0x9b, 0x08, 0x71, 0xe1, 0xbf, 0xe3, 0x36, 0x11, 0xe2, 0x02, 0x33, 0x83, 0xe8, 0x18,
+	0xcb, 0xc8, 0x1f, 0xda, 0xcc, 0x20, 0xf9, 0x30, 0xb2, 0x27, 0xf0, 0xcd, 0x42, 0xa4, 0x13, 0xe1,
+	0xbf, 0x4b, 0xfc, 0x16, 0xf2, 0xea, 0xe3, 0xe5, 0x83, 0xd5, 0xc7, 0x0b, 0xfb, 0x04, 0xb6, 0x65,
+	0x3a, 0x89, 0x17, 0x91, 0x18, 0x8b, 0xab, 0xb9, 0x54, 0x22, 0xf2, 0xef, 0xd0, 0xf9, 0xf6, 0x1d,
+	0x3c, 0xb2, 0x68, 0xf0, 0x77, 0x0f, 0x6e, 0x1e, 0xa6, 0xd2, 0x48, 0x1e, 0x7f, 0xad, 0xa4, 0x11,
+	0x3f, 0x59, 0x5d, 0x17, 0x75, 0xe3, 0x55, 0xeb, 0xe6, 0x53, 0xe8, 0x49, 0xbb, 0xdb, 0x18, 0x2b,
+	0xd7, 0x6f, 0x94, 0x77, 0x07, 0x5d, 0xe7, 0x61, 0xd7, 0xa9, 0x1f, 0x73, 0xc3, 0xd9, 0x07, 0x00,
+	0xe2, 0x6a, 0xae, 0x9c, 0x1f, 0xb6, 0x61, 0x55, 0x10, 0x64, 0x33, 0xc9, 0x94, 0x70, 0xb5, 0x4c,
+	0x63, 0x4c, 0xcc, 0x39, 0x57, 0x46, 0xd2, 0x71, 0x50, 0xca, 0xd9, 0x07, 0xe2, 0x56, 0x81, 0x52,
+	0xce, 0xd9, 0x7e, 0x1a, 0x11, 0xe0, 0x4a, 0xbb, 0x04, 0x90, 0x5b, 0xcd, 0x5f, 0x89, 0x71, 0x92,
+	0x45, 0xc2, 0xef, 0x58, 0x6e, 0x11, 0x78, 0x91, 0x45, 0x82, 0xdd, 0x83, 0x1b, 0xd8, 0x79, 0x65,
+	0xba, 0x10, 0xe3, 0x2c, 0x1d, 0xdb, 0x97, 0x06, 0x90, 0x0b, 0xdb, 0xb9, 0xe2, 0x24, 0x1d, 0x21,
+	0xbc, 0x7a, 0x48, 0xdd, 0xb5, 0x43, 0xc2, 0x1a, 0x42, 0xe5, 0x5c, 0x89, 0x89, 0xa4, 0x10, 0x7b,
+	0x64, 0xb1, 0x85, 0xe8, 0x97, 0x39, 0x88, 0xa9, 0x6a, 0x4c, 0x4c, 0x85, 0xdf, 0x09, 0x71, 0x48,
+	0xb7, 0x24, 0x35, 0x45, 0xeb, 0x60, 0xdf, 0x12, 0x63, 0x21, 0x74, 0x31, 0x48, 0xa1, 0xb7, 0x72,
+	0x9a, 0x5f, 0x40, 0x4b, 0xd9, 0xa1, 0x3b, 0xcd, 0xb7, 0x91, 0xf1, 0x6b, 0xce, 0xfd, 0xd9, 0x46,
+	0x98, 0x5b, 0xb2, 0x0f, 0x61, 0x93, 0x3e, 0x0e, 0xfc, 0xfa, 0xda, 0x21, 0x3d, 0xdb, 0x08, 0xad,
+	0xe6, 0xa0, 0x69, 0x6f, 0xdf, 0x60, 0x5a, 0xec, 0xa7, 0xe7, 0x99, 0x16, 0xd4, 0x04, 0xd1, 0x40,
+	0xdb, 0x67, 0x71, 0xe8, 0x24, 0x3c, 0x30, 0x95, 0x5d, 0x6a, 0x5a, 0xd1, 0x0b, 0x69, 0xcc, 0xee,
+	0x41, 0x77, 0xca, 0x65, 0x2c, 0xa2, 0x31, 0xa9, 0xbc, 0xf5, 0x8c, 0x00, 0xab, 0x0d, 0xb3, 0x4b,
+	0x1d, 0xfc, 0xa3, 0x0e, 0x5b, 0x8f, 0x94, 0xe0, 0x6f, 0x3c, 0x4f, 0xcb, 0x5b, 0xa9, 0xf1, 0xc3,
+	0xb7, 0xd2, 0x03, 0xe8, 0xc8, 0xe9, 0x58, 0x5c, 0x49, 0x4d, 0x5f, 0x2e, 0xf8, 0xb5, 0x33, 0x40,
+	0x5b, 0x3a, 0xfe, 0x93, 0x39, 0x66, 0x93, 0x0e, 0xdb, 0x72, 0x3a, 0x22, 0x0b, 0x22, 0x80, 0x1b,
+	0xe1, 0xee, 0x58, 0x1a, 0x63, 0x96, 0xe7, 0x8d, 0x42, 0x68, 0x77, 0xf9, 0x54, 0x10, 0xf6, 0xbf,
+	0xf0, 0x76, 0xb5, 0xc5, 0xcc, 0x14, 0x4f, 0x17, 0x31, 0x57, 0xd2, 0x2c, 0x5d, 0xe2, 0xde, 0xae,
+	0xa8, 0x9f, 0x96, 0x5a, 0x3c, 0x05, 0x6a, 0x24, 0x9a, 0x52, 0xd8, 0x0b, 0x9d, 0x84, 0xcd, 0x41,
+	0x09, 0x23, 0x52, 0x5a, 0xee, 0x65, 0xb6, 0x50, 0x9a, 0xd2, 0xd7, 0x0b, 0xfb, 0x05, 0xfc, 0x0c,
+	0xd1, 0x60, 0x00, 0xfd, 0x9c, 0x6d, 0x3d, 0xcf, 0x52, 0x2d, 0x82, 0x7f, 0xd6, 0x60, 0xeb, 0xb1,
+	0x88, 0xc5, 0x1b, 0x3f, 0x80, 0xf2, 0x1a, 0x6d, 0xac, 0x5c, 0xa3, 0x9f, 0x01, 0xc8, 0xe9, 0x38,
+	0x91, 0x5a, 0xcb, 0x74, 0xf6, 0xbd, 0x84, 0x77, 0xe4, 0xf4, 0x85, 0x35, 0x29, 0xdb, 0x7f, 0xf3,
+	0x9a, 0xf6, 0xdf, 0x2a, 0xdb, 0xbf, 0x0f, 0xad, 0x44, 0x18, 0x25, 0x27, 0xf6, 0xcb, 0xb1, 0x13,
+	0xe6, 0x22, 0xb2, 0x90, 0x87, 0xec, 0x58, 0x18, 0x40, 0xff, 0x2b, 0xa1, 0x28, 0x40, 0xcb, 0x42,
+	0xf0, 0x08, 0x7a, 0xa3, 0x2b, 0x31, 0xc9, 0x2d, 0xf0, 0x71, 0x6c, 0x6b, 0xa7, 0xb6, 0x9e, 0xce,
+	0x16, 0xbf, 0xae, 0x12, 0x82, 0x3f, 0xd5, 0xa1, 0x6b, 0x57, 0x79, 0xa3, 0xd4, 0xd2, 0xdb, 0x25,
+	0x49, 0x78, 0x1a, 0x39, 0x6e, 0x73, 0x91, 0x3d, 0x80, 0x06, 0x57, 0xb3, 0xfc, 0x93, 0xe1, 0x1d,
+	0xa2, 0xb5, 0xf4, 0x67, 0x6f, 0x5f, 0xcd, 0xdc, 0xc7, 0x02, 0x99, 0xad, 0xb5, 0xe7, 0xe6, 0x6b,
+	0xed, 0xb9, 0x20, 0xa1, 0x75, 0x3d, 0x09, 0xc3, 0x03, 0xe8, 0x14, 0x6b, 0xfe, 0xd8, 0x2f, 0x8c,
+	0xfb, 0xb0, 0x5d, 0x9c, 0x85, 0x23, 0xdf, 0x87, 0xd6, 0x2b, 0x0b, 0xb9, 0xd5, 0x72, 0x31, 0xf8,
+	0x73, 0x1d, 0xfa, 0xcf, 0xa4, 0x36, 0x99, 0x5a, 0xbe, 0x61, 0x92, 0xaf, 0x7b, 0x7d, 0xdf, 0x86,
+	0x26, 0x9f, 0x98, 0xf2, 0x2a, 0x73, 0x12, 0xfb, 0x08, 0xfa, 0x89, 0x4c, 0xed, 0xa3, 0x67, 0x8c,
+	0xbd, 0xdf, 0x71, 0xd9, 0x4b, 0xf0, 0x11, 0xc8, 0x95, 0x39, 0x93, 0xf4, 0x3d, 0xdd, 0x4f, 0xf8,
+	0x55, 0xd5, 0xaa, 0xe5, 0xac, 0xf8, 0x55, 0x69, 0xb5, 0xf2, 0x9d, 0xd0, 0x5e, 0xff, 0x4e, 0xf8,
+	0x10, 0x70, 0xcd, 0x71, 0xb4, 0x50, 0xd4, 0x2c, 0x5c, 0x5f, 0xe8, 0x26, 0x32, 0x7d, 0xec, 0x20,
+	0x32, 0xe1, 0x57, 0xa5, 0x09, 0x38, 0x13, 0x7e, 0x95, 0x9b, 0x04, 0xbf, 0x81, 0x36, 0x75, 0xfb,
+	0xfd, 0xc9, 0x05, 0x46, 0x5f, 0x26, 0xba, 0xf7, 0x03, 0xd9, 0xfd, 0x1f, 0xf5, 0xf9, 0x0c, 0xb6,
+	0x0f, 0xb8, 0x99, 0xbc, 0xa4, 0x6d, 0xa8, 0xed, 0x96, 0x34, 0xd7, 0xaa, 0x34, 0xdf, 0xf9, 0xbe,
+	0x3b, 0x2a, 0xf7, 0xe4, 0xf5, 0xe7, 0x80, 0x77, 0xcd, 0x73, 0x20, 0xf8, 0x63, 0x0d, 0x6e, 0x94,
+	0x3b, 0xfe, 0x64, 0xb9, 0x71, 0x1f, 0x9a, 0xe4, 0x67, 0xfe, 0xdf, 0xe2, 0x26, 0xce, 0x5f, 0x8b,
+	0x2c, 0x74, 0x26, 0xec, 0x3e, 0xdc, 0x70, 0x79, 0x3a, 0xe6, 0xc6, 0x28, 0x79, 0xbe, 0x30, 0xf9,
+	0x77, 0xfd, 0xc0, 0x29, 0xf6, 0x73, 0x3c, 0x88, 0x61, 0x40, 0xeb, 0xd8, 0x25, 0x84, 0xc6, 0x7f,
+	0x01, 0xd7, 0x53, 0x84, 0xb7, 0x80, 0xe1, 0x66, 0xa1, 0x9d, 0x73, 0x4e, 0x42, 0x6b, 0x69, 0x44,
+	0x62, 0x4f, 0xc2, 0x0b, 0xad, 0x70, 0xfd, 0xaf, 0x93, 0xe0, 0x15, 0xb0, 0x2a, 0x3b, 0xae, 0xce,
+	0x28, 0xd7, 0x92, 0x44, 0x1a, 0x23, 0xec, 0x3f, 0xc2, 0x76, 0x58, 0x02, 0xec, 0xd3, 0x22, 0x76,
+	0xfb, 0x63, 0xe7, 0x56, 0x11, 0x7b, 0xc5, 0xe7, 0x22, 0xf8, 0x62, 0x5f, 0xaf, 0xb2, 0xef, 0xbd,
+	0xaf, 0x60, 0x93, 0x7e, 0x1b, 0xb2, 0x36, 0x34, 0x8e, 0x4f, 0x8e, 0xf1, 0x1f, 0x5c, 0x17, 0x5a,
+	0x87, 0xc7, 0x67, 0xa3, 0xa7, 0xa3, 0x70, 0x50, 0xc3, 0x1f, 0x72, 0x4f, 0x8e, 0x4e, 0xf6, 0xcf,
+	0x06, 0x75, 0x06, 0xd0, 0x3c, 0x3d, 0x0b, 0x0f, 0x8f, 0x9f, 0x0e, 0x3c, 0xb4, 0x3e, 0x3b, 0x7c,
+	0x31, 0x1a, 0x34, 0xd0, 0xfa, 0xe0, 0xe4, 0xe4, 0x68, 0xb4, 0x7f, 0x3c, 0xd8, 0xa4, 0x45, 0x7e,
+	0x75, 0x74, 0x34, 0x68, 0xde, 0xfb, 0x08, 0x7a, 0xd5, 0xfb, 0x02, 0x35, 0x4f, 0xf6, 0x0f, 0x8f,
+	0x06, 0x1b, 0xb8, 0xcc, 0xe1, 0xd3, 0xe3, 0x93, 0x70, 0x34, 0xa8, 0x3d, 0xfc, 0xab, 0x07, 0xcd,
+	0x27, 0xf6, 0xe1, 0xf2, 0x5f, 0xd0, 0xc0, 0xaf, 0x5e, 0x46, 0xef, 0x80, 0xca, 0xf7, 0xef, 0xb0,
+	0xcc, 0xb8, 0x60, 0xe3, 0xf3, 0x1a, 0xfb, 0x0c, 0x36, 0x89, 0x23, 0x46, 0x77, 0x52, 0x35, 0x99,
+	0x86, 0x55, 0x84, 0x5e, 0x49, 0xc1, 0xc6, 0x6e, 0x8d, 0xfd, 0x37, 0x34, 0xed, 0x15, 0xcb, 0xe8,
+	0x4f, 0xd4, 0xca, 0xe3, 0x66, 0xc8, 0xaa, 0x90, 0xbb, 0x7b, 0x36, 0x70, 0x8a, 0xbd, 0x8f, 0xec,
+	0x94, 0x95, 0xeb, 0x78, 0xc8, 0xaa, 0x50, 0x31, 0xe5, 0x3e, 0x34, 0xb0, 0x91, 0x5b, 0xf7, 0x2b,
+	0x2d, 0x7d, 0x38, 0x28, 0x81, 0xc2, 0xf8, 0x53, 0x68, 0xb9, 0x1e, 0xc9, 0x68, 0xb5, 0xd5, 0x86,
+	0xb9, 0x1e, 0xf1, 0xff, 0x40, 0xcb, 0xf5, 0x5f, 0x6b, 0xbd, 0x7a, 0x31, 0x0e, 0x6f, 0xae, 0x60,
+	0xc5, 0x1e, 0x5f, 0x40, 0x97, 0xa8, 0x38, 0x35, 0x4a, 0xf0, 0xe4, 0x1a, 0xb6, 0x7a, 0x05, 0xb2,
+	0x3f, 0xb9, 0x40, 0xa6, 0x3e, 0xaf, 0xb1, 0x9f, 0x01, 0x94, 0x59, 0xc8, 0xde, 0x5a, 0xad, 0xa5,
+	0x7c, 0xe2, 0xed, 0x75, 0x38, 0xdf, 0xf3, 0xbc, 0x49, 0xff, 0xb8, 0xbf, 0xf8, 0xf7, 0x00, 0xc7,
+	0x1e, 0x37, 0xd6, 0xf3, 0x16, 0x00, 0x00,
 }
@@ -0,0 +1,103 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/v3io/frames/pb"
+)
+
+// RetryPolicy is the retry policy of idempotent client calls
+type RetryPolicy struct {
+	MaxAttempts    int           // including the first call, 1 disables retries
+	InitialBackoff time.Duration // backoff before the first retry
+	MaxBackoff     time.Duration
+	Multiplier     float64 // backoff multiplier between retries
+}
+
+// DefaultRetryPolicy returns the default retry policy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// Validate validates the policy
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("retry MaxAttempts must be at least 1 (got %d)", p.MaxAttempts)
+	}
+
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 || p.Multiplier < 1 {
+		return fmt.Errorf("bad retry backoff - %+v", p)
+	}
+
+	return nil
+}
+
+// Backoff returns the time to wait before retry number n (starting at 1),
+// with ±20% jitter
+func (p RetryPolicy) Backoff(n int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(n-1))
+	if max := float64(p.MaxBackoff); backoff > max {
+		backoff = max
+	}
+
+	jitter := 0.8 + 0.4*rand.Float64()
+	return time.Duration(backoff * jitter)
+}
+
+// Retry calls fn until it succeeds, it returns an error that is not
+// retryable, attempts are exhausted or ctx is done
+func (p RetryPolicy) Retry(ctx context.Context, retryable func(error) bool, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt >= p.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// IdempotentCreate returns true if creating a table can be retried
+func IdempotentCreate(request *pb.CreateRequest) bool {
+	return request.IfExists == pb.ErrorOptions_IGNORE
+}
+
+// IdempotentDelete returns true if a delete can be retried
+func IdempotentDelete(request *pb.DeleteRequest) bool {
+	return request.IfMissing == pb.ErrorOptions_IGNORE
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 2}
+	errTemporary := fmt.Errorf("temporary")
+	errPermanent := fmt.Errorf("permanent")
+	retryable := func(err error) bool { return err == errTemporary }
+
+	testCases := []struct {
+		name     string
+		errors   []error
+		err      error
+		attempts int
+	}{
+		{"success", []error{nil}, nil, 1},
+		{"retry", []error{errTemporary, errTemporary, nil}, nil, 3},
+		{"exhausted", []error{errTemporary, errTemporary, errTemporary}, errTemporary, 3},
+		{"permanent", []error{errTemporary, errPermanent}, errPermanent, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			err := policy.Retry(context.Background(), retryable, func() error {
+				err := tc.errors[attempts]
+				attempts++
+				return err
+			})
+
+			if err != tc.err {
+				t.Fatalf("bad error: %v != %v", err, tc.err)
+			}
+
+			if attempts != tc.attempts {
+				t.Fatalf("bad number of attempts: %d != %d", attempts, tc.attempts)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts := 0
+	err := policy.Retry(ctx, retryable, func() error {
+		attempts++
+		return errTemporary
+	})
+	if err != errTemporary || attempts != 1 {
+		t.Fatalf("retry after cancel: %v, %d attempts", err, attempts)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	for n := 1; n < 10; n++ {
+		backoff := policy.Backoff(n)
+		if backoff < policy.InitialBackoff*8/10 || backoff > policy.MaxBackoff*12/10 {
+			t.Fatalf("retry %d: backoff %s out of range", n, backoff)
+		}
+	}
+
+	if err := (RetryPolicy{MaxAttempts: 0}).Validate(); err == nil {
+		t.Fatal("no error on 0 attempts")
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"fmt"
+)
+
+// Failed rows frame columns
+const (
+	FailedRowKeyColumn     = "key"
+	FailedRowCodeColumn    = "error_code"
+	FailedRowMessageColumn = "message"
+)
+
+// RowError is a row that failed to write
+type RowError struct {
+	Key     string `json:"key"`
+	Code    string `json:"error_code"`
+	Message string `json:"message"`
+}
+
+// NewFailedRowsFrame returns a frame with key, error_code and message columns
+// from rowErrors, nil if rowErrors is empty
+func NewFailedRowsFrame(rowErrors []RowError) (Frame, error) {
+	if len(rowErrors) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(rowErrors))
+	codes := make([]string, len(rowErrors))
+	messages := make([]string, len(rowErrors))
+	for i, rowError := range rowErrors {
+		keys[i] = rowError.Key
+		codes[i] = rowError.Code
+		messages[i] = rowError.Message
+	}
+
+	data := map[string][]string{
+		FailedRowKeyColumn:     keys,
+		FailedRowCodeColumn:    codes,
+		FailedRowMessageColumn: messages,
+	}
+
+	var columns []Column
+	for _, name := range []string{FailedRowKeyColumn, FailedRowCodeColumn, FailedRowMessageColumn} {
+		col, err := NewSliceColumn(name, data[name])
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+
+	return NewFrame(columns, nil, nil)
+}
+
+// RowErrorsFromFrame returns the row errors in a frame created by
+// NewFailedRowsFrame
+func RowErrorsFromFrame(frame Frame) ([]RowError, error) {
+	if frame == nil {
+		return nil, nil
+	}
+
+	var columns [3]Column
+	for i, name := range []string{FailedRowKeyColumn, FailedRowCodeColumn, FailedRowMessageColumn} {
+		col, err := frame.Column(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if col.DType() != StringType {
+			return nil, fmt.Errorf("%q column is not a string column", name)
+		}
+		columns[i] = col
+	}
+
+	rowErrors := make([]RowError, frame.Len())
+	for i := range rowErrors {
+		var err error
+		if rowErrors[i].Key, err = columns[0].StringAt(i); err != nil {
+			return nil, err
+		}
+		if rowErrors[i].Code, err = columns[1].StringAt(i); err != nil {
+			return nil, err
+		}
+		if rowErrors[i].Message, err = columns[2].StringAt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return rowErrors, nil
+}
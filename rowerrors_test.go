@@ -0,0 +1,60 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFailedRowsFrame(t *testing.T) {
+	rowErrors := []RowError{
+		{Key: "a", Code: "-201326594", Message: "Condition evaluated to false"},
+		{Key: "b", Code: "503", Message: "service unavailable"},
+	}
+
+	frame, err := NewFailedRowsFrame(rowErrors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frame.Len() != len(rowErrors) {
+		t.Fatalf("bad frame length: %d != %d", frame.Len(), len(rowErrors))
+	}
+
+	out, err := RowErrorsFromFrame(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(out, rowErrors) {
+		t.Fatalf("bad row errors: %+v != %+v", out, rowErrors)
+	}
+
+	frame, err = NewFailedRowsFrame(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frame != nil {
+		t.Fatalf("non nil frame for no failed rows")
+	}
+}
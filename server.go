@@ -20,6 +20,14 @@ such restriction.
 
 package frames
 
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
 // ServerState is state of server
 type ServerState string
 
@@ -70,3 +78,69 @@ func (s *ServerBase) SetError(err error) {
 	s.err = err
 	s.state = ErrorState
 }
+
+// DefaultUnixSocketMode is the default permissions of Unix domain sockets
+const DefaultUnixSocketMode = "0660"
+
+const unixSocketPrefix = "unix://"
+
+// UnixSocketPath returns the socket path of a unix:///path address
+func UnixSocketPath(address string) (string, bool) {
+	if !strings.HasPrefix(address, unixSocketPrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(address, unixSocketPrefix), true
+}
+
+// Listen listens on a TCP address (host:port) or on a Unix domain socket
+// (unix:///path). A stale socket file left by a previous server is removed,
+// and the socket permissions are set to mode.
+func Listen(address string, mode os.FileMode) (net.Listener, error) {
+	path, ok := UnixSocketPath(address)
+	if !ok {
+		return net.Listen("tcp", address)
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("empty socket path in %q", address)
+	}
+
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		lis.Close()
+		return nil, err
+	}
+
+	return lis, nil
+}
+
+// removeStaleSocket removes a socket file no server is listening on
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%q exists and is not a socket", path)
+	}
+
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("%q is in use", path)
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package frames
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frames.sock")
+	address := "unix://" + path
+
+	lis, err := Listen(address, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Fatalf("bad socket mode: %o != %o", mode, 0600)
+	}
+
+	if _, err := Listen(address, 0600); err == nil {
+		t.Fatal("listening on a socket in use")
+	}
+
+	lis.Close()
+
+	// Simulate a stale socket left by a server that was killed
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(address, 0600); err == nil {
+		t.Fatal("listening over a regular file")
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err = Listen(address, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Close doesn't unlink when the socket is left for the next server
+	lis.(interface{ SetUnlinkOnClose(bool) }).SetUnlinkOnClose(false)
+	lis.Close()
+
+	lis, err = Listen(address, 0660)
+	if err != nil {
+		t.Fatalf("stale socket - %s", err)
+	}
+	defer lis.Close()
+}
+
+func TestConfigSocketMode(t *testing.T) {
+	cfg := &Config{}
+	mode, err := cfg.SocketMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode != 0660 {
+		t.Fatalf("bad default mode: %o", mode)
+	}
+
+	for _, value := range []string{"0600", "777"} {
+		cfg.UnixSocketMode = value
+		if _, err := cfg.SocketMode(); err != nil {
+			t.Fatalf("%q: %s", value, err)
+		}
+	}
+
+	for _, value := range []string{"rw", "0999", "01777"} {
+		cfg.UnixSocketMode = value
+		if _, err := cfg.SocketMode(); err == nil {
+			t.Fatalf("%q: no error", value)
+		}
+	}
+}
@@ -35,7 +35,7 @@ type Query struct {
 	GroupBy string
 }
 
-// ParseSQL parsers SQL query to a Query struct
+// ParseSQL parsers SQL query to a Query struct, SELECT * leaves Columns empty
 func ParseSQL(sql string) (*Query, error) {
 	stmt, err := sqlparser.Parse(sql)
 	if err != nil {
@@ -65,8 +65,11 @@ func ParseSQL(sql string) (*Query, error) {
 		Table: table.Name.String(),
 	}
 
+	allColumns := false
 	for _, sexpr := range slct.SelectExprs {
 		switch col := sexpr.(type) {
+		case *sqlparser.StarExpr:
+			allColumns = true
 		case *sqlparser.AliasedExpr:
 			if !col.As.IsEmpty() {
 				return nil, fmt.Errorf("SELECT ... AS ... is not supported")
@@ -83,7 +86,11 @@ func ParseSQL(sql string) (*Query, error) {
 		}
 	}
 
-	if len(query.Columns) == 0 {
+	if allColumns {
+		if len(query.Columns) > 0 {
+			return nil, fmt.Errorf("can't mix * with other columns")
+		}
+	} else if len(query.Columns) == 0 {
 		return nil, fmt.Errorf("no columns")
 	}
 
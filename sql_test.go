@@ -50,3 +50,19 @@ func TestSimple(t *testing.T) {
 		t.Fatalf("wrong result - %+v", query)
 	}
 }
+
+func TestSelectAll(t *testing.T) {
+	query, err := ParseSQL("SELECT * FROM employees")
+	if err != nil {
+		t.Fatalf("error parsing - %s", err)
+	}
+
+	expected := &Query{Table: "employees"}
+	if !reflect.DeepEqual(query, expected) {
+		t.Fatalf("wrong result - %+v", query)
+	}
+
+	if _, err := ParseSQL("SELECT *, first FROM employees"); err == nil {
+		t.Fatalf("no error on * with other columns")
+	}
+}
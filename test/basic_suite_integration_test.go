@@ -17,15 +17,19 @@ import (
 	"github.com/nuclio/logger"
 	"github.com/stretchr/testify/suite"
 	"github.com/v3io/frames"
+	"github.com/v3io/frames/api"
 	"github.com/v3io/frames/grpc"
 	"github.com/v3io/frames/http"
 	"github.com/v3io/frames/v3ioutils"
+	"github.com/v3io/frames/v3ioutils/fake"
 	v3io "github.com/v3io/v3io-go/pkg/dataplane"
 	v3iohttp "github.com/v3io/v3io-go/pkg/dataplane/http"
 )
 
 const (
 	configFile = "config.yaml"
+	// Container of the in-memory V3IO used when V3IO_SESSION isn't set
+	fakeContainerName = "bigdata"
 )
 
 var (
@@ -45,6 +49,7 @@ type testInfo struct {
 	v3ioContainer  v3io.Container
 	debugMode      bool
 	backendsToTest string
+	fakeContext    *fake.Context // in-memory V3IO, nil when testing with a cluster
 }
 
 type mainTestSuite struct {
@@ -56,13 +61,13 @@ type mainTestSuite struct {
 func (mainSuite *mainTestSuite) newGrpcClient() frames.Client {
 	client, err := grpc.NewClient(mainSuite.info.grpcAddr, mainSuite.info.session, mainSuite.logger)
 	mainSuite.Require().NoError(err, "could not craete grpc client")
-	return client
+	return frames.NewV1Client(client)
 }
 
 func (mainSuite *mainTestSuite) newHttpClient() frames.Client {
 	client, err := http.NewClient(mainSuite.info.httpAddr, mainSuite.info.session, mainSuite.logger)
 	mainSuite.Require().NoError(err, "could not craete http client")
-	return client
+	return frames.NewV1Client(client)
 }
 
 func (mainSuite *mainTestSuite) SetupSuite() {
@@ -70,7 +75,7 @@ func (mainSuite *mainTestSuite) SetupSuite() {
 }
 
 func (mainSuite *mainTestSuite) TearDownSuite() {
-	if !mainSuite.info.debugMode {
+	if !mainSuite.info.debugMode && mainSuite.info.process != nil {
 		mainSuite.info.process.Kill()
 	}
 }
@@ -192,7 +197,7 @@ func sessionInfo(t testing.TB) *frames.Session {
 	return &s.Session
 }
 
-func generateConfig(root string, session *frames.Session) *frames.Config {
+func generateConfig(root string, session *frames.Session, useFake bool) *frames.Config {
 	backends := []*frames.BackendConfig{
 		{
 			Type:    "csv",
@@ -200,7 +205,15 @@ func generateConfig(root string, session *frames.Session) *frames.Config {
 		},
 	}
 
-	if session != nil {
+	if useFake {
+		// TSDB uses its own V3IO client, it needs a cluster
+		backends = append(backends, &frames.BackendConfig{
+			Type: "kv",
+		})
+		backends = append(backends, &frames.BackendConfig{
+			Type: "stream",
+		})
+	} else if session != nil {
 		backends = append(backends, &frames.BackendConfig{
 			Type: "kv",
 		})
@@ -296,21 +309,34 @@ func setupTest(t testing.TB, internalLogger logger.Logger) *testInfo {
 	info := &testInfo{}
 	info.debugMode = strings.ToLower(os.Getenv("DEBUG")) == "true"
 	info.backendsToTest = os.Getenv("TEST_BACKENDS")
-	if info.backendsToTest == "" {
-		info.backendsToTest = "kv,tsdb,stream,csv"
-	}
 
 	info.root = setupRoot(t)
 	t.Logf("root: %s", info.root)
 	info.session = sessionInfo(t)
+	if info.session == nil {
+		// No V3IO cluster, run in-process servers over an in-memory V3IO
+		t.Log("V3IO_SESSION not set, using an in-memory V3IO")
+		info.fakeContext = fake.NewContext()
+		info.session = &frames.Session{Container: fakeContainerName}
+		info.debugMode = false
+		if info.backendsToTest == "" {
+			info.backendsToTest = "kv,stream,csv"
+		}
+	}
+	if info.backendsToTest == "" {
+		info.backendsToTest = "kv,tsdb,stream,csv"
+	}
 	t.Logf("session: %+v", info.session)
-	info.config = generateConfig(info.root, info.session)
+	info.config = generateConfig(info.root, info.session, info.fakeContext != nil)
 	t.Logf("config: %+v", info.config)
 	configPath := fmt.Sprintf("%s/%s", info.root, configFile)
 	encodeConfig(t, info.config, configPath)
 
 	grpcPort, httpPort := 8081, 8080
-	if !info.debugMode {
+	if info.fakeContext != nil {
+		grpcPort, httpPort = freePort(t), freePort(t)
+		startFakeServers(t, info, grpcPort, httpPort, internalLogger)
+	} else if !info.debugMode {
 		grpcPort, httpPort = freePort(t), freePort(t)
 		cmd := runServer(t, info.root, grpcPort, httpPort)
 		info.process = cmd.Process
@@ -319,6 +345,11 @@ func setupTest(t testing.TB, internalLogger logger.Logger) *testInfo {
 	info.grpcAddr = fmt.Sprintf("localhost:%d", grpcPort)
 	info.httpAddr = fmt.Sprintf("http://localhost:%d", httpPort)
 
+	if info.fakeContext != nil {
+		info.v3ioContainer = info.fakeContext.GetContainer(fakeContainerName)
+		return info
+	}
+
 	newClient := v3iohttp.NewClient(&v3iohttp.NewClientInput{DialTimeout: 0, MaxConnsPerHost: 100})
 	newContextInput := &v3iohttp.NewContextInput{
 		HTTPClient:     newClient,
@@ -337,6 +368,28 @@ func setupTest(t testing.TB, internalLogger logger.Logger) *testInfo {
 	return info
 }
 
+// startFakeServers starts in-process gRPC and HTTP servers whose backends
+// use the in-memory V3IO of info, they run until the test process exits
+func startFakeServers(t testing.TB, info *testInfo, grpcPort int, httpPort int, logger logger.Logger) {
+	grpcServer, err := grpc.NewServer(info.config, fmt.Sprintf(":%d", grpcPort), logger, nil, "test", api.WithV3ioContext(info.fakeContext))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer, err := http.NewServer(info.config, fmt.Sprintf(":%d", httpPort), logger, nil, "test", api.WithV3ioContext(info.fakeContext))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, server := range []frames.Server{grpcServer, httpServer} {
+		if err := server.Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitForServer(t, httpPort)
+}
+
 func TestFrames(t *testing.T) {
 	logger, err := frames.NewLogger("integration-test")
 	if err != nil {
@@ -345,8 +398,15 @@ func TestFrames(t *testing.T) {
 	suite.Run(t, &mainTestSuite{logger: logger})
 }
 
+// createTestContainer returns a container of the V3IO_SESSION cluster, or an
+// in-memory one when V3IO_SESSION isn't set
 func createTestContainer(t testing.TB) v3io.Container {
 	session := sessionInfo(t)
+	if session == nil {
+		t.Log("V3IO_SESSION not set, using an in-memory V3IO")
+		return fake.NewContext().GetContainer(fakeContainerName)
+	}
+
 	logger, err := frames.NewLogger("integration-test")
 	if err != nil {
 		t.Fatal(err)
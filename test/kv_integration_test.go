@@ -1428,3 +1428,73 @@ func (kvSuite *KvTestSuite) TestUpdateExpressionWithCondition() {
 
 	kvSuite.Require().NoError(iter.Err())
 }
+
+func (kvSuite *KvTestSuite) TestBatchWrite() {
+	prefix := fmt.Sprintf("frames_ci/TestBatchWrite_%d", time.Now().UnixNano())
+	orders, lines := prefix+"/orders", prefix+"/lines"
+
+	frame := func(keys []string, column string, values []int64) frames.Frame {
+		keyCol, err := frames.NewSliceColumn("key", keys)
+		kvSuite.Require().NoError(err)
+		col, err := frames.NewSliceColumn(column, values)
+		kvSuite.Require().NoError(err)
+		frame, err := frames.NewFrame([]frames.Column{col}, []frames.Column{keyCol}, nil)
+		kvSuite.Require().NoError(err)
+		return frame
+	}
+	order := func(total int64, lineKeys ...string) *frames.BatchWriteRequest {
+		quantities := make([]int64, len(lineKeys))
+		for i := range lineKeys {
+			quantities[i] = int64(i + 1)
+		}
+
+		return &frames.BatchWriteRequest{
+			Backend: kvSuite.backendName,
+			Tables: []*frames.BatchWriteTable{
+				{Table: orders, Frame: frame([]string{"o1"}, "total", []int64{total})},
+				{Table: lines, Frame: frame(lineKeys, "quantity", quantities)},
+			},
+		}
+	}
+
+	result, err := kvSuite.client.BatchWrite(order(10, "l1", "l2"))
+	kvSuite.Require().NoError(err)
+	kvSuite.Require().True(result.Committed)
+	kvSuite.Require().Len(result.Tables, 2)
+	for _, table := range result.Tables {
+		kvSuite.Require().Equal(frames.BatchCommitted, table.Status)
+	}
+	kvSuite.Require().Equal(2, result.Tables[1].Items)
+
+	it, err := kvSuite.client.Read(&pb.ReadRequest{Backend: kvSuite.backendName, Table: lines})
+	kvSuite.Require().NoError(err)
+	rows := 0
+	for it.Next() {
+		rows += it.At().Len()
+	}
+	kvSuite.Require().NoError(it.Err())
+	kvSuite.Require().Equal(2, rows)
+
+	// lines/bad is a directory, it can't be updated and the batch is aborted
+	_, err = kvSuite.v3ioContainer.PutItemSync(&v3io.PutItemInput{
+		Path:       lines + "/bad/x",
+		Attributes: map[string]interface{}{"a": 1},
+	})
+	kvSuite.Require().NoError(err)
+
+	result, err = kvSuite.client.BatchWrite(order(20, "l1", "bad"))
+	kvSuite.Require().Error(err)
+	kvSuite.Require().NotNil(result)
+	kvSuite.Require().False(result.Committed)
+	kvSuite.Require().Equal(frames.BatchAborted, result.Tables[0].Status)
+	kvSuite.Require().Contains(result.Tables[1].Error, "bad")
+
+	it, err = kvSuite.client.Read(&pb.ReadRequest{Backend: kvSuite.backendName, Table: orders})
+	kvSuite.Require().NoError(err)
+	kvSuite.Require().True(it.Next())
+	col, err := it.At().Column("total")
+	kvSuite.Require().NoError(err)
+	total, err := col.IntAt(0)
+	kvSuite.Require().NoError(err)
+	kvSuite.Require().EqualValues(10, total)
+}
@@ -86,6 +86,13 @@ const (
 	CreateNewItemsOnly
 )
 
+// Schema modes of writes (see WriteRequest.SchemaMode)
+const (
+	SchemaModeEvolve = "evolve" // add new columns and widen types (int to float) in the schema
+	SchemaModeStrict = "strict" // reject columns that are not in the schema or have another type
+	SchemaModeCoerce = "coerce" // cast columns to their schema type, reject columns not in the schema
+)
+
 // Column is a data column
 type Column interface {
 	Len() int                                 // Number of elements
@@ -151,6 +158,30 @@ type FrameAppender interface {
 	Close()
 }
 
+// RowErrorsCollector is implemented by appenders that report rows failing to
+// write instead of failing the write (see WriteRequest.ContinueOnError)
+type RowErrorsCollector interface {
+	// TakeRowErrors returns the rows that failed since the last call
+	TakeRowErrors() []RowError
+}
+
+// FailedRowsAppender is implemented by client appenders, FailedRows returns
+// the rows that failed to write (see NewFailedRowsFrame) once the write is
+// complete, nil if no row failed
+type FailedRowsAppender interface {
+	FailedRows() Frame
+}
+
+// FrameFlusher is implemented by appenders that can wait for the frames added
+// so far to be applied without completing the write
+type FrameFlusher interface {
+	Flush(timeout time.Duration) error
+}
+
+// MarkerLabel is the label of read frames holding the marker to pass in
+// ReadRequest.Marker to continue the read after the frame (KV backend)
+const MarkerLabel = "marker"
+
 // ReadRequest is a read/query request
 type ReadRequest struct {
 	Proto    *pb.ReadRequest
@@ -240,6 +271,17 @@ type WriteRequest struct {
 	// Will we get more message chunks (in a stream), if not we can complete
 	HaveMore bool
 	SaveMode SaveMode
+	// Report rows that fail to write (see RowError) instead of failing the write
+	ContinueOnError bool
+	// Time zone (IANA name) and precision (s, ms, us or ns) of written time columns
+	TimeZone      string
+	TimePrecision string
+	// Item time to live, a duration (e.g. "1h") or the name of a time column
+	// holding the item expiry time
+	TTL string
+	// How the written columns are checked against the table schema (see
+	// SchemaModeEvolve), the table mode if empty
+	SchemaMode string
 }
 
 func (writeRequest WriteRequest) ToMap() map[string]string {
@@ -257,6 +299,21 @@ func (writeRequest WriteRequest) ToMap() map[string]string {
 	}
 
 	reqMap["saveMode"] = writeRequest.SaveMode.String()
+	if writeRequest.ContinueOnError {
+		reqMap["continueOnError"] = "true"
+	}
+	if writeRequest.TimeZone != "" {
+		reqMap["timeZone"] = writeRequest.TimeZone
+	}
+	if writeRequest.TimePrecision != "" {
+		reqMap["timePrecision"] = writeRequest.TimePrecision
+	}
+	if writeRequest.TTL != "" {
+		reqMap["ttl"] = writeRequest.TTL
+	}
+	if writeRequest.SchemaMode != "" {
+		reqMap["schemaMode"] = writeRequest.SchemaMode
+	}
 
 	return reqMap
 }
@@ -349,6 +406,148 @@ type VersionRequest struct {
 	Token    SecretString
 }
 
+// BatchWriteRequest writes frames to several tables of a backend, either all
+// of them are written or none is (see BatchWriter)
+type BatchWriteRequest struct {
+	Session  *Session
+	Password SecretString
+	Token    SecretString
+	Backend  string // backend name
+	Tables   []*BatchWriteTable
+	// Item attribute holding the item version, DefaultVersionAttribute if empty
+	VersionAttribute string
+}
+
+// BatchWriteTable is the data written to a table in a BatchWriteRequest
+type BatchWriteTable struct {
+	Table string // Table name (path)
+	// Frame indexed by the item keys (and sorting keys)
+	Frame Frame
+	// Columns to partition the data by
+	PartitionKeys []string
+}
+
+// DefaultVersionAttribute is the default BatchWriteRequest.VersionAttribute
+const DefaultVersionAttribute = "_version"
+
+// Batch write table statuses
+const (
+	BatchCommitted      = "committed"       // all items were written
+	BatchAborted        = "aborted"         // no item was written, or written items were restored
+	BatchRollbackFailed = "rollback_failed" // some written items could not be restored
+)
+
+// BatchWriteResult is the outcome of a BatchWriteRequest
+type BatchWriteResult struct {
+	Committed bool
+	Tables    []*BatchTableResult // in request order
+}
+
+// BatchTableResult is the outcome of a table in a batch write
+type BatchTableResult struct {
+	Table  string
+	Status string // BatchCommitted, BatchAborted or BatchRollbackFailed
+	Items  int    // items in the table frame
+	// Error that aborted the batch (if it's in this table), or of restoring
+	// the table items
+	Error string
+}
+
+// BatchWriter is implemented by backends that support batch writes
+type BatchWriter interface {
+	BatchWrite(request *BatchWriteRequest) (*BatchWriteResult, error)
+}
+
+// Proto returns the protobuf message of the request, clients send the
+// credentials in the session
+func (r *BatchWriteRequest) Proto() (*pb.BatchWriteRequest, error) {
+	msg := &pb.BatchWriteRequest{
+		Session:          r.Session,
+		Backend:          r.Backend,
+		VersionAttribute: r.VersionAttribute,
+	}
+
+	for _, table := range r.Tables {
+		tableMsg := &pb.BatchWriteTable{Table: table.Table, PartitionKeys: table.PartitionKeys}
+		if table.Frame != nil {
+			framed, ok := table.Frame.(pb.Framed)
+			if !ok {
+				return nil, errors.Errorf("unknown frame type of table '%s'", table.Table)
+			}
+			tableMsg.Frame = framed.Proto()
+		}
+		msg.Tables = append(msg.Tables, tableMsg)
+	}
+
+	return msg, nil
+}
+
+// BatchWriteRequestFromProto returns the request of msg, the session
+// credentials are moved to the request Password and Token
+func BatchWriteRequestFromProto(msg *pb.BatchWriteRequest) *BatchWriteRequest {
+	request := &BatchWriteRequest{
+		Session:          msg.Session,
+		Backend:          msg.Backend,
+		VersionAttribute: msg.VersionAttribute,
+	}
+	if msg.Session != nil {
+		request.Password = InitSecretString(msg.Session.Password)
+		request.Token = InitSecretString(msg.Session.Token)
+		msg.Session.Password = ""
+		msg.Session.Token = ""
+	}
+
+	for _, table := range msg.Tables {
+		tableRequest := &BatchWriteTable{Table: table.Table, PartitionKeys: table.PartitionKeys}
+		if table.Frame != nil {
+			tableRequest.Frame = NewFrameFromProto(table.Frame)
+		}
+		request.Tables = append(request.Tables, tableRequest)
+	}
+
+	return request
+}
+
+// Proto returns the protobuf message of the result, err is the error that
+// aborted the batch
+func (r *BatchWriteResult) Proto(err error) *pb.BatchWriteResponse {
+	msg := &pb.BatchWriteResponse{Committed: r.Committed}
+	if err != nil {
+		msg.Error = err.Error()
+	}
+
+	for _, table := range r.Tables {
+		msg.Tables = append(msg.Tables, &pb.BatchTableResult{
+			Table:  table.Table,
+			Status: table.Status,
+			Items:  int64(table.Items),
+			Error:  table.Error,
+		})
+	}
+
+	return msg
+}
+
+// BatchWriteResultFromProto returns the result of msg and the error that
+// aborted the batch
+func BatchWriteResultFromProto(msg *pb.BatchWriteResponse) (*BatchWriteResult, error) {
+	result := &BatchWriteResult{Committed: msg.Committed}
+	for _, table := range msg.Tables {
+		result.Tables = append(result.Tables, &BatchTableResult{
+			Table:  table.Table,
+			Status: table.Status,
+			Items:  int(table.Items),
+			Error:  table.Error,
+		})
+	}
+
+	if msg.Error != "" {
+		return result, errors.New(msg.Error)
+	}
+
+	return result, nil
+}
+
 // ExecRequest is execution request
 type ExecRequest struct {
 	Proto    *pb.ExecRequest
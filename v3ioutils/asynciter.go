@@ -37,19 +37,58 @@ type ItemsCursor interface {
 	GetFields() map[string]interface{}
 }
 
+// ItemsStreamState is the position of a single GetItems stream (a segment
+// or a sharding key of a partition) of an AsyncItemsCursor
+type ItemsStreamState struct {
+	Partition   string `json:"p"`
+	Segment     int    `json:"s,omitempty"`
+	ShardingKey string `json:"k,omitempty"`
+	Marker      string `json:"m,omitempty"` // v3io marker of the next items
+	Skip        int    `json:"n,omitempty"` // items after Marker that were already returned
+	Done        bool   `json:"d,omitempty"`
+}
+
+// ItemsCursorState is the position of an AsyncItemsCursor. A cursor created
+// from it (see NewAsyncItemsCursorFromState) returns the items that weren't
+// returned yet.
+type ItemsCursorState struct {
+	TotalSegments int                `json:"t,omitempty"`
+	Streams       []ItemsStreamState `json:"streams"`
+}
+
+// Done returns true if all the streams are done
+func (s *ItemsCursorState) Done() bool {
+	for _, stream := range s.Streams {
+		if !stream.Done {
+			return false
+		}
+	}
+
+	return true
+}
+
+// itemsStream is a GetItems stream of a cursor
+type itemsStream struct {
+	state ItemsStreamState
+	input *v3io.GetItemsInput
+	next  string // marker after the current batch
+	last  bool   // the current batch is the last one
+}
+
 // AsyncItemsCursor is async item cursor
 type AsyncItemsCursor struct {
-	currentItem        v3io.Item
-	currentError       error
-	itemIndex          int
-	items              []v3io.Item
-	input              *v3io.GetItemsInput
-	container          v3io.Container
-	logger             logger.Logger
-	numberOfPartitions int
-
+	currentItem  v3io.Item
+	currentError error
+	itemIndex    int
+	items        []v3io.Item
+	input        *v3io.GetItemsInput
+	container    v3io.Container
+	logger       logger.Logger
+
+	streams       []*itemsStream
+	pending       []*itemsStream // streams that weren't started yet
+	current       *itemsStream   // stream of items
 	responseChan  chan *v3io.Response
-	workers       int
 	totalSegments int
 	lastShards    int
 	Cnt           int
@@ -61,67 +100,151 @@ func NewAsyncItemsCursor(container v3io.Container, input *v3io.GetItemsInput, wo
 	logger logger.Logger, limit int, partitions []string,
 	sortKeyRangeStart string, sortKeyRangeEnd string) (*AsyncItemsCursor, error) {
 
+	maxStreams := workers
+
 	// TODO: use workers from Context.numWorkers (if no ShardingKey)
 	if workers == 0 || input.ShardingKey != "" {
 		workers = 1
 	}
 
-	newAsyncItemsCursor := &AsyncItemsCursor{
-		container:          container,
-		input:              input,
-		workers:            workers,
-		logger:             logger.GetChild("AsyncItemsCursor"),
-		limit:              limit,
-		numberOfPartitions: len(partitions),
-	}
-
-	if len(shardingKeys) > 0 {
-		newAsyncItemsCursor.workers = len(shardingKeys)
-		newAsyncItemsCursor.responseChan = make(chan *v3io.Response, len(partitions)*newAsyncItemsCursor.workers)
-
-		for _, partition := range partitions {
-			for i := 0; i < newAsyncItemsCursor.workers; i++ {
-				input := v3io.GetItemsInput{
-					Path:              partition,
-					AttributeNames:    input.AttributeNames,
-					Filter:            input.Filter,
-					ShardingKey:       shardingKeys[i],
-					SortKeyRangeStart: sortKeyRangeStart,
-					SortKeyRangeEnd:   sortKeyRangeEnd,
-				}
-				_, err := container.GetItems(&input, &input, newAsyncItemsCursor.responseChan)
-
-				if err != nil {
-					return nil, err
-				}
-			}
+	state := NewItemsCursorState(partitions, workers, nil, shardingKeys)
+	return NewAsyncItemsCursorFromState(container, input, state, maxStreams, logger, limit, sortKeyRangeStart, sortKeyRangeEnd)
+}
+
+// NewItemsCursorState returns the initial state of a cursor over partitions.
+// If shardingKeys is not empty, every partition is read by sharding key,
+// otherwise partitions are split to totalSegments and only segments are read
+// (all of them if segments is empty).
+func NewItemsCursorState(partitions []string, totalSegments int, segments []int, shardingKeys []string) *ItemsCursorState {
+	if len(shardingKeys) == 0 && len(segments) == 0 {
+		for i := 0; i < totalSegments; i++ {
+			segments = append(segments, i)
 		}
-	} else {
-		newAsyncItemsCursor.totalSegments = workers
-		newAsyncItemsCursor.responseChan = make(chan *v3io.Response, len(partitions)*newAsyncItemsCursor.workers)
-
-		for _, partition := range partitions {
-			for i := 0; i < newAsyncItemsCursor.workers; i++ {
-				input := v3io.GetItemsInput{
-					Path:           partition,
-					AttributeNames: input.AttributeNames,
-					Filter:         input.Filter,
-					TotalSegments:  newAsyncItemsCursor.totalSegments,
-					Segment:        i,
-				}
-				_, err := container.GetItems(&input, &input, newAsyncItemsCursor.responseChan)
-
-				if err != nil {
-					// TODO: proper exit, release requests which passed
-					return nil, err
-				}
+	}
+
+	state := &ItemsCursorState{}
+	for _, partition := range partitions {
+		if len(shardingKeys) > 0 {
+			for _, shardingKey := range shardingKeys {
+				state.Streams = append(state.Streams, ItemsStreamState{Partition: partition, ShardingKey: shardingKey})
 			}
+			continue
+		}
+
+		state.TotalSegments = totalSegments
+		for _, segment := range segments {
+			state.Streams = append(state.Streams, ItemsStreamState{Partition: partition, Segment: segment})
+		}
+	}
+
+	return state
+}
+
+// NewAsyncItemsCursorFromState returns a new AsyncItemsCursor starting at
+// state (see AsyncItemsCursor.State). At most maxStreams streams are read at
+// a time (at least one), the next stream starts when one is done.
+func NewAsyncItemsCursorFromState(container v3io.Container, input *v3io.GetItemsInput, state *ItemsCursorState, maxStreams int,
+	logger logger.Logger, limit int, sortKeyRangeStart string, sortKeyRangeEnd string) (*AsyncItemsCursor, error) {
+
+	if maxStreams < 1 {
+		maxStreams = 1
+	}
+	if maxStreams > len(state.Streams) {
+		maxStreams = len(state.Streams)
+	}
+
+	newAsyncItemsCursor := &AsyncItemsCursor{
+		container:     container,
+		input:         input,
+		logger:        logger.GetChild("AsyncItemsCursor"),
+		limit:         limit,
+		totalSegments: state.TotalSegments,
+		responseChan:  make(chan *v3io.Response, maxStreams),
+	}
+
+	for _, streamState := range state.Streams {
+		stream := &itemsStream{state: streamState}
+		newAsyncItemsCursor.streams = append(newAsyncItemsCursor.streams, stream)
+		if streamState.Done {
+			newAsyncItemsCursor.lastShards++
+			continue
+		}
+
+		stream.input = &v3io.GetItemsInput{
+			Path:           streamState.Partition,
+			AttributeNames: input.AttributeNames,
+			Filter:         input.Filter,
+			Marker:         streamState.Marker,
+		}
+
+		if streamState.ShardingKey != "" {
+			stream.input.ShardingKey = streamState.ShardingKey
+			stream.input.SortKeyRangeStart = sortKeyRangeStart
+			stream.input.SortKeyRangeEnd = sortKeyRangeEnd
+		} else {
+			stream.input.TotalSegments = state.TotalSegments
+			stream.input.Segment = streamState.Segment
+		}
+
+		newAsyncItemsCursor.pending = append(newAsyncItemsCursor.pending, stream)
+	}
+
+	for i := 0; i < maxStreams; i++ {
+		if err := newAsyncItemsCursor.startStream(); err != nil {
+			// TODO: proper exit, release requests which passed
+			return nil, err
 		}
 	}
 
 	return newAsyncItemsCursor, nil
 }
 
+// startStream sends the first request of the next pending stream, if any
+func (ic *AsyncItemsCursor) startStream() error {
+	if len(ic.pending) == 0 {
+		return nil
+	}
+
+	stream := ic.pending[0]
+	ic.pending = ic.pending[1:]
+	_, err := ic.container.GetItems(stream.input, stream, ic.responseChan)
+	return err
+}
+
+// State returns the position of the cursor, it's valid between calls to Next
+func (ic *AsyncItemsCursor) State() *ItemsCursorState {
+	state := &ItemsCursorState{
+		TotalSegments: ic.totalSegments,
+		Streams:       make([]ItemsStreamState, len(ic.streams)),
+	}
+
+	for i, stream := range ic.streams {
+		state.Streams[i] = stream.state
+		if stream == ic.current && ic.itemIndex == len(ic.items) {
+			// Current batch is done
+			advanceStream(&state.Streams[i], stream, len(ic.items))
+		}
+	}
+
+	return state
+}
+
+// advanceStream moves state past the current batch of stream with size items
+func advanceStream(state *ItemsStreamState, stream *itemsStream, size int) {
+	if stream.last {
+		state.Done = true
+		state.Marker = ""
+		state.Skip = 0
+		return
+	}
+
+	state.Marker = stream.next
+	state.Skip -= size
+	if state.Skip < 0 {
+		state.Skip = 0
+	}
+}
+
 // Err returns the last error
 func (ic *AsyncItemsCursor) Err() error {
 	return ic.currentError
@@ -149,8 +272,8 @@ func (ic *AsyncItemsCursor) NextItem() (v3io.Item, error) {
 
 	// are there any more items left in the previous response we received?
 	if ic.itemIndex < len(ic.items) {
-		// if we read more rows than limit, return EOF
-		if ic.limit > 0 && ic.Cnt > ic.limit {
+		// if we read limit rows, return EOF
+		if ic.limit > 0 && ic.Cnt >= ic.limit {
 			return nil, nil
 		}
 
@@ -160,12 +283,20 @@ func (ic *AsyncItemsCursor) NextItem() (v3io.Item, error) {
 		// next time we'll give next item
 		ic.itemIndex++
 		ic.Cnt++
+		ic.current.state.Skip++
 
 		return ic.currentItem, nil
 	}
 
+	if ic.current != nil {
+		advanceStream(&ic.current.state, ic.current, len(ic.items))
+		ic.current = nil
+		ic.items = nil
+		ic.itemIndex = 0
+	}
+
 	// are there any more items up stream? did all the shards complete ?
-	if ic.lastShards == ic.workers*ic.numberOfPartitions {
+	if ic.lastShards == len(ic.streams) {
 		ic.currentError = nil
 		return nil, nil
 	}
@@ -173,11 +304,16 @@ func (ic *AsyncItemsCursor) NextItem() (v3io.Item, error) {
 	// Read response from channel
 	resp := <-ic.responseChan
 	resp.Release()
+	stream := resp.Context.(*itemsStream)
 
 	// Ignore 404s
 	if e, hasErrorCode := resp.Error.(v3ioerrors.ErrorWithStatusCode); hasErrorCode && e.StatusCode() == http.StatusNotFound {
 		ic.logger.Debug("Got 404 - error: %v, request: %v", resp.Error, resp.Request().Input)
+		stream.state.Done = true
 		ic.lastShards++
+		if err := ic.startStream(); err != nil {
+			return nil, errors.Wrap(err, "Failed to request next items")
+		}
 		return ic.NextItem()
 	}
 	if resp.Error != nil {
@@ -187,26 +323,33 @@ func (ic *AsyncItemsCursor) NextItem() (v3io.Item, error) {
 
 	getItemsResp := resp.Output.(*v3io.GetItemsOutput)
 
-	// set the cursor items and reset the item index
+	// set the cursor items and skip the ones returned before the cursor
+	// state was taken
+	ic.current = stream
 	ic.items = getItemsResp.Items
-	ic.itemIndex = 0
+	ic.itemIndex = stream.state.Skip
+	if ic.itemIndex > len(ic.items) {
+		ic.itemIndex = len(ic.items)
+	}
+	stream.next = getItemsResp.NextMarker
+	stream.last = getItemsResp.Last
 
 	if !getItemsResp.Last {
 
 		// if not last, make a new request to that shard
-		input := resp.Context.(*v3io.GetItemsInput)
-
-		// set next marker
-		input.Marker = getItemsResp.NextMarker
+		stream.input.Marker = getItemsResp.NextMarker
 
-		_, err := ic.container.GetItems(input, input, ic.responseChan)
+		_, err := ic.container.GetItems(stream.input, stream, ic.responseChan)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to request next items")
 		}
 
 	} else {
-		// Mark one more shard as completed
+		// Mark one more shard as completed, and start the next one
 		ic.lastShards++
+		if err := ic.startStream(); err != nil {
+			return nil, errors.Wrap(err, "Failed to request next items")
+		}
 	}
 
 	// and recurse into next now that we repopulated response
@@ -252,3 +395,12 @@ func (ic *AsyncItemsCursor) GetFields() map[string]interface{} {
 func (ic *AsyncItemsCursor) GetItem() v3io.Item {
 	return ic.currentItem
 }
+
+// CurrentPartition returns the partition path of the current item
+func (ic *AsyncItemsCursor) CurrentPartition() string {
+	if ic.current == nil {
+		return ""
+	}
+
+	return ic.current.state.Partition
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3ioutils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/v3io/frames"
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+)
+
+// batchesContainer returns the items of every partition segment in batches
+// of batchSize, markers are item offsets
+type batchesContainer struct {
+	v3io.Container
+	items     map[string][]v3io.Item // segment key -> items
+	batchSize int
+	inFlight  int // max responses waiting in the response channel
+}
+
+func segmentKey(path string, segment int) string {
+	return fmt.Sprintf("%s/%d", path, segment)
+}
+
+func (c *batchesContainer) GetItems(input *v3io.GetItemsInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	items := c.items[segmentKey(input.Path, input.Segment)]
+	start := 0
+	if input.Marker != "" {
+		var err error
+		if start, err = strconv.Atoi(input.Marker); err != nil {
+			return nil, err
+		}
+	}
+
+	end := start + c.batchSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	if n := len(responseChan) + 1; n > c.inFlight {
+		c.inFlight = n
+	}
+	responseChan <- &v3io.Response{
+		Context: context,
+		Output: &v3io.GetItemsOutput{
+			Items:      items[start:end],
+			Last:       end == len(items),
+			NextMarker: strconv.Itoa(end),
+		},
+	}
+
+	return &v3io.Request{}, nil
+}
+
+func newBatchesContainer(partitions []string, segments int, itemsPerSegment int) *batchesContainer {
+	c := &batchesContainer{
+		items:     make(map[string][]v3io.Item),
+		batchSize: 3,
+	}
+
+	for _, partition := range partitions {
+		for segment := 0; segment < segments; segment++ {
+			key := segmentKey(partition, segment)
+			for i := 0; i < itemsPerSegment; i++ {
+				c.items[key] = append(c.items[key], v3io.Item{"__name": fmt.Sprintf("%s/%d", key, i)})
+			}
+		}
+	}
+
+	return c
+}
+
+func cursorNames(t *testing.T, cursor *AsyncItemsCursor, n int) []string {
+	var names []string
+	for (n < 0 || len(names) < n) && cursor.Next() {
+		names = append(names, cursor.GetField("__name").(string))
+	}
+
+	if err := cursor.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return names
+}
+
+func TestAsyncItemsCursorState(t *testing.T) {
+	logger, err := frames.NewLogger("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partitions := []string{"table/p=1/", "table/p=2/"}
+	container := newBatchesContainer(partitions, 2, 7)
+	input := &v3io.GetItemsInput{}
+
+	cursor, err := NewAsyncItemsCursor(container, input, 2, nil, logger, 0, partitions, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := cursorNames(t, cursor, -1)
+	if len(expected) != 2*2*7 {
+		t.Fatalf("wrong number of items: %d", len(expected))
+	}
+	if !cursor.State().Done() {
+		t.Fatalf("state of exhausted cursor is not done - %+v", cursor.State())
+	}
+
+	for _, pageSize := range []int{1, 2, 3, 5, 28} {
+		t.Run(strconv.Itoa(pageSize), func(t *testing.T) {
+			cursor, err := NewAsyncItemsCursor(container, input, 2, nil, logger, 0, partitions, "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for {
+				page := cursorNames(t, cursor, pageSize)
+				names = append(names, page...)
+				state := cursor.State()
+				if state.Done() {
+					break
+				}
+
+				if len(page) == 0 {
+					t.Fatalf("empty page of not done state - %+v", state)
+				}
+
+				cursor, err = NewAsyncItemsCursorFromState(container, input, state, 2, logger, 0, "", "")
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sort.Strings(names)
+			sorted := append([]string(nil), expected...)
+			sort.Strings(sorted)
+			if fmt.Sprint(names) != fmt.Sprint(sorted) {
+				t.Fatalf("wrong items:\n%v\nexpected:\n%v", names, sorted)
+			}
+		})
+	}
+}
+
+func TestAsyncItemsCursorSegments(t *testing.T) {
+	logger, err := frames.NewLogger("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partitions := []string{"table/p=1/", "table/p=2/"}
+	container := newBatchesContainer(partitions, 3, 4)
+	input := &v3io.GetItemsInput{}
+
+	seen := make(map[string]int)
+	for _, segments := range [][]int{{0, 2}, {1}} {
+		state := NewItemsCursorState(partitions, 3, segments, nil)
+		if n := len(state.Streams); n != len(partitions)*len(segments) {
+			t.Fatalf("wrong number of streams for segments %v: %d", segments, n)
+		}
+
+		cursor, err := NewAsyncItemsCursorFromState(container, input, state, 2, logger, 0, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range cursorNames(t, cursor, -1) {
+			seen[name]++
+		}
+	}
+
+	if container.inFlight > 2 {
+		t.Fatalf("%d streams read at a time", container.inFlight)
+	}
+
+	if len(seen) != 2*3*4 {
+		t.Fatalf("wrong number of items: %d", len(seen))
+	}
+
+	for name, count := range seen {
+		if count != 1 {
+			t.Fatalf("%s read %d times", name, count)
+		}
+	}
+}
@@ -172,6 +172,9 @@ func DeleteTable(logger logger.Logger, container v3io.Container, path, filter st
 }
 
 func getItemsWorker(container v3io.Container, input *v3io.GetItemsInput, fileNameChan chan<- string, terminationChan chan<- error, onErrorTerminationChannel <-chan struct{}) {
+	if input.Filter != "" {
+		input.Filter += " and __name != '.#schema'"
+	}
 	for {
 		select {
 		case <-onErrorTerminationChannel:
@@ -179,9 +182,6 @@ func getItemsWorker(container v3io.Container, input *v3io.GetItemsInput, fileNam
 			return
 		default:
 		}
-		if input.Filter != "" {
-			input.Filter += " and __name != '.#schema'"
-		}
 		resp, err := container.GetItemsSync(input)
 		if err != nil {
 			terminationChan <- err
@@ -0,0 +1,850 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+// Package fake provides an in-memory V3IO container for tests that can't
+// reach a V3IO cluster. Inject it to backends with NewContext (e.g. with
+// api.WithV3ioContext).
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+	v3ioerrors "github.com/v3io/v3io-go/pkg/errors"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// DefaultGetItemsLimit is the default number of items in a GetItems
+	// response
+	DefaultGetItemsLimit = 1000
+	// DefaultNumberOfVNs is the default number of VNs in GetClusterMD
+	DefaultNumberOfVNs = 4
+
+	// V3IO error codes the backends look for
+	genericErrorCode        = -201326594
+	falseConditionErrorCode = 16777244
+	itemExistsErrorCode     = 369098809
+)
+
+// entry is a file, items are files with attributes
+type entry struct {
+	attrs map[string]interface{}
+	body  []byte
+	ctime time.Time
+	mtime time.Time
+}
+
+// Container is an in-memory V3IO container, it's safe for concurrent use
+type Container struct {
+	// GetItemsLimit is the maximal number of items in a GetItems response
+	GetItemsLimit int
+	// NumberOfVNs is returned by GetClusterMD
+	NumberOfVNs int
+
+	name    string
+	lock    sync.Mutex
+	entries map[string]*entry  // path -> entry
+	dirs    map[string]bool    // directory paths (without a trailing slash)
+	streams map[string]*stream // stream path -> stream
+	now     func() time.Time
+}
+
+var (
+	// Make sure we're implementing v3io.Container
+	_ v3io.Container = &Container{}
+)
+
+// NewContainer returns a new empty container
+func NewContainer(name string) *Container {
+	return &Container{
+		GetItemsLimit: DefaultGetItemsLimit,
+		NumberOfVNs:   DefaultNumberOfVNs,
+		name:          name,
+		entries:       make(map[string]*entry),
+		dirs:          map[string]bool{"": true},
+		streams:       make(map[string]*stream),
+		now:           time.Now,
+	}
+}
+
+// Name returns the container name
+func (c *Container) Name() string {
+	return c.name
+}
+
+// cleanPath returns path without leading, trailing and duplicate slashes
+func cleanPath(p string) string {
+	p = path.Clean("/" + p)
+	return strings.TrimPrefix(p, "/")
+}
+
+func parentDir(p string) string {
+	if i := strings.LastIndex(p, "/"); i != -1 {
+		return p[:i]
+	}
+	return ""
+}
+
+// addDirs adds the parent directories of p, c.lock must be held
+func (c *Container) addDirs(p string) {
+	for dir := parentDir(p); !c.dirs[dir]; dir = parentDir(dir) {
+		c.dirs[dir] = true
+	}
+}
+
+// hasChildren returns true if dir has any entries, c.lock must be held
+func (c *Container) hasChildren(dir string) bool {
+	prefix := dir + "/"
+	for p := range c.entries {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	for p := range c.dirs {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Error helpers, errors look like the ones returned by the V3IO HTTP API so
+// the backends handle them the same way
+
+func newError(status int, format string, args ...interface{}) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"ErrorCode":    genericErrorCode,
+		"ErrorMessage": fmt.Sprintf(format, args...),
+	})
+	return newErrorWithBody(status, string(body))
+}
+
+func newErrorWithBody(status int, body string) error {
+	err := fmt.Errorf("Expected a 2xx response status code: %d %s\nResponse body: %s", status, http.StatusText(status), body)
+	return v3ioerrors.NewErrorWithStatusCode(err, status)
+}
+
+func notFound(p string) error {
+	return newError(http.StatusNotFound, "%q not found", p)
+}
+
+func badRequest(err error) error {
+	return newError(http.StatusBadRequest, "%s", err)
+}
+
+func falseCondition() error {
+	return newErrorWithBody(http.StatusBadRequest,
+		fmt.Sprintf(`{"ErrorCode": %d, "ErrorMessage": "Condition evaluated to false"}`, falseConditionErrorCode))
+}
+
+func itemExists(p string) error {
+	return newErrorWithBody(http.StatusBadRequest,
+		fmt.Sprintf(`{"ErrorCode": %d, "ErrorMessage": "Failed to update %s", "Reason": {"ErrorCode": %d, "ErrorMessage": "Item already exists"}}`,
+			genericErrorCode, p, itemExistsErrorCode))
+}
+
+func notImplemented(name string) error {
+	return newError(http.StatusNotImplemented, "%s is not supported by the fake container", name)
+}
+
+// newResponse returns a response to input
+func newResponse(input interface{}, context interface{}, responseChan chan *v3io.Response) *v3io.Response {
+	requestResponse := &v3io.RequestResponse{
+		Request: v3io.Request{
+			Input:        input,
+			Context:      context,
+			ResponseChan: responseChan,
+		},
+	}
+
+	response := &requestResponse.Response
+	response.RequestResponse = requestResponse
+	response.Context = context
+	return response
+}
+
+// async runs a sync call in the background and posts its response to
+// responseChan
+func async(input interface{}, context interface{}, responseChan chan *v3io.Response, call func() (*v3io.Response, error)) (*v3io.Request, error) {
+	request := &newResponse(input, context, responseChan).RequestResponse.Request
+	go func() {
+		response, err := call()
+		if response == nil {
+			response = newResponse(input, context, responseChan)
+		}
+		response.Error = err
+		response.Context = context
+		response.RequestResponse.Request.Context = context
+		response.RequestResponse.Request.ResponseChan = responseChan
+		responseChan <- response
+	}()
+
+	return request, nil
+}
+
+// normalizeValue converts a value to the type V3IO returns for it
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case int32:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
+
+func validateValue(name string, value interface{}) error {
+	switch value.(type) {
+	case int, int32, int64, uint64, float32, float64, string, []byte, bool, time.Time:
+		return nil
+	}
+	return fmt.Errorf("unexpected attribute type for %s: %T", name, value)
+}
+
+// GetClusterMD returns the cluster metadata
+func (c *Container) GetClusterMD(input *v3io.GetClusterMDInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.GetClusterMDSync(input) })
+}
+
+// GetClusterMDSync returns the cluster metadata
+func (c *Container) GetClusterMDSync(input *v3io.GetClusterMDInput) (*v3io.Response, error) {
+	response := newResponse(input, nil, nil)
+	response.Output = &v3io.GetClusterMDOutput{NumberOfVNs: c.NumberOfVNs}
+	return response, nil
+}
+
+// GetContainers returns the container names
+func (c *Container) GetContainers(input *v3io.GetContainersInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.GetContainersSync(input) })
+}
+
+// GetContainersSync returns the container name
+func (c *Container) GetContainersSync(input *v3io.GetContainersInput) (*v3io.Response, error) {
+	response := newResponse(input, nil, nil)
+	output := &v3io.GetContainersOutput{}
+	output.Results.Containers = []v3io.ContainerInfo{{Name: c.name}}
+	response.Output = output
+	return response, nil
+}
+
+// GetContainerContents lists a directory
+func (c *Container) GetContainerContents(input *v3io.GetContainerContentsInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.GetContainerContentsSync(input) })
+}
+
+// GetContainerContentsSync lists a directory, entries are sorted by name
+func (c *Container) GetContainerContentsSync(input *v3io.GetContainerContentsInput) (*v3io.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	dir := cleanPath(input.Path)
+	if !c.dirs[dir] {
+		return nil, notFound(input.Path)
+	}
+
+	type child struct {
+		name  string
+		isDir bool
+	}
+
+	var children []child
+	for p := range c.dirs {
+		if p != "" && parentDir(p) == dir {
+			children = append(children, child{p, true})
+		}
+	}
+	if !input.DirectoriesOnly {
+		for p := range c.entries {
+			if parentDir(p) == dir {
+				children = append(children, child{p, false})
+			}
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].name < children[j].name })
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = c.GetItemsLimit
+	}
+
+	output := &v3io.GetContainerContentsOutput{Name: c.name}
+	for _, ch := range children {
+		if input.Marker != "" && ch.name <= input.Marker {
+			continue
+		}
+
+		if len(output.Contents)+len(output.CommonPrefixes) == limit {
+			output.IsTruncated = true
+			break
+		}
+		output.NextMarker = ch.name
+
+		if ch.isDir {
+			commonPrefix := v3io.CommonPrefix{Prefix: ch.name + "/", Mode: "040755"}
+			if s, ok := c.streams[ch.name]; ok {
+				commonPrefix.ShardCount = len(s.shards)
+				commonPrefix.RetentionPeriodHours = s.retentionHours
+			}
+			output.CommonPrefixes = append(output.CommonPrefixes, commonPrefix)
+			continue
+		}
+
+		e := c.entries[ch.name]
+		size := len(e.body)
+		output.Contents = append(output.Contents, v3io.Content{
+			Key:          ch.name,
+			Size:         &size,
+			LastModified: e.mtime.UTC().Format(time.RFC3339),
+			Mode:         "0100644",
+		})
+	}
+
+	if !output.IsTruncated {
+		output.NextMarker = ""
+	}
+
+	response := newResponse(input, nil, nil)
+	response.Output = output
+	return response, nil
+}
+
+// CheckPathExists checks if a path exists
+func (c *Container) CheckPathExists(input *v3io.CheckPathExistsInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) {
+		return newResponse(input, context, responseChan), c.CheckPathExistsSync(input)
+	})
+}
+
+// CheckPathExistsSync checks if a path (file or directory) exists
+func (c *Container) CheckPathExistsSync(input *v3io.CheckPathExistsInput) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	p := cleanPath(input.Path)
+	if _, ok := c.entries[p]; ok || c.dirs[p] {
+		return nil
+	}
+	return notFound(input.Path)
+}
+
+// GetObject reads a file
+func (c *Container) GetObject(input *v3io.GetObjectInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.GetObjectSync(input) })
+}
+
+// GetObjectSync reads a file, the body is in the response HTTPResponse.
+// Ranges that end before the end of the file return
+// http.StatusPartialContent.
+func (c *Container) GetObjectSync(input *v3io.GetObjectInput) (*v3io.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.entries[cleanPath(input.Path)]
+	if !ok {
+		return nil, notFound(input.Path)
+	}
+
+	body := e.body
+	status := http.StatusOK
+	if input.Offset != 0 || input.NumBytes != 0 {
+		start, end := input.Offset, len(body)
+		if input.NumBytes > 0 && start+input.NumBytes < end {
+			end = start + input.NumBytes
+			status = http.StatusPartialContent
+		}
+		if start > len(body) {
+			start = len(body)
+		}
+		body = body[start:end]
+	}
+
+	response := newResponse(input, nil, nil)
+	response.HTTPResponse = fasthttp.AcquireResponse()
+	response.HTTPResponse.SetStatusCode(status)
+	response.HTTPResponse.SetBody(body)
+	return response, nil
+}
+
+// PutObject writes a file
+func (c *Container) PutObject(input *v3io.PutObjectInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) {
+		return newResponse(input, context, responseChan), c.PutObjectSync(input)
+	})
+}
+
+// PutObjectSync writes a file, a path that ends with a slash creates a
+// directory
+func (c *Container) PutObjectSync(input *v3io.PutObjectInput) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	p := cleanPath(input.Path)
+	if strings.HasSuffix(input.Path, "/") {
+		c.addDirs(p + "/")
+		return nil
+	}
+
+	if c.dirs[p] {
+		return newError(http.StatusConflict, "%q is a directory", input.Path)
+	}
+
+	e := c.getOrCreateEntry(p)
+	switch {
+	case input.Append:
+		e.body = append(e.body, input.Body...)
+	case input.Offset > 0:
+		if input.Offset > len(e.body) {
+			e.body = append(e.body, make([]byte, input.Offset-len(e.body))...)
+		}
+		body := append([]byte{}, e.body[:input.Offset]...)
+		body = append(body, input.Body...)
+		if rest := input.Offset + len(input.Body); rest < len(e.body) {
+			body = append(body, e.body[rest:]...)
+		}
+		e.body = body
+	default:
+		e.body = append([]byte{}, input.Body...)
+	}
+	e.mtime = c.now()
+
+	return nil
+}
+
+// getOrCreateEntry returns the entry at p, c.lock must be held
+func (c *Container) getOrCreateEntry(p string) *entry {
+	e, ok := c.entries[p]
+	if !ok {
+		now := c.now()
+		e = &entry{attrs: make(map[string]interface{}), ctime: now, mtime: now}
+		c.entries[p] = e
+		c.addDirs(p)
+	}
+	return e
+}
+
+// UpdateObjectSync updates directory attributes, they're ignored
+func (c *Container) UpdateObjectSync(input *v3io.UpdateObjectInput) error {
+	return c.CheckPathExistsSync(&v3io.CheckPathExistsInput{Path: input.Path})
+}
+
+// DeleteObject deletes a file or an empty directory
+func (c *Container) DeleteObject(input *v3io.DeleteObjectInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) {
+		return newResponse(input, context, responseChan), c.DeleteObjectSync(input)
+	})
+}
+
+// DeleteObjectSync deletes a file or an empty directory
+func (c *Container) DeleteObjectSync(input *v3io.DeleteObjectInput) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	p := cleanPath(input.Path)
+	if _, ok := c.entries[p]; ok {
+		delete(c.entries, p)
+		return nil
+	}
+
+	if !c.dirs[p] || p == "" {
+		return notFound(input.Path)
+	}
+
+	if c.hasChildren(p) {
+		return newError(http.StatusConflict, "directory %q is not empty", input.Path)
+	}
+
+	delete(c.dirs, p)
+	delete(c.streams, p)
+	return nil
+}
+
+// GetItem reads an item
+func (c *Container) GetItem(input *v3io.GetItemInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.GetItemSync(input) })
+}
+
+// GetItemSync reads an item
+func (c *Container) GetItemSync(input *v3io.GetItemInput) (*v3io.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	p := cleanPath(input.Path)
+	e, ok := c.entries[p]
+	if !ok {
+		return nil, notFound(input.Path)
+	}
+
+	response := newResponse(input, nil, nil)
+	response.Output = &v3io.GetItemOutput{Item: c.itemAttributes(p, e, input.AttributeNames)}
+	return response, nil
+}
+
+// allAttributes returns the user and system attributes of an entry
+func allAttributes(p string, e *entry) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(e.attrs)+8)
+	for name, value := range e.attrs {
+		attrs[name] = value
+	}
+
+	_, name := path.Split(p)
+	attrs["__name"] = name
+	attrs["__size"] = len(e.body)
+	attrs["__mtime_secs"] = int(e.mtime.Unix())
+	attrs["__mtime_nsecs"] = e.mtime.Nanosecond()
+	attrs["__ctime_secs"] = int(e.ctime.Unix())
+	attrs["__ctime_nsecs"] = e.ctime.Nanosecond()
+	attrs["__atime_secs"] = int(e.mtime.Unix())
+	attrs["__atime_nsecs"] = e.mtime.Nanosecond()
+	attrs["__mode"] = 0100644
+	attrs["__uid"] = 0
+	attrs["__gid"] = 0
+	attrs["__obj_type"] = 1
+	attrs["__collection_id"] = 0
+	return attrs
+}
+
+// itemAttributes returns the requested attributes of an entry. "*" is all
+// user attributes (and __name), "**" is all attributes.
+func (c *Container) itemAttributes(p string, e *entry, names []string) v3io.Item {
+	all := allAttributes(p, e)
+	item := v3io.Item{}
+	for _, name := range names {
+		switch name {
+		case "**":
+			for attr, value := range all {
+				item[attr] = value
+			}
+		case "*":
+			for attr, value := range e.attrs {
+				item[attr] = value
+			}
+			item["__name"] = all["__name"]
+		default:
+			if value, ok := all[name]; ok {
+				item[name] = value
+			}
+		}
+	}
+	return item
+}
+
+// GetItems reads items
+func (c *Container) GetItems(input *v3io.GetItemsInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.GetItemsSync(input) })
+}
+
+// GetItemsSync reads the items of a directory (not recursively). Items are
+// ordered by name, the marker is the name of the last returned item.
+func (c *Container) GetItemsSync(input *v3io.GetItemsInput) (*v3io.Response, error) {
+	filter, err := parseCondition(input.Filter)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	dir := cleanPath(input.Path)
+	if !c.dirs[dir] {
+		return nil, notFound(input.Path)
+	}
+
+	var names []string
+	for p := range c.entries {
+		if parentDir(p) == dir && c.inScan(p, input) {
+			names = append(names, p)
+		}
+	}
+	sort.Strings(names)
+
+	limit := input.Limit
+	if limit <= 0 || limit > c.GetItemsLimit {
+		limit = c.GetItemsLimit
+	}
+
+	output := &v3io.GetItemsOutput{Last: true}
+	for _, p := range names {
+		_, name := path.Split(p)
+		if input.Marker != "" && name <= input.Marker {
+			continue
+		}
+
+		e := c.entries[p]
+		ok, err := filter.match(allAttributes(p, e))
+		if err != nil {
+			return nil, badRequest(err)
+		}
+		if !ok {
+			continue
+		}
+
+		if len(output.Items) == limit {
+			output.Last = false
+			break
+		}
+
+		attributeNames := input.AttributeNames
+		if len(attributeNames) == 0 {
+			attributeNames = []string{"*"}
+		}
+		output.Items = append(output.Items, c.itemAttributes(p, e, attributeNames))
+		output.NextMarker = name
+	}
+
+	if output.Last {
+		output.NextMarker = ""
+	}
+
+	response := newResponse(input, nil, nil)
+	response.Output = output
+	return response, nil
+}
+
+// inScan returns true if the item at p is in the segment, sharding key and
+// sorting key range of input
+func (c *Container) inScan(p string, input *v3io.GetItemsInput) bool {
+	_, name := path.Split(p)
+	if input.TotalSegments > 1 {
+		hash := fnv.New32a()
+		hash.Write([]byte(name))
+		if int(hash.Sum32()%uint32(input.TotalSegments)) != input.Segment {
+			return false
+		}
+	}
+
+	if input.ShardingKey != "" {
+		if !strings.HasPrefix(name, input.ShardingKey+".") {
+			return false
+		}
+		sortingKey := name[len(input.ShardingKey)+1:]
+		if input.SortKeyRangeStart != "" && sortingKey < input.SortKeyRangeStart {
+			return false
+		}
+		if input.SortKeyRangeEnd != "" && sortingKey >= input.SortKeyRangeEnd {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PutItem writes an item
+func (c *Container) PutItem(input *v3io.PutItemInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.PutItemSync(input) })
+}
+
+// PutItemSync writes an item, replacing all its attributes
+func (c *Container) PutItemSync(input *v3io.PutItemInput) (*v3io.Response, error) {
+	mode := input.UpdateMode
+	if mode == "" {
+		mode = "OverWriteAttributes"
+	}
+
+	if err := c.updateItem(input.Path, input.Attributes, nil, input.Condition, mode); err != nil {
+		return nil, err
+	}
+	return c.updateResponse(input, input.Path), nil
+}
+
+// PutItems writes items under input.Path
+func (c *Container) PutItems(input *v3io.PutItemsInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.PutItemsSync(input) })
+}
+
+// PutItemsSync writes items under input.Path, errors are in the output
+func (c *Container) PutItemsSync(input *v3io.PutItemsInput) (*v3io.Response, error) {
+	output := &v3io.PutItemsOutput{Success: true, Errors: make(map[string]error)}
+	for key, attributes := range input.Items {
+		err := c.updateItem(input.Path+"/"+key, attributes, nil, input.Condition, "OverWriteAttributes")
+		if err != nil {
+			output.Success = false
+			output.Errors[key] = err
+		}
+	}
+
+	response := newResponse(input, nil, nil)
+	response.Output = output
+	return response, nil
+}
+
+// UpdateItem updates an item
+func (c *Container) UpdateItem(input *v3io.UpdateItemInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.UpdateItemSync(input) })
+}
+
+// UpdateItemSync updates an item with attributes and/or an update
+// expression. Supported modes are CreateOrReplaceAttributes (default),
+// OverWriteAttributes and CreateNewItemOnly.
+func (c *Container) UpdateItemSync(input *v3io.UpdateItemInput) (*v3io.Response, error) {
+	mode := input.UpdateMode
+	if mode == "" {
+		mode = "CreateOrReplaceAttributes"
+	}
+
+	if err := c.updateItem(input.Path, input.Attributes, input.Expression, input.Condition, mode); err != nil {
+		return nil, err
+	}
+	return c.updateResponse(input, input.Path), nil
+}
+
+func (c *Container) updateResponse(input interface{}, p string) *v3io.Response {
+	c.lock.Lock()
+	mtime := c.now()
+	if e, ok := c.entries[cleanPath(p)]; ok {
+		mtime = e.mtime
+	}
+	c.lock.Unlock()
+
+	response := newResponse(input, nil, nil)
+	switch input.(type) {
+	case *v3io.PutItemInput:
+		response.Output = &v3io.PutItemOutput{MtimeSecs: int(mtime.Unix()), MtimeNSecs: mtime.Nanosecond()}
+	default:
+		response.Output = &v3io.UpdateItemOutput{MtimeSecs: int(mtime.Unix()), MtimeNSecs: mtime.Nanosecond()}
+	}
+	return response
+}
+
+func (c *Container) updateItem(itemPath string, attributes map[string]interface{}, expression *string, conditionExpr string, mode string) error {
+	cond, err := parseCondition(conditionExpr)
+	if err != nil {
+		return badRequest(err)
+	}
+
+	var updates []*update
+	if expression != nil {
+		if updates, err = parseUpdateExpression(*expression); err != nil {
+			return badRequest(err)
+		}
+	}
+
+	for name, value := range attributes {
+		if err := validateValue(name, value); err != nil {
+			return badRequest(err)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	p := cleanPath(itemPath)
+	if p == "" || c.dirs[p] {
+		return newError(http.StatusBadRequest, "%q is not an item path", itemPath)
+	}
+
+	e, exists := c.entries[p]
+	if exists && mode == "CreateNewItemOnly" {
+		return itemExists(itemPath)
+	}
+
+	current := map[string]interface{}{}
+	if exists {
+		current = allAttributes(p, e)
+	}
+
+	ok, err := cond.match(current)
+	if err != nil {
+		return badRequest(err)
+	}
+	if !ok {
+		return falseCondition()
+	}
+
+	attrs := make(map[string]interface{})
+	if exists && mode != "OverWriteAttributes" {
+		for name, value := range e.attrs {
+			attrs[name] = value
+		}
+	}
+
+	for name, value := range attributes {
+		attrs[name] = normalizeValue(value)
+	}
+
+	for _, u := range updates {
+		if strings.HasPrefix(u.name, "__") {
+			return newError(http.StatusBadRequest, "can't update system attribute %q", u.name)
+		}
+
+		// Expressions see the stored attributes (including system ones) too
+		scope := make(map[string]interface{}, len(attrs)+len(current))
+		for name, value := range current {
+			scope[name] = value
+		}
+		for name, value := range attrs {
+			scope[name] = value
+		}
+
+		if err := u.apply(scope); err != nil {
+			return badRequest(err)
+		}
+
+		if u.remove {
+			delete(attrs, u.name)
+		} else {
+			attrs[u.name] = scope[u.name]
+		}
+	}
+
+	e = c.getOrCreateEntry(p)
+	e.attrs = attrs
+	e.mtime = c.now()
+	return nil
+}
+
+// PutChunk is not supported
+func (c *Container) PutChunk(input *v3io.PutChunkInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return nil, notImplemented("PutChunk")
+}
+
+// PutChunkSync is not supported
+func (c *Container) PutChunkSync(input *v3io.PutChunkInput) error {
+	return notImplemented("PutChunk")
+}
+
+// PutOOSObject writes a file from the header and data parts
+func (c *Container) PutOOSObject(input *v3io.PutOOSObjectInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) {
+		return newResponse(input, context, responseChan), c.PutOOSObjectSync(input)
+	})
+}
+
+// PutOOSObjectSync writes a file from the header and data parts
+func (c *Container) PutOOSObjectSync(input *v3io.PutOOSObjectInput) error {
+	body := append([]byte{}, input.Header...)
+	for _, data := range input.Data {
+		body = append(body, data...)
+	}
+
+	return c.PutObjectSync(&v3io.PutObjectInput{Path: input.Path, Body: body})
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+	v3ioerrors "github.com/v3io/v3io-go/pkg/errors"
+)
+
+func statusCode(err error) int {
+	if errWithStatus, ok := err.(v3ioerrors.ErrorWithStatusCode); ok {
+		return errWithStatus.StatusCode()
+	}
+	return 0
+}
+
+func putItems(t *testing.T, c *Container, dir string, n int) {
+	for i := 0; i < n; i++ {
+		input := &v3io.PutItemInput{
+			Path:       fmt.Sprintf("%s/%d", dir, i),
+			Attributes: map[string]interface{}{"i": i, "s": fmt.Sprintf("s%d", i)},
+		}
+		if _, err := c.PutItemSync(input); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetItems(t *testing.T) {
+	c := NewContainer("test")
+	putItems(t, c, "table", 10)
+
+	var names []string
+	marker := ""
+	for {
+		input := &v3io.GetItemsInput{
+			Path:           "table/",
+			AttributeNames: []string{"__name", "i"},
+			Filter:         "i >= 3",
+			Limit:          3,
+			Marker:         marker,
+		}
+		resp, err := c.GetItemsSync(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output := resp.Output.(*v3io.GetItemsOutput)
+		for _, item := range output.Items {
+			names = append(names, item["__name"].(string))
+		}
+
+		if output.Last {
+			break
+		}
+		marker = output.NextMarker
+	}
+
+	if len(names) != 7 {
+		t.Fatalf("expected 7 items, got %v", names)
+	}
+
+	if _, err := c.GetItemsSync(&v3io.GetItemsInput{Path: "nope/"}); statusCode(err) != http.StatusNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestGetItemsSegments(t *testing.T) {
+	c := NewContainer("test")
+	putItems(t, c, "table", 20)
+
+	total := 0
+	for segment := 0; segment < 3; segment++ {
+		input := &v3io.GetItemsInput{
+			Path:          "table/",
+			Segment:       segment,
+			TotalSegments: 3,
+		}
+		resp, err := c.GetItemsSync(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += len(resp.Output.(*v3io.GetItemsOutput).Items)
+	}
+
+	if total != 20 {
+		t.Fatalf("expected 20 items in all segments, got %d", total)
+	}
+}
+
+func TestUpdateItem(t *testing.T) {
+	c := NewContainer("test")
+	putItems(t, c, "table", 1)
+
+	expr := "i = i + 1; t = 'x'"
+	input := &v3io.UpdateItemInput{
+		Path:       "table/0",
+		Expression: &expr,
+		Condition:  "i == 0",
+	}
+	if _, err := c.UpdateItemSync(input); err != nil {
+		t.Fatal(err)
+	}
+
+	// Condition is now false
+	if _, err := c.UpdateItemSync(input); err == nil {
+		t.Fatal("no error on false condition")
+	}
+
+	resp, err := c.GetItemSync(&v3io.GetItemInput{Path: "table/0", AttributeNames: []string{"*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := resp.Output.(*v3io.GetItemOutput).Item
+	if item["i"] != 1 || item["s"] != "s0" || item["t"] != "x" {
+		t.Fatalf("bad item: %v", item)
+	}
+
+	input = &v3io.UpdateItemInput{
+		Path:       "table/0",
+		Attributes: map[string]interface{}{"i": 7},
+		UpdateMode: "CreateNewItemOnly",
+	}
+	if _, err := c.UpdateItemSync(input); err == nil {
+		t.Fatal("no error on existing item")
+	}
+}
+
+func TestObjects(t *testing.T) {
+	c := NewContainer("test")
+	if err := c.PutObjectSync(&v3io.PutObjectInput{Path: "dir/obj", Body: []byte("hello")}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.GetObjectSync(&v3io.GetObjectInput{Path: "dir/obj"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := string(resp.Body()); body != "hello" {
+		t.Fatalf("bad body: %q", body)
+	}
+
+	resp, err = c.GetContainerContentsSync(&v3io.GetContainerContentsInput{Path: ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefixes := resp.Output.(*v3io.GetContainerContentsOutput).CommonPrefixes; len(prefixes) != 1 {
+		t.Fatalf("expected one directory, got %v", prefixes)
+	}
+
+	if err := c.DeleteObjectSync(&v3io.DeleteObjectInput{Path: "dir/"}); err == nil {
+		t.Fatal("deleted non empty directory")
+	}
+
+	if err := c.DeleteObjectSync(&v3io.DeleteObjectInput{Path: "dir/obj"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.CheckPathExistsSync(&v3io.CheckPathExistsInput{Path: "dir/obj"})
+	if statusCode(err) != http.StatusNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	c := NewContainer("test")
+	if err := c.CreateStreamSync(&v3io.CreateStreamInput{Path: "stream/", ShardCount: 2, RetentionPeriodHours: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	shardID := 1
+	input := &v3io.PutRecordsInput{
+		Path: "stream/",
+		Records: []*v3io.StreamRecord{
+			{ShardID: &shardID, Data: []byte("r1")},
+			{ShardID: &shardID, Data: []byte("r2")},
+		},
+	}
+	if _, err := c.PutRecordsSync(input); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.SeekShardSync(&v3io.SeekShardInput{Path: "stream/1", Type: v3io.SeekShardInputTypeEarliest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := resp.Output.(*v3io.SeekShardOutput).Location
+
+	resp, err = c.GetRecordsSync(&v3io.GetRecordsInput{Path: "stream/1", Location: location, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := resp.Output.(*v3io.GetRecordsOutput).Records
+	if len(records) != 2 || string(records[1].Data) != "r2" {
+		t.Fatalf("bad records: %v", records)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package fake
+
+import (
+	"sync"
+
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+)
+
+// Context is an in-memory v3io.Context, containers are created on first use
+// and shared by all sessions. Data plane calls on the context itself go to
+// the container named "".
+type Context struct {
+	*Container
+
+	lock       sync.Mutex
+	containers map[string]*Container
+}
+
+var (
+	// Make sure we're implementing v3io.Context
+	_ v3io.Context = &Context{}
+)
+
+// NewContext returns a new context with no data
+func NewContext() *Context {
+	ctx := &Context{
+		containers: make(map[string]*Container),
+	}
+	ctx.Container = ctx.GetContainer("")
+	return ctx
+}
+
+// GetContainer returns the container by name, creating it if needed
+func (c *Context) GetContainer(name string) *Container {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	container, ok := c.containers[name]
+	if !ok {
+		container = NewContainer(name)
+		c.containers[name] = container
+	}
+	return container
+}
+
+// NewSession returns a new session, credentials are ignored
+func (c *Context) NewSession(input *v3io.NewSessionInput) (v3io.Session, error) {
+	return &session{context: c}, nil
+}
+
+type session struct {
+	context *Context
+}
+
+func (s *session) NewContainer(input *v3io.NewContainerInput) (v3io.Container, error) {
+	return s.context.GetContainer(input.ContainerName), nil
+}
@@ -0,0 +1,939 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package fake
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A subset of the V3IO expression language used by conditions, filters and
+// update expressions:
+//
+//	a == 1 AND (b > 2.5 OR NOT exists(c)) AND d IN ('x', 'y')
+//	SET a = a + 1; b = if_not_exists(b, 0); REMOVE c; delete(d)
+//
+// Timestamps are written as seconds:nanoseconds (e.g. 1546300800:0).
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	identToken
+	numberToken
+	timeToken
+	stringToken
+	opToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			var buf strings.Builder
+			for i++; ; i++ {
+				if i >= len(expr) {
+					return nil, fmt.Errorf("unterminated string at %d", start)
+				}
+				if expr[i] == '\\' && i+1 < len(expr) {
+					i++
+					buf.WriteByte(expr[i])
+					continue
+				}
+				if expr[i] == c {
+					i++
+					break
+				}
+				buf.WriteByte(expr[i])
+			}
+			tokens = append(tokens, token{stringToken, buf.String()})
+		case c == '`':
+			end := strings.IndexByte(expr[i+1:], '`')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated name at %d", start)
+			}
+			tokens = append(tokens, token{identToken, expr[i+1 : i+1+end]})
+			i += end + 2
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9'):
+			for i < len(expr) && (isDigit(expr[i]) || expr[i] == '.' ||
+				((expr[i] == 'e' || expr[i] == 'E') && i+1 < len(expr)) ||
+				((expr[i] == '+' || expr[i] == '-') && (expr[i-1] == 'e' || expr[i-1] == 'E'))) {
+				i++
+			}
+			kind := numberToken
+			if i+1 < len(expr) && expr[i] == ':' && isDigit(expr[i+1]) {
+				for i++; i < len(expr) && isDigit(expr[i]); i++ {
+				}
+				kind = timeToken
+			}
+			tokens = append(tokens, token{kind, expr[start:i]})
+		case isIdentByte(c, true):
+			for i < len(expr) && isIdentByte(expr[i], false) {
+				i++
+			}
+			tokens = append(tokens, token{identToken, expr[start:i]})
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "+=", "-=", "<", ">", "=", "!", "(", ")", ",", ";", "+", "-", "*", "/", "%", "[", "]"} {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+			i += len(op)
+			tokens = append(tokens, token{opToken, op})
+		}
+	}
+
+	return append(tokens, token{kind: eofToken}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentByte(c byte, first bool) bool {
+	return c == '_' || unicode.IsLetter(rune(c)) || (!first && (isDigit(c) || c == '.'))
+}
+
+// node is a parsed expression
+type node interface {
+	eval(attrs map[string]interface{}) (interface{}, error)
+}
+
+// missing is the value of attributes that don't exist
+type missingValue struct{}
+
+var missing = missingValue{}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type attrNode struct {
+	name string
+}
+
+func (n *attrNode) eval(attrs map[string]interface{}) (interface{}, error) {
+	value, ok := attrs[n.name]
+	if !ok {
+		return missing, nil
+	}
+	return normalizeEvalValue(value), nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(attrs map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "not":
+		b, ok := value.(bool)
+		if !ok {
+			return false, nil
+		}
+		return !b, nil
+	case "-":
+		switch v := value.(type) {
+		case int64:
+			return -v, nil
+		case float64:
+			return -v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("bad operand for %s - %v", n.op, value)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(attrs map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit logical operators
+	switch n.op {
+	case "and":
+		if b, ok := left.(bool); !ok || !b {
+			return false, nil
+		}
+		right, err := n.right.eval(attrs)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := right.(bool)
+		return ok && b, nil
+	case "or":
+		if b, ok := left.(bool); ok && b {
+			return true, nil
+		}
+		right, err := n.right.eval(attrs)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := right.(bool)
+		return ok && b, nil
+	}
+
+	right, err := n.right.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return compareOp(n.op, left, right), nil
+	}
+
+	return arithmetic(n.op, left, right)
+}
+
+type inNode struct {
+	value  node
+	values []node
+}
+
+func (n *inNode) eval(attrs map[string]interface{}) (interface{}, error) {
+	value, err := n.value.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidateNode := range n.values {
+		candidate, err := candidateNode.eval(attrs)
+		if err != nil {
+			return nil, err
+		}
+		if compareOp("==", value, candidate) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n *callNode) eval(attrs map[string]interface{}) (interface{}, error) {
+	switch n.name {
+	case "exists":
+		if err := n.checkArgs(1); err != nil {
+			return nil, err
+		}
+		value, err := n.args[0].eval(attrs)
+		return value != missing, err
+	case "if_not_exists":
+		if err := n.checkArgs(2); err != nil {
+			return nil, err
+		}
+		value, err := n.args[0].eval(attrs)
+		if err != nil || value != missing {
+			return value, err
+		}
+		return n.args[1].eval(attrs)
+	}
+
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(attrs)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+
+	switch n.name {
+	case "starts", "ends", "contains":
+		if err := n.checkArgs(2); err != nil {
+			return nil, err
+		}
+		s, ok1 := args[0].(string)
+		sub, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		switch n.name {
+		case "starts":
+			return strings.HasPrefix(s, sub), nil
+		case "ends":
+			return strings.HasSuffix(s, sub), nil
+		}
+		return strings.Contains(s, sub), nil
+	case "length":
+		if err := n.checkArgs(1); err != nil {
+			return nil, err
+		}
+		switch v := args[0].(type) {
+		case string:
+			return int64(len(v)), nil
+		case []byte:
+			return int64(len(v)), nil
+		}
+		return nil, fmt.Errorf("bad argument for length - %v", args[0])
+	case "min", "max":
+		if err := n.checkArgs(2); err != nil {
+			return nil, err
+		}
+		less := compareOp("<", args[0], args[1])
+		if less == (n.name == "min") {
+			return args[0], nil
+		}
+		return args[1], nil
+	}
+
+	return nil, fmt.Errorf("unknown function %q", n.name)
+}
+
+func (n *callNode) checkArgs(count int) error {
+	if len(n.args) != count {
+		return fmt.Errorf("%s expects %d arguments, got %d", n.name, count, len(n.args))
+	}
+	return nil
+}
+
+// normalizeEvalValue converts attribute values to the types used in
+// evaluation (int64, float64, string, bool, []byte, time.Time)
+func normalizeEvalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// compareOp compares two values, values of different types (and missing
+// attributes) are never equal
+func compareOp(op string, left, right interface{}) bool {
+	cmp, ok := compareValues(left, right)
+	if !ok {
+		return op == "!=" && left != missing && right != missing
+	}
+
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareValues(left, right interface{}) (int, bool) {
+	if l, ok := left.(int64); ok {
+		if r, ok := right.(int64); ok {
+			switch {
+			case l < r:
+				return -1, true
+			case l > r:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	if l, ok := toFloat(left); ok {
+		r, ok := toFloat(right)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case l < r:
+			return -1, true
+		case l > r:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r), true
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			if l == r {
+				return 0, true
+			}
+			if !l {
+				return -1, true
+			}
+			return 1, true
+		}
+	case []byte:
+		if r, ok := right.([]byte); ok {
+			return bytes.Compare(l, r), true
+		}
+	case time.Time:
+		if r, ok := right.(time.Time); ok {
+			switch {
+			case l.Before(r):
+				return -1, true
+			case l.After(r):
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
+
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	if l, ok := left.(string); ok && op == "+" {
+		if r, ok := right.(string); ok {
+			return l + r, nil
+		}
+	}
+
+	if l, ok := left.(int64); ok {
+		if r, ok := right.(int64); ok {
+			switch op {
+			case "+":
+				return l + r, nil
+			case "-":
+				return l - r, nil
+			case "*":
+				return l * r, nil
+			case "/", "%":
+				if r == 0 {
+					return nil, fmt.Errorf("division by zero")
+				}
+				if op == "%" {
+					return l % r, nil
+				}
+				return l / r, nil
+			}
+		}
+	}
+
+	l, ok1 := toFloat(left)
+	r, ok2 := toFloat(right)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("bad operands for %s - %v, %v", op, left, right)
+	}
+
+	switch op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		return l / r, nil
+	case "%":
+		return math.Mod(l, r), nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+// parser is a recursive descent parser over tokens
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func newParser(expr string) (*parser, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != eofToken {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	tok := p.peek()
+	if tok.kind != opToken {
+		return false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == identToken && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *parser) expectOp(op string) error {
+	if !p.isOp(op) {
+		return fmt.Errorf("expected %q, got %q", op, p.peek().text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("or") || p.isOp("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("and") || p.isOp("&&") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isKeyword("not") || p.isOp("!") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "not", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isOp("==", "!=", "<", "<=", ">", ">=", "=") {
+		op := p.next().text
+		if op == "=" {
+			op = "=="
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op, left: left, right: right}, nil
+	}
+
+	if p.isKeyword("in") {
+		p.next()
+		if err := p.expectOp("("); err != nil {
+			return nil, err
+		}
+		values, err := p.parseList(")")
+		if err != nil {
+			return nil, err
+		}
+		return &inNode{value: left, values: values}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("+", "-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("*", "/", "%") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOp("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if literal, ok := operand.(*literalNode); ok {
+			return (&unaryNode{op: "-", operand: literal}).fold()
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// fold evaluates a negative literal
+func (n *unaryNode) fold() (node, error) {
+	value, err := n.eval(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &literalNode{value: value}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case numberToken:
+		if i, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return &literalNode{value: i}, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q", tok.text)
+		}
+		return &literalNode{value: f}, nil
+	case timeToken:
+		parts := strings.SplitN(tok.text, ":", 2)
+		sec, err1 := strconv.ParseInt(parts[0], 10, 64)
+		nsec, err2 := strconv.ParseInt(parts[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("bad timestamp %q", tok.text)
+		}
+		return &literalNode{value: time.Unix(sec, nsec)}, nil
+	case stringToken:
+		return &literalNode{value: tok.text}, nil
+	case identToken:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		}
+		if p.isOp("(") {
+			p.next()
+			args, err := p.parseList(")")
+			if err != nil {
+				return nil, err
+			}
+			return &callNode{name: strings.ToLower(tok.text), args: args}, nil
+		}
+		return &attrNode{name: tok.text}, nil
+	case opToken:
+		if tok.text == "(" {
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expectOp(")")
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+// parseList parses comma separated expressions up to end
+func (p *parser) parseList(end string) ([]node, error) {
+	var nodes []node
+	if p.isOp(end) {
+		p.next()
+		return nodes, nil
+	}
+
+	for {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, expr)
+
+		if p.isOp(end) {
+			p.next()
+			return nodes, nil
+		}
+		if err := p.expectOp(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// condition is a parsed filter or condition
+type condition struct {
+	expr node
+}
+
+// parseCondition parses a filter or a condition, empty conditions are true
+func parseCondition(expr string) (*condition, error) {
+	if strings.TrimSpace(expr) == "" {
+		return &condition{}, nil
+	}
+
+	p, err := newParser(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != eofToken {
+		return nil, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+
+	return &condition{expr: node}, nil
+}
+
+func (c *condition) match(attrs map[string]interface{}) (bool, error) {
+	if c.expr == nil {
+		return true, nil
+	}
+
+	value, err := c.expr.eval(attrs)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := value.(bool)
+	return ok && b, nil
+}
+
+// update is a single update expression action
+type update struct {
+	name   string
+	op     string // =, += -= or remove
+	value  node
+	remove bool
+}
+
+// parseUpdateExpression parses ; separated actions
+func parseUpdateExpression(expr string) ([]*update, error) {
+	p, err := newParser(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []*update
+	for p.peek().kind != eofToken {
+		if p.isOp(";") {
+			p.next()
+			continue
+		}
+
+		switch {
+		case p.isKeyword("set"):
+			p.next()
+			for {
+				action, err := p.parseAssignment()
+				if err != nil {
+					return nil, err
+				}
+				updates = append(updates, action)
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+		case p.isKeyword("remove"):
+			p.next()
+			for {
+				tok := p.next()
+				if tok.kind != identToken {
+					return nil, fmt.Errorf("expected attribute name, got %q", tok.text)
+				}
+				updates = append(updates, &update{name: tok.text, remove: true})
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+		case p.isKeyword("delete") && p.tokens[p.pos+1].text == "(":
+			p.next()
+			p.next()
+			tok := p.next()
+			if tok.kind != identToken {
+				return nil, fmt.Errorf("expected attribute name, got %q", tok.text)
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			updates = append(updates, &update{name: tok.text, remove: true})
+		default:
+			action, err := p.parseAssignment()
+			if err != nil {
+				return nil, err
+			}
+			updates = append(updates, action)
+		}
+
+		if !p.isOp(";") && p.peek().kind != eofToken {
+			return nil, fmt.Errorf("expected ';', got %q", p.peek().text)
+		}
+	}
+
+	return updates, nil
+}
+
+func (p *parser) parseAssignment() (*update, error) {
+	tok := p.next()
+	if tok.kind != identToken {
+		return nil, fmt.Errorf("expected attribute name, got %q", tok.text)
+	}
+
+	if !p.isOp("=", "+=", "-=") {
+		return nil, fmt.Errorf("expected assignment to %q, got %q", tok.text, p.peek().text)
+	}
+	op := p.next().text
+
+	value, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	return &update{name: tok.text, op: op, value: value}, nil
+}
+
+// apply applies the update to attrs
+func (u *update) apply(attrs map[string]interface{}) error {
+	if u.remove {
+		delete(attrs, u.name)
+		return nil
+	}
+
+	value, err := u.value.eval(attrs)
+	if err != nil {
+		return err
+	}
+
+	if u.op != "=" {
+		current, ok := attrs[u.name]
+		if !ok {
+			return fmt.Errorf("attribute %q doesn't exist", u.name)
+		}
+		if value, err = arithmetic(u.op[:1], normalizeEvalValue(current), value); err != nil {
+			return err
+		}
+	}
+
+	if value == missing {
+		return fmt.Errorf("attribute in assignment to %q doesn't exist", u.name)
+	}
+
+	attrs[u.name] = normalizeValue(value)
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package fake
+
+import (
+	"testing"
+)
+
+func TestCondition(t *testing.T) {
+	attrs := map[string]interface{}{
+		"a":      1,
+		"b":      2.5,
+		"s":      "hello",
+		"flag":   true,
+		"__name": "item1",
+	}
+
+	testCases := []struct {
+		expr     string
+		expected bool
+	}{
+		{"", true},
+		{"a == 1", true},
+		{"a = 1", true},
+		{"a != 1", false},
+		{"a < b", true},
+		{"a + 1 >= b", false},
+		{"a * 3 > b", true},
+		{"s == 'hello'", true},
+		{`s == "hello"`, true},
+		{"s > 'abc' AND a == 1", true},
+		{"a == 2 OR flag == true", true},
+		{"NOT (a == 1)", false},
+		{"a IN (3, 2, 1)", true},
+		{"s IN ('x', 'y')", false},
+		{"exists(a)", true},
+		{"exists(nope)", false},
+		{"nope == 1", false},
+		{"starts(s, 'he')", true},
+		{"ends(s, 'lo')", true},
+		{"contains(s, 'ell')", true},
+		{"length(s) == 5", true},
+		{"__name == 'item1'", true},
+		{"`a` == 1", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			cond, err := parseCondition(tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ok, err := cond.match(attrs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ok != tc.expected {
+				t.Fatalf("%q: expected %v, got %v", tc.expr, tc.expected, ok)
+			}
+		})
+	}
+}
+
+func TestConditionErrors(t *testing.T) {
+	for _, expr := range []string{"a ==", "(a == 1", "a == 1 b", "'open"} {
+		if _, err := parseCondition(expr); err == nil {
+			t.Fatalf("%q: no error", expr)
+		}
+	}
+}
+
+func TestUpdateExpression(t *testing.T) {
+	attrs := map[string]interface{}{
+		"a": 1,
+		"b": 2,
+		"c": "x",
+	}
+
+	expr := "SET d=a+b, e='new'; a += 10; REMOVE c; f=if_not_exists(f, 0) + 1"
+	updates, err := parseUpdateExpression(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, u := range updates {
+		if err := u.apply(attrs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string]interface{}{
+		"a": 11,
+		"b": 2,
+		"d": 3,
+		"e": "new",
+		"f": 1,
+	}
+
+	if len(attrs) != len(expected) {
+		t.Fatalf("bad attributes: %v", attrs)
+	}
+
+	for name, value := range expected {
+		if attrs[name] != value {
+			t.Fatalf("%s: expected %v, got %v (%T)", name, value, attrs[name], attrs[name])
+		}
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package fake
+
+import (
+	"hash/fnv"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	v3io "github.com/v3io/v3io-go/pkg/dataplane"
+)
+
+// stream is a stream directory, shards are addressed as stream/<shard id>
+type stream struct {
+	shards         [][]*record
+	retentionHours int
+	nextShard      int // round robin shard for records without a shard or a partition key
+}
+
+type record struct {
+	v3io.GetRecordsResult
+}
+
+// CreateStream creates a stream
+func (c *Container) CreateStream(input *v3io.CreateStreamInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) {
+		return newResponse(input, context, responseChan), c.CreateStreamSync(input)
+	})
+}
+
+// CreateStreamSync creates a stream
+func (c *Container) CreateStreamSync(input *v3io.CreateStreamInput) error {
+	if input.ShardCount <= 0 {
+		return newError(http.StatusBadRequest, "bad shard count - %d", input.ShardCount)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	p := cleanPath(input.Path)
+	if _, ok := c.entries[p]; ok || c.dirs[p] {
+		return newError(http.StatusConflict, "%q already exists", input.Path)
+	}
+
+	c.addDirs(p + "/")
+	c.streams[p] = &stream{
+		shards:         make([][]*record, input.ShardCount),
+		retentionHours: input.RetentionPeriodHours,
+	}
+	return nil
+}
+
+// DescribeStream returns stream information
+func (c *Container) DescribeStream(input *v3io.DescribeStreamInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.DescribeStreamSync(input) })
+}
+
+// DescribeStreamSync returns stream information
+func (c *Container) DescribeStreamSync(input *v3io.DescribeStreamInput) (*v3io.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s, ok := c.streams[cleanPath(input.Path)]
+	if !ok {
+		return nil, notFound(input.Path)
+	}
+
+	response := newResponse(input, nil, nil)
+	response.Output = &v3io.DescribeStreamOutput{ShardCount: len(s.shards), RetentionPeriodHours: s.retentionHours}
+	return response, nil
+}
+
+// DeleteStream deletes a stream
+func (c *Container) DeleteStream(input *v3io.DeleteStreamInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) {
+		return newResponse(input, context, responseChan), c.DeleteStreamSync(input)
+	})
+}
+
+// DeleteStreamSync deletes a stream
+func (c *Container) DeleteStreamSync(input *v3io.DeleteStreamInput) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	p := cleanPath(input.Path)
+	if _, ok := c.streams[p]; !ok {
+		return notFound(input.Path)
+	}
+
+	delete(c.streams, p)
+	delete(c.dirs, p)
+	return nil
+}
+
+// shard returns the stream and shard at shardPath, c.lock must be held
+func (c *Container) shard(shardPath string) (*stream, int, error) {
+	p := cleanPath(shardPath)
+	dir, shardID := path.Split(p)
+	s, ok := c.streams[cleanPath(dir)]
+	if !ok {
+		return nil, 0, notFound(shardPath)
+	}
+
+	id, err := strconv.Atoi(shardID)
+	if err != nil || id < 0 || id >= len(s.shards) {
+		return nil, 0, notFound(shardPath)
+	}
+
+	return s, id, nil
+}
+
+// PutRecords adds records to a stream
+func (c *Container) PutRecords(input *v3io.PutRecordsInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.PutRecordsSync(input) })
+}
+
+// PutRecordsSync adds records to a stream. Records go to their ShardID, to
+// a shard by the hash of their PartitionKey, or round robin.
+func (c *Container) PutRecordsSync(input *v3io.PutRecordsInput) (*v3io.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s, ok := c.streams[cleanPath(input.Path)]
+	if !ok {
+		return nil, notFound(input.Path)
+	}
+
+	output := &v3io.PutRecordsOutput{}
+	now := c.now()
+	for _, inputRecord := range input.Records {
+		var shardID int
+		switch {
+		case inputRecord.ShardID != nil:
+			shardID = *inputRecord.ShardID
+		case inputRecord.PartitionKey != "":
+			hash := fnv.New32a()
+			hash.Write([]byte(inputRecord.PartitionKey))
+			shardID = int(hash.Sum32() % uint32(len(s.shards)))
+		default:
+			shardID = s.nextShard
+			s.nextShard = (s.nextShard + 1) % len(s.shards)
+		}
+
+		if shardID < 0 || shardID >= len(s.shards) {
+			output.FailedRecordCount++
+			output.Records = append(output.Records, v3io.PutRecordResult{
+				ShardID:      shardID,
+				ErrorCode:    genericErrorCode,
+				ErrorMessage: "bad shard ID",
+			})
+			continue
+		}
+
+		sequenceNumber := uint64(len(s.shards[shardID]) + 1)
+		s.shards[shardID] = append(s.shards[shardID], &record{v3io.GetRecordsResult{
+			ArrivalTimeSec:  int(now.Unix()),
+			ArrivalTimeNSec: now.Nanosecond(),
+			SequenceNumber:  sequenceNumber,
+			ClientInfo:      inputRecord.ClientInfo,
+			PartitionKey:    inputRecord.PartitionKey,
+			Data:            inputRecord.Data,
+		}})
+		output.Records = append(output.Records, v3io.PutRecordResult{SequenceNumber: sequenceNumber, ShardID: shardID})
+	}
+
+	response := newResponse(input, nil, nil)
+	response.Output = output
+	return response, nil
+}
+
+// SeekShard returns a shard location for GetRecords
+func (c *Container) SeekShard(input *v3io.SeekShardInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.SeekShardSync(input) })
+}
+
+// SeekShardSync returns a shard location for GetRecords, locations are
+// record offsets in the shard
+func (c *Container) SeekShardSync(input *v3io.SeekShardInput) (*v3io.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s, shardID, err := c.shard(input.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	records := s.shards[shardID]
+	location := len(records)
+	switch input.Type {
+	case v3io.SeekShardInputTypeEarliest:
+		location = 0
+	case v3io.SeekShardInputTypeLatest:
+	case v3io.SeekShardInputTypeSequence:
+		for i, r := range records {
+			if r.SequenceNumber >= input.StartingSequenceNumber {
+				location = i
+				break
+			}
+		}
+	case v3io.SeekShardInputTypeTime:
+		for i, r := range records {
+			if r.ArrivalTimeSec >= input.Timestamp {
+				location = i
+				break
+			}
+		}
+	default:
+		return nil, newError(http.StatusBadRequest, "bad seek type - %d", input.Type)
+	}
+
+	response := newResponse(input, nil, nil)
+	response.Output = &v3io.SeekShardOutput{Location: strconv.Itoa(location)}
+	return response, nil
+}
+
+// GetRecords reads records from a shard location
+func (c *Container) GetRecords(input *v3io.GetRecordsInput, context interface{}, responseChan chan *v3io.Response) (*v3io.Request, error) {
+	return async(input, context, responseChan, func() (*v3io.Response, error) { return c.GetRecordsSync(input) })
+}
+
+// GetRecordsSync reads records from a shard location
+func (c *Container) GetRecordsSync(input *v3io.GetRecordsInput) (*v3io.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s, shardID, err := c.shard(input.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	records := s.shards[shardID]
+	location, err := strconv.Atoi(input.Location)
+	if err != nil || location < 0 || location > len(records) {
+		return nil, newError(http.StatusBadRequest, "bad location - %q", input.Location)
+	}
+
+	end := len(records)
+	if input.Limit > 0 && location+input.Limit < end {
+		end = location + input.Limit
+	}
+
+	output := &v3io.GetRecordsOutput{
+		NextLocation:        strconv.Itoa(end),
+		RecordsBehindLatest: len(records) - end,
+	}
+	for _, r := range records[location:end] {
+		output.Records = append(output.Records, r.GetRecordsResult)
+	}
+
+	if end > 0 && end < len(records) {
+		last, latest := records[end-1], records[len(records)-1]
+		behind := time.Unix(int64(latest.ArrivalTimeSec), int64(latest.ArrivalTimeNSec)).Sub(
+			time.Unix(int64(last.ArrivalTimeSec), int64(last.ArrivalTimeNSec)))
+		output.MSecBehindLatest = int(behind / time.Millisecond)
+	}
+
+	response := newResponse(input, nil, nil)
+	response.Output = output
+	return response, nil
+}
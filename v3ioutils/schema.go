@@ -70,6 +70,10 @@ type OldV3ioSchema struct {
 	Key              string           `json:"key"`
 	SortingKey       string           `json:"sortingKey,omitempty"`
 	HashingBucketNum int              `json:"hashingBucketNum"`
+	// Attributes with a secondary index (NoSQL)
+	Indexes []string `json:"indexes,omitempty"`
+	// Schema mode of writes (evolve, strict or coerce), evolve if empty
+	Mode string `json:"mode,omitempty"`
 }
 
 // OldSchemaField is OldV3ioSchema field
@@ -77,6 +81,63 @@ type OldSchemaField struct {
 	Name     string `json:"name"`
 	Type     string `json:"type"`
 	Nullable bool   `json:"nullable"`
+	// Time columns: IANA time zone of read times (local time if empty) and
+	// precision of written times (s, ms, us or ns, the default)
+	TimeZone  string `json:"timeZone,omitempty"`
+	Precision string `json:"precision,omitempty"`
+}
+
+// timePrecisions are the durations of the time column precisions
+var timePrecisions = map[string]time.Duration{
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+// ValidateTimeFormat checks a time column time zone and precision
+func ValidateTimeFormat(timeZone string, precision string) error {
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return errors.Wrapf(err, "bad time zone %q", timeZone)
+	}
+
+	if _, ok := timePrecisions[precision]; precision != "" && !ok {
+		return fmt.Errorf("bad time precision %q, should be s, ms, us or ns", precision)
+	}
+
+	return nil
+}
+
+// TruncateTime truncates t to the field precision
+func (f OldSchemaField) TruncateTime(t time.Time) time.Time {
+	if duration, ok := timePrecisions[f.Precision]; ok && duration > time.Nanosecond {
+		return t.Truncate(duration)
+	}
+
+	return t
+}
+
+// mergeTimeFormat sets the time zone and precision of f from other if they
+// are not set
+func (f *OldSchemaField) mergeTimeFormat(other OldSchemaField) (bool, error) {
+	changed := false
+	if other.TimeZone != "" && other.TimeZone != f.TimeZone {
+		if f.TimeZone != "" {
+			return false, fmt.Errorf("changing the time zone of column %v from %s to %s is not allowed", f.Name, f.TimeZone, other.TimeZone)
+		}
+		f.TimeZone = other.TimeZone
+		changed = true
+	}
+
+	if other.Precision != "" && other.Precision != f.Precision {
+		if f.Precision != "" {
+			return changed, fmt.Errorf("changing the precision of column %v from %s to %s is not allowed", f.Name, f.Precision, other.Precision)
+		}
+		f.Precision = other.Precision
+		changed = true
+	}
+
+	return changed, nil
 }
 
 // AddColumn adds a column
@@ -137,6 +198,12 @@ func (s *OldV3ioSchema) merge(new *OldV3ioSchema) (bool, error) {
 		if index < 0 {
 			s.Fields = append(s.Fields, field)
 			changed = true
+		} else if field.Type == TimeType && s.Fields[index].Type == TimeType {
+			fieldChanged, err := s.Fields[index].mergeTimeFormat(field)
+			if err != nil {
+				return changed, err
+			}
+			changed = changed || fieldChanged
 		} else if field.Type != s.Fields[index].Type {
 			if s.Fields[index].Type == DoubleType && field.Type == LongType {
 				continue
@@ -181,6 +248,17 @@ func (s *OldV3ioSchema) merge(new *OldV3ioSchema) (bool, error) {
 	return changed, nil
 }
 
+// Merge merges newSchema into the schema without saving it, it returns true
+// if the schema changed
+func (s *OldV3ioSchema) Merge(newSchema V3ioSchema) (bool, error) {
+	changed, err := s.merge(newSchema.(*OldV3ioSchema))
+	if err != nil {
+		return changed, errors.Wrap(err, "failed to merge schema")
+	}
+
+	return changed, nil
+}
+
 // UpdateSchema updates the schema
 func (s *OldV3ioSchema) UpdateSchema(container v3io.Container, tablePath string, newSchema V3ioSchema) error {
 	changed, err := s.merge(newSchema.(*OldV3ioSchema))
@@ -189,18 +267,25 @@ func (s *OldV3ioSchema) UpdateSchema(container v3io.Container, tablePath string,
 	}
 
 	if changed {
-		body, err := s.toJSON()
-		if err != nil {
-			return errors.Wrap(err, "failed to marshal schema")
-		}
-		err = container.PutObjectSync(&v3io.PutObjectInput{Path: tablePath + ".#schema", Body: body})
-		if err != nil {
-			if strings.Contains(err.Error(), "status 401") {
-				return errors.New("unauthorized update (401), may be caused by wrong password or credentials")
-			}
+		return s.Save(container, tablePath)
+	}
+
+	return nil
+}
 
-			return errors.Wrap(err, "failed to update schema")
+// Save writes the schema to the table at tablePath
+func (s *OldV3ioSchema) Save(container v3io.Container, tablePath string) error {
+	body, err := s.toJSON()
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal schema")
+	}
+	err = container.PutObjectSync(&v3io.PutObjectInput{Path: tablePath + ".#schema", Body: body})
+	if err != nil {
+		if strings.Contains(err.Error(), "status 401") {
+			return errors.New("unauthorized update (401), may be caused by wrong password or credentials")
 		}
+
+		return errors.Wrap(err, "failed to update schema")
 	}
 
 	return nil
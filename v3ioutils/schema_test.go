@@ -23,6 +23,7 @@ package v3ioutils
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 const schemaTst = `
@@ -158,3 +159,53 @@ func TestMerge(t *testing.T) {
 		t.Fatal("merge with self should not cause any modifications to schema")
 	}
 }
+
+func TestMergeTimeFormat(t *testing.T) {
+	schema := OldV3ioSchema{Fields: []OldSchemaField{{Name: "t", Type: TimeType}}}
+	changed, err := schema.merge(&OldV3ioSchema{Fields: []OldSchemaField{
+		{Name: "t", Type: TimeType, TimeZone: "Asia/Jerusalem", Precision: "ms"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Fatal("setting the time format should change the schema")
+	}
+
+	// Writes without a time format keep the existing one
+	changed, err = schema.merge(&OldV3ioSchema{Fields: []OldSchemaField{{Name: "t", Type: TimeType}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed || schema.Fields[0].TimeZone != "Asia/Jerusalem" || schema.Fields[0].Precision != "ms" {
+		t.Fatalf("bad merge - %+v", schema.Fields[0])
+	}
+
+	_, err = schema.merge(&OldV3ioSchema{Fields: []OldSchemaField{{Name: "t", Type: TimeType, Precision: "s"}}})
+	if err == nil {
+		t.Fatal("no error on precision change")
+	}
+}
+
+func TestTruncateTime(t *testing.T) {
+	ts := time.Date(2021, 3, 14, 6, 59, 59, 123456789, time.UTC)
+	for precision, expected := range map[string]int{
+		"":   123456789,
+		"ns": 123456789,
+		"us": 123456000,
+		"ms": 123000000,
+		"s":  0,
+	} {
+		field := OldSchemaField{Name: "t", Type: TimeType, Precision: precision}
+		if ns := field.TruncateTime(ts).Nanosecond(); ns != expected {
+			t.Fatalf("%q: bad nanoseconds %d != %d", precision, ns, expected)
+		}
+	}
+
+	if err := ValidateTimeFormat("Europe/London", "us"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateTimeFormat("", "minutes"); err == nil {
+		t.Fatal("no error on bad precision")
+	}
+}